@@ -0,0 +1,60 @@
+package avl
+
+// Delete deletes a node with the given key.
+func (t *Tree) Delete(key Key) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if n := search(t.root, key); n != nil {
+		t.delete(n)
+	}
+}
+
+func (t *Tree) delete(z *node) {
+	// The lowest node whose subtree changed in height.
+	var lowest *node
+
+	switch {
+	case z.left == nil:
+		lowest = z.parent
+		t.transplant(z, z.right)
+	case z.right == nil:
+		lowest = z.parent
+		t.transplant(z, z.left)
+	default:
+		// y is the successor of z and has no left child.
+		y := min(z.right)
+
+		if y.parent == z {
+			lowest = y
+		} else {
+			lowest = y.parent
+
+			t.transplant(y, y.right)
+			y.right = z.right
+			y.right.parent = y
+		}
+
+		t.transplant(z, y)
+
+		y.left = z.left
+		y.left.parent = y
+	}
+
+	t.retrace(lowest)
+}
+
+func (t *Tree) transplant(u, v *node) {
+	switch {
+	case u.parent == nil:
+		t.root = v
+	case u == u.parent.left:
+		u.parent.left = v
+	default:
+		u.parent.right = v
+	}
+
+	if v != nil {
+		v.parent = u.parent
+	}
+}
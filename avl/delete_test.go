@@ -0,0 +1,101 @@
+package avl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_Delete(t *testing.T) {
+	t.Run("delete on empty tree is noop", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Delete(myInt(0))
+
+		assert.Equal(t, -1, tree.Height())
+	})
+
+	t.Run("delete of non-existing key is noop", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Upsert(myInt(15), "test")
+		tree.Delete(myInt(0))
+
+		assert.Equal(t, "test", tree.Search(myInt(15)))
+	})
+
+	t.Run("deleting root node leaves empty tree", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Upsert(myInt(15), "test")
+		tree.Delete(myInt(15))
+
+		assert.Equal(t, -1, tree.Height())
+		assert.Nil(t, tree.root)
+	})
+
+	t.Run("deleting node with two children replaces it with its successor", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		for _, k := range []myInt{10, 5, 15, 12, 20} {
+			tree.Upsert(k, nil)
+		}
+
+		tree.Delete(myInt(10))
+
+		assert.Equal(t, myInt(12), tree.root.key)
+		assert.Nil(t, tree.Search(myInt(10)))
+		verify(t, tree.root)
+	})
+
+	t.Run("deleting leaf rebalances tree", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		for _, k := range []myInt{10, 5, 15, 20} {
+			tree.Upsert(k, nil)
+		}
+
+		tree.Delete(myInt(5))
+
+		assert.Equal(t, myInt(15), tree.root.key)
+		assert.Equal(t, 1, tree.Height())
+		verify(t, tree.root)
+	})
+
+	t.Run("deleting leaf of left-right heavy tree rotates left, then right", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		for _, k := range []myInt{10, 5, 15, 7} {
+			tree.Upsert(k, nil)
+		}
+
+		tree.Delete(myInt(15))
+
+		assert.Equal(t, myInt(7), tree.root.key)
+		assert.Equal(t, myInt(5), tree.root.left.key)
+		assert.Equal(t, myInt(10), tree.root.right.key)
+		verify(t, tree.root)
+	})
+
+	t.Run("deleting from Fibonacci tree rebalances up to the root", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		// The sparsest AVL tree of height 4, in which every inner node is
+		// right heavy. Removing its lowest key shrinks the left subtree by
+		// rotating it, which unbalances the root in turn.
+		for _, k := range []myInt{5, 2, 8, 1, 3, 6, 10, 4, 7, 9, 11, 12} {
+			tree.Upsert(k, nil)
+		}
+
+		require.Equal(t, 4, tree.Height())
+		require.Equal(t, myInt(5), tree.root.key)
+
+		tree.Delete(myInt(1))
+
+		assert.Equal(t, myInt(8), tree.root.key)
+		assert.Equal(t, myInt(3), tree.root.left.left.key)
+		assert.Equal(t, 3, tree.Height())
+		verify(t, tree.root)
+	})
+}
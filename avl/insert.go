@@ -0,0 +1,42 @@
+package avl
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+func (t *Tree) Upsert(key Key, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if existing := search(t.root, key); existing != nil {
+		existing.payload = payload
+	} else {
+		t.insert(&node{key: key, payload: payload})
+	}
+}
+
+func (t *Tree) insert(z *node) {
+	var (
+		y *node
+		x = t.root
+	)
+
+	for x != nil {
+		y = x
+		if z.key.Less(x.key) {
+			x = x.left
+		} else {
+			x = x.right
+		}
+	}
+
+	z.parent = y
+
+	switch {
+	case y == nil:
+		t.root = z
+	case z.key.Less(y.key):
+		y.left = z
+	default:
+		y.right = z
+	}
+
+	t.retrace(y)
+}
@@ -0,0 +1,69 @@
+package avl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_Upsert(t *testing.T) {
+	t.Run("insert on empty tree creates root node", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Upsert(myInt(15), "test")
+
+		assert.Equal(t, 0, tree.Height())
+		assert.Equal(t, myInt(15), tree.root.key)
+		assert.Nil(t, tree.root.parent)
+		assert.Equal(t, "test", tree.Search(myInt(15)))
+	})
+
+	t.Run("upsert on existing key changes payload", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Upsert(myInt(15), "test")
+		tree.Upsert(myInt(15), "test2")
+
+		assert.Equal(t, 0, tree.Height())
+		assert.Equal(t, "test2", tree.Search(myInt(15)))
+	})
+
+	t.Run("left-left case rotates right", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Upsert(myInt(3), nil)
+		tree.Upsert(myInt(2), nil)
+		tree.Upsert(myInt(1), nil)
+
+		assert.Equal(t, myInt(2), tree.root.key)
+		assert.Equal(t, myInt(1), tree.root.left.key)
+		assert.Equal(t, myInt(3), tree.root.right.key)
+		verify(t, tree.root)
+	})
+
+	t.Run("left-right case rotates left, then right", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Upsert(myInt(3), nil)
+		tree.Upsert(myInt(1), nil)
+		tree.Upsert(myInt(2), nil)
+
+		assert.Equal(t, myInt(2), tree.root.key)
+		assert.Equal(t, myInt(1), tree.root.left.key)
+		assert.Equal(t, myInt(3), tree.root.right.key)
+		verify(t, tree.root)
+	})
+
+	t.Run("right-left case rotates right, then left", func(t *testing.T) {
+		tree := NewAVLTree()
+
+		tree.Upsert(myInt(1), nil)
+		tree.Upsert(myInt(3), nil)
+		tree.Upsert(myInt(2), nil)
+
+		assert.Equal(t, myInt(2), tree.root.key)
+		assert.Equal(t, myInt(1), tree.root.left.key)
+		assert.Equal(t, myInt(3), tree.root.right.key)
+		verify(t, tree.root)
+	})
+}
@@ -0,0 +1,11 @@
+package avl
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
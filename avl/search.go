@@ -0,0 +1,32 @@
+package avl
+
+// InOrder returns an ordered list of all entries.
+func (t *Tree) InOrder() []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	res := make([]Result, 0)
+
+	resultsInorder(t.root, &res)
+
+	return res
+}
+
+func resultsInorder(z *node, res *[]Result) {
+	if z == nil {
+		return
+	}
+
+	resultsInorder(z.left, res)
+
+	*res = append(*res, Result{
+		Key:     z.key,
+		Payload: z.payload,
+	})
+
+	resultsInorder(z.right, res)
+}
@@ -0,0 +1,261 @@
+// Package avl implements an AVL tree, which is a strictly height-balanced
+// binary search tree that runs in O(lg n) on all operations. Compared to a
+// red-black tree it is more rigidly balanced, making lookups faster at the
+// cost of more rotations on modifications.
+package avl
+
+// NewAVLTree returns a new AVL tree. Unless WithoutLocking is passed, all
+// operations on the tree are safe to be accessed concurrently.
+func NewAVLTree(opts ...Option) *Tree {
+	t := &Tree{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Root returns the payload of the root node of the tree.
+func (t *Tree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a single node has a height of zero.
+// Runs in O(1) time.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return height(t.root)
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *Tree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	n := min(t.root)
+
+	if n == nil {
+		return nil
+	}
+
+	return n.payload
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *Tree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	n := max(t.root)
+
+	if n == nil {
+		return nil
+	}
+
+	return n.payload
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *Tree) Search(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	n := search(t.root, key)
+
+	if n == nil {
+		return nil
+	}
+
+	return n.payload
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (t *Tree) Successor(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	n := successor(search(t.root, key))
+
+	if n == nil {
+		return nil
+	}
+
+	return n.payload
+}
+
+func height(z *node) int {
+	if z == nil {
+		return -1
+	}
+
+	return z.height
+}
+
+// balance returns the balance factor of z, which is positive if its left
+// subtree is higher.
+func balance(z *node) int {
+	return height(z.left) - height(z.right)
+}
+
+func updateHeight(z *node) {
+	z.height = 1 + maxInt(height(z.left), height(z.right))
+}
+
+func successor(z *node) *node {
+	if z == nil {
+		return nil
+	}
+
+	if z.right != nil {
+		return min(z.right)
+	}
+
+	parent := z.parent
+
+	for parent != nil && z == parent.right {
+		z = parent
+		parent = z.parent
+	}
+
+	return parent
+}
+
+func min(z *node) *node {
+	for z != nil && z.left != nil {
+		z = z.left
+	}
+
+	return z
+}
+
+func max(z *node) *node {
+	for z != nil && z.right != nil {
+		z = z.right
+	}
+
+	return z
+}
+
+func search(z *node, key Key) *node {
+	for z != nil && z.key != key {
+		if z.key.Less(key) {
+			z = z.right
+		} else {
+			z = z.left
+		}
+	}
+
+	return z
+}
+
+func (t *Tree) rotateLeft(x *node) *node {
+	// y's left subtree will be x's right subtree.
+	y := x.right
+	x.right = y.left
+
+	if y.left != nil {
+		y.left.parent = x
+	}
+
+	// Restore parent relationships.
+	y.parent = x.parent
+
+	switch {
+	case x.parent == nil:
+		t.root = y
+	case x.parent.left == x:
+		x.parent.left = y
+	default:
+		x.parent.right = y
+	}
+
+	// x will be y's new left-child.
+	y.left = x
+	x.parent = y
+
+	updateHeight(x)
+	updateHeight(y)
+
+	return y
+}
+
+func (t *Tree) rotateRight(x *node) *node {
+	y := x.left
+	x.left = y.right
+
+	if y.right != nil {
+		y.right.parent = x
+	}
+
+	y.parent = x.parent
+
+	switch {
+	case x.parent == nil:
+		t.root = y
+	case x.parent.left == x:
+		x.parent.left = y
+	default:
+		x.parent.right = y
+	}
+
+	y.right = x
+	x.parent = y
+
+	updateHeight(x)
+	updateHeight(y)
+
+	return y
+}
+
+// rebalance restores the AVL property at z, whose subtrees differ in height by
+// at most two, and returns the new root of the subtree.
+func (t *Tree) rebalance(z *node) *node {
+	updateHeight(z)
+
+	switch b := balance(z); {
+	case b > 1:
+		// Left-right case: reduce to the left-left case first.
+		if balance(z.left) < 0 {
+			t.rotateLeft(z.left)
+		}
+
+		return t.rotateRight(z)
+	case b < -1:
+		// Right-left case: reduce to the right-right case first.
+		if balance(z.right) > 0 {
+			t.rotateRight(z.right)
+		}
+
+		return t.rotateLeft(z)
+	default:
+		return z
+	}
+}
+
+// retrace walks up from z to the root, updating heights and rebalancing
+// along the way.
+func (t *Tree) retrace(z *node) {
+	for z != nil {
+		z = t.rebalance(z).parent
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,157 @@
+package avl
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the AVL property, heights, ordering and parent pointers of the
+// subtree rooted at z.
+func verify(t *testing.T, z *node) {
+	if z == nil {
+		return
+	}
+
+	if z.left != nil {
+		require.Equal(t, z, z.left.parent, "parent of %v", z.left.key)
+		require.True(t, z.left.key.Less(z.key), "%v not less than %v", z.left.key, z.key)
+	}
+
+	if z.right != nil {
+		require.Equal(t, z, z.right.parent, "parent of %v", z.right.key)
+		require.True(t, z.key.Less(z.right.key), "%v not less than %v", z.key, z.right.key)
+	}
+
+	require.Equal(t, 1+maxInt(height(z.left), height(z.right)), z.height, "height of %v", z.key)
+	require.LessOrEqual(t, abs(balance(z)), 1, "balance of %v", z.key)
+
+	verify(t, z.left)
+	verify(t, z.right)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+func TestTree_Root(t *testing.T) {
+	tree := NewAVLTree()
+
+	assert.Nil(t, tree.Root())
+
+	tree.Upsert(myInt(1), "1")
+	tree.Upsert(myInt(2), "2")
+	tree.Upsert(myInt(3), "3")
+
+	assert.Equal(t, "2", tree.Root())
+}
+
+func TestTree_Height(t *testing.T) {
+	tt := []struct {
+		name  string
+		items []myInt
+		want  int
+	}{
+		{name: "empty tree returns -1", want: -1},
+		{name: "rooted tree returns 0", items: []myInt{15}, want: 0},
+		{name: "ascending keys get balanced", items: []myInt{1, 2, 3, 4, 5, 6, 7}, want: 2},
+		{name: "descending keys get balanced", items: []myInt{7, 6, 5, 4, 3, 2, 1}, want: 2},
+		{name: "zig-zag keys get balanced", items: []myInt{10, 5, 7, 20, 15}, want: 2},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := NewAVLTree()
+
+			for _, k := range tc.items {
+				tree.Upsert(k, nil)
+			}
+
+			assert.Equal(t, tc.want, tree.Height())
+			verify(t, tree.root)
+		})
+	}
+}
+
+func TestTree_MinMax(t *testing.T) {
+	tree := NewAVLTree()
+
+	assert.Nil(t, tree.Min())
+	assert.Nil(t, tree.Max())
+
+	for _, k := range []myInt{15, 6, 18, 3, 7, 17, 20} {
+		tree.Upsert(k, int(k))
+	}
+
+	assert.Equal(t, 3, tree.Min())
+	assert.Equal(t, 20, tree.Max())
+}
+
+func TestTree_Successor(t *testing.T) {
+	tree := NewAVLTree()
+
+	assert.Nil(t, tree.Successor(myInt(1)))
+
+	for _, k := range []myInt{15, 6, 18, 3, 7, 17, 20, 2, 4, 13, 9} {
+		tree.Upsert(k, int(k))
+	}
+
+	assert.Equal(t, 6, tree.Successor(myInt(4)))
+	assert.Equal(t, 15, tree.Successor(myInt(13)))
+	assert.Equal(t, 17, tree.Successor(myInt(15)))
+	assert.Nil(t, tree.Successor(myInt(20)))
+	assert.Nil(t, tree.Successor(myInt(5)))
+}
+
+func TestTree_random(t *testing.T) {
+	var (
+		tree = NewAVLTree()
+		want = make(map[myInt]int)
+		rng  = rand.New(rand.NewSource(1))
+	)
+
+	for i := 0; i < 5000; i++ {
+		k := myInt(rng.Intn(500))
+
+		if rng.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(want, k)
+		} else {
+			tree.Upsert(k, i)
+			want[k] = i
+		}
+
+		if i%100 == 0 {
+			verify(t, tree.root)
+		}
+	}
+
+	verify(t, tree.root)
+
+	require.Len(t, tree.InOrder(), len(want))
+
+	// An AVL tree is never higher than ~1.44 lg(n+2).
+	assert.LessOrEqual(t, float64(tree.Height()), 1.44*math.Log2(float64(len(want)+2)))
+}
+
+func TestNewAVLTree_WithoutLocking(t *testing.T) {
+	tree := NewAVLTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+
+	tree.Upsert(myInt(1), "1")
+	assert.Equal(t, "1", tree.Search(myInt(1)))
+}
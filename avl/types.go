@@ -0,0 +1,29 @@
+package avl
+
+import (
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the tree have to implement. It is shared with
+// package redblack, so the same key types work with both trees.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the tree.
+type Result = redblack.Result
+
+// Tree represents an AVL tree with a root node and a lock to protect
+// concurrent access.
+type Tree struct {
+	lock lock.RWMutex
+	root *node
+}
+
+type node struct {
+	key     Key
+	height  int
+	left    *node
+	right   *node
+	parent  *node
+	payload interface{}
+}
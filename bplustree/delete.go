@@ -0,0 +1,151 @@
+package bplustree
+
+// Delete deletes the given key.
+func (t *Tree) Delete(key Key) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.root == nil {
+		return
+	}
+
+	if t.delete(t.root, key) {
+		t.size--
+	}
+
+	// Merging the last two children of the root is the only way the tree
+	// shrinks in height.
+	switch {
+	case t.root.leaf() && len(t.root.keys) == 0:
+		t.root = nil
+	case !t.root.leaf() && len(t.root.keys) == 0:
+		t.root = t.root.children[0]
+	}
+}
+
+// delete removes key from the subtree rooted at n and rebalances underflowing
+// children on the way back up. Separator keys of deleted keys are left in
+// place, as they still divide the subtrees correctly. Returns true if the key
+// was found.
+func (t *Tree) delete(n *node, key Key) bool {
+	if n.leaf() {
+		i, found := n.find(key)
+
+		if found {
+			n.keys = removeKey(n.keys, i)
+			n.payloads = removePayload(n.payloads, i)
+		}
+
+		return found
+	}
+
+	i := n.childIndex(key)
+
+	if !t.delete(n.children[i], key) {
+		return false
+	}
+
+	if len(n.children[i].keys) < t.minKeys() {
+		t.rebalance(n, i)
+	}
+
+	return true
+}
+
+// rebalance refills the underflowing i-th child of n by borrowing a key from a
+// sibling, or merges it with a sibling if both are at the minimum.
+func (t *Tree) rebalance(n *node, i int) {
+	switch {
+	case i > 0 && len(n.children[i-1].keys) > t.minKeys():
+		t.borrowLeft(n, i)
+	case i+1 < len(n.children) && len(n.children[i+1].keys) > t.minKeys():
+		t.borrowRight(n, i)
+	case i > 0:
+		t.merge(n, i-1)
+	default:
+		t.merge(n, i)
+	}
+}
+
+// borrowLeft moves the highest key of the left sibling of the i-th child of n
+// into that child.
+func (t *Tree) borrowLeft(n *node, i int) {
+	var (
+		child = n.children[i]
+		left  = n.children[i-1]
+		last  = len(left.keys) - 1
+	)
+
+	if child.leaf() {
+		child.keys = insertKey(child.keys, 0, left.keys[last])
+		child.payloads = insertPayload(child.payloads, 0, left.payloads[last])
+
+		left.keys = truncateKeys(left.keys, last)
+		left.payloads = truncatePayloads(left.payloads, last)
+
+		n.keys[i-1] = child.keys[0]
+
+		return
+	}
+
+	// The separator moves down into child, the highest key of left takes its
+	// place.
+	child.keys = insertKey(child.keys, 0, n.keys[i-1])
+	child.children = insertChild(child.children, 0, left.children[last+1])
+
+	n.keys[i-1] = left.keys[last]
+
+	left.keys = truncateKeys(left.keys, last)
+	left.children = truncateChildren(left.children, last+1)
+}
+
+// borrowRight moves the lowest key of the right sibling of the i-th child of n
+// into that child.
+func (t *Tree) borrowRight(n *node, i int) {
+	var (
+		child = n.children[i]
+		right = n.children[i+1]
+	)
+
+	if child.leaf() {
+		child.keys = append(child.keys, right.keys[0])
+		child.payloads = append(child.payloads, right.payloads[0])
+
+		right.keys = removeKey(right.keys, 0)
+		right.payloads = removePayload(right.payloads, 0)
+
+		n.keys[i] = right.keys[0]
+
+		return
+	}
+
+	child.keys = append(child.keys, n.keys[i])
+	child.children = append(child.children, right.children[0])
+
+	n.keys[i] = right.keys[0]
+
+	right.keys = removeKey(right.keys, 0)
+	right.children = removeChild(right.children, 0)
+}
+
+// merge merges the (i+1)-th child of n into the i-th child and removes the
+// separator between them from n.
+func (t *Tree) merge(n *node, i int) {
+	var (
+		left  = n.children[i]
+		right = n.children[i+1]
+	)
+
+	if left.leaf() {
+		left.keys = append(left.keys, right.keys...)
+		left.payloads = append(left.payloads, right.payloads...)
+		left.next = right.next
+	} else {
+		left.keys = append(left.keys, n.keys[i])
+		left.keys = append(left.keys, right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+
+	n.keys = removeKey(n.keys, i)
+	n.children = removeChild(n.children, i+1)
+}
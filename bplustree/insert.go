@@ -0,0 +1,187 @@
+package bplustree
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+func (t *Tree) Upsert(key Key, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.root == nil {
+		t.root = t.newLeaf()
+	}
+
+	added, sep, right := t.insert(t.root, key, payload)
+
+	if added {
+		t.size++
+	}
+
+	// Splitting the root is the only way the tree grows in height.
+	if right != nil {
+		root := t.newInner()
+		root.keys = append(root.keys, sep)
+		root.children = append(root.children, t.root, right)
+
+		t.root = root
+	}
+}
+
+// insert inserts or updates key in the subtree rooted at n. Returns true if a
+// new key was inserted. If n overflowed, it's split and the new right sibling
+// is returned along with the key separating it from n.
+func (t *Tree) insert(n *node, key Key, payload interface{}) (bool, Key, *node) {
+	if n.leaf() {
+		i, found := n.find(key)
+
+		if found {
+			n.payloads[i] = payload
+			return false, nil, nil
+		}
+
+		n.keys = insertKey(n.keys, i, key)
+		n.payloads = insertPayload(n.payloads, i, payload)
+
+		if len(n.keys) <= t.order {
+			return true, nil, nil
+		}
+
+		right := t.splitLeaf(n)
+
+		return true, right.keys[0], right
+	}
+
+	i := n.childIndex(key)
+
+	added, sep, right := t.insert(n.children[i], key, payload)
+	if right == nil {
+		return added, nil, nil
+	}
+
+	n.keys = insertKey(n.keys, i, sep)
+	n.children = insertChild(n.children, i+1, right)
+
+	if len(n.keys) <= t.order {
+		return added, nil, nil
+	}
+
+	sep, right = t.splitInner(n)
+
+	return added, sep, right
+}
+
+// splitLeaf moves the upper half of the overflowing leaf n into a new leaf,
+// which is linked after n and returned.
+func (t *Tree) splitLeaf(n *node) *node {
+	var (
+		mid   = len(n.keys) / 2
+		right = t.newLeaf()
+	)
+
+	right.keys = append(right.keys, n.keys[mid:]...)
+	right.payloads = append(right.payloads, n.payloads[mid:]...)
+	right.next = n.next
+
+	n.keys = truncateKeys(n.keys, mid)
+	n.payloads = truncatePayloads(n.payloads, mid)
+	n.next = right
+
+	return right
+}
+
+// splitInner moves the keys and children above the median of the overflowing
+// inner node n into a new node. Returns the median, which moves up into the
+// parent, and the new node.
+func (t *Tree) splitInner(n *node) (Key, *node) {
+	var (
+		mid   = len(n.keys) / 2
+		sep   = n.keys[mid]
+		right = t.newInner()
+	)
+
+	right.keys = append(right.keys, n.keys[mid+1:]...)
+	right.children = append(right.children, n.children[mid+1:]...)
+
+	n.keys = truncateKeys(n.keys, mid)
+	n.children = truncateChildren(n.children, mid+1)
+
+	return sep, right
+}
+
+func (t *Tree) newLeaf() *node {
+	return &node{
+		keys:     make([]Key, 0, t.order+1),
+		payloads: make([]interface{}, 0, t.order+1),
+	}
+}
+
+func (t *Tree) newInner() *node {
+	return &node{
+		keys:     make([]Key, 0, t.order+1),
+		children: make([]*node, 0, t.order+2),
+	}
+}
+
+// The following helpers insert into and remove from the slices of a node.
+// Removed elements are cleared so they can be garbage collected.
+
+func insertKey(s []Key, i int, k Key) []Key {
+	s = append(s, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = k
+
+	return s
+}
+
+func insertPayload(s []interface{}, i int, p interface{}) []interface{} {
+	s = append(s, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = p
+
+	return s
+}
+
+func insertChild(s []*node, i int, c *node) []*node {
+	s = append(s, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = c
+
+	return s
+}
+
+func removeKey(s []Key, i int) []Key {
+	copy(s[i:], s[i+1:])
+	return truncateKeys(s, len(s)-1)
+}
+
+func removePayload(s []interface{}, i int) []interface{} {
+	copy(s[i:], s[i+1:])
+	return truncatePayloads(s, len(s)-1)
+}
+
+func removeChild(s []*node, i int) []*node {
+	copy(s[i:], s[i+1:])
+	return truncateChildren(s, len(s)-1)
+}
+
+func truncateKeys(s []Key, n int) []Key {
+	for i := n; i < len(s); i++ {
+		s[i] = nil
+	}
+
+	return s[:n]
+}
+
+func truncatePayloads(s []interface{}, n int) []interface{} {
+	for i := n; i < len(s); i++ {
+		s[i] = nil
+	}
+
+	return s[:n]
+}
+
+func truncateChildren(s []*node, n int) []*node {
+	for i := n; i < len(s); i++ {
+		s[i] = nil
+	}
+
+	return s[:n]
+}
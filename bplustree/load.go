@@ -0,0 +1,93 @@
+package bplustree
+
+// Load replaces the contents of the tree with the passed entries, which have
+// to be sorted by strictly ascending keys. Building the tree bottom-up from
+// sorted input takes O(n) time and fills the leaves completely. Returns
+// ErrNotSorted and leaves the tree untouched if the keys aren't ascending.
+func (t *Tree) Load(entries []Result) error {
+	for i := 1; i < len(entries); i++ {
+		if !entries[i-1].Key.Less(entries[i].Key) {
+			return ErrNotSorted
+		}
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.size = len(entries)
+	t.root = nil
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var (
+		sizes  = spread(len(entries), t.order)
+		level  = make([]*node, len(sizes))
+		lowest = make([]Key, len(sizes))
+		prev   *node
+	)
+
+	for i, size := range sizes {
+		l := t.newLeaf()
+
+		for _, e := range entries[:size] {
+			l.keys = append(l.keys, e.Key)
+			l.payloads = append(l.payloads, e.Payload)
+		}
+
+		entries = entries[size:]
+
+		if prev != nil {
+			prev.next = l
+		}
+
+		level[i], lowest[i], prev = l, l.keys[0], l
+	}
+
+	// Group the nodes of each level under parents until a single root is
+	// left. The lowest key of every child but the first becomes a separator.
+	for len(level) > 1 {
+		var (
+			sizes   = spread(len(level), t.order+1)
+			parents = make([]*node, len(sizes))
+			keys    = make([]Key, len(sizes))
+		)
+
+		for i, size := range sizes {
+			p := t.newInner()
+
+			p.children = append(p.children, level[:size]...)
+			p.keys = append(p.keys, lowest[1:size]...)
+
+			parents[i], keys[i] = p, lowest[0]
+			level, lowest = level[size:], lowest[size:]
+		}
+
+		level, lowest = parents, keys
+	}
+
+	t.root = level[0]
+
+	return nil
+}
+
+// spread splits n elements into as few groups of at most max elements as
+// possible, with sizes differing by at most one. This keeps the last group
+// from underflowing.
+func spread(n, max int) []int {
+	var (
+		groups = (n + max - 1) / max
+		sizes  = make([]int, groups)
+	)
+
+	for i := range sizes {
+		sizes[i] = n / groups
+
+		if i < n%groups {
+			sizes[i]++
+		}
+	}
+
+	return sizes
+}
@@ -0,0 +1,24 @@
+package bplustree
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithOrder sets the maximum number of keys per node m: every node but the
+// root holds at least m/2 keys. Higher orders result in flatter trees and
+// longer sequential scans within a leaf. Panics if m is less than 3.
+func WithOrder(m int) Option {
+	if m < 3 {
+		panic("bplustree: order must be at least 3")
+	}
+
+	return func(t *Tree) {
+		t.order = m
+	}
+}
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
@@ -0,0 +1,58 @@
+package bplustree
+
+// InOrder returns an ordered list of all entries.
+func (t *Tree) InOrder() []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	res := make([]Result, 0, t.size)
+
+	for l := t.root.first(); l != nil; l = l.next {
+		for i := range l.keys {
+			res = append(res, l.result(i))
+		}
+	}
+
+	return res
+}
+
+// Range calls fn in ascending order for every key in the half-open range
+// [from, to). A nil bound leaves that side of the range open. Iteration stops
+// early if fn returns false. The tree must not be modified from within fn.
+//
+// After locating the first key, Range scans the linked leaves sequentially
+// without revisiting inner nodes.
+func (t *Tree) Range(from, to Key, fn func(key Key, payload interface{}) bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return
+	}
+
+	var (
+		l = t.root.first()
+		i int
+	)
+
+	if from != nil {
+		l = t.root.leafFor(from)
+		i, _ = l.find(from)
+	}
+
+	for ; l != nil; l, i = l.next, 0 {
+		for ; i < len(l.keys); i++ {
+			if to != nil && !l.keys[i].Less(to) {
+				return
+			}
+
+			if !fn(l.keys[i], l.payloads[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Package bplustree implements an in-memory B+ tree. Payloads are only stored
+// in the leaves, which are linked in ascending order, so ordered iteration and
+// range queries are sequential scans. All point operations run in O(lg n)
+// time.
+package bplustree
+
+import (
+	"sort"
+)
+
+// NewBPlusTree returns a new B+ tree. Unless WithoutLocking is passed, all
+// operations on the tree are safe to be accessed concurrently.
+func NewBPlusTree(opts ...Option) *Tree {
+	t := &Tree{
+		order: DefaultOrder,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Root returns the payload of the key the root node splits the tree on, which
+// is the median key if the root is a leaf.
+func (t *Tree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	if t.root.leaf() {
+		return t.root.payloads[len(t.root.keys)/2]
+	}
+
+	return t.root.children[len(t.root.children)/2].first().payloads[0]
+}
+
+// Height returns the height (max depth) of the tree, counted in nodes rather
+// than keys. Returns -1 if the tree has no nodes. A tree with only a root leaf
+// has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return -1
+	}
+
+	// All leaves of a B+ tree have the same depth.
+	h := 0
+
+	for n := t.root; !n.leaf(); n = n.children[0] {
+		h++
+	}
+
+	return h
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *Tree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.first().payloads[0]
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *Tree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	l := t.root.last()
+
+	return l.payloads[len(l.payloads)-1]
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *Tree) Search(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	l := t.root.leafFor(key)
+
+	if i, found := l.find(key); found {
+		return l.payloads[i]
+	}
+
+	return nil
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (t *Tree) Successor(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	l := t.root.leafFor(key)
+
+	i, found := l.find(key)
+	if !found {
+		return nil
+	}
+
+	if i+1 < len(l.keys) {
+		return l.payloads[i+1]
+	}
+
+	if l.next != nil {
+		return l.next.payloads[0]
+	}
+
+	return nil
+}
+
+// Floor returns the entry with the highest key less than or equal to the
+// passed key. Returns false if there is none.
+func (t *Tree) Floor(key Key) (Result, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return Result{}, false
+	}
+
+	// The subtree left of the path to key, holding the highest key below the
+	// leaf key belongs to.
+	var left *node
+
+	n := t.root
+
+	for !n.leaf() {
+		i := n.childIndex(key)
+
+		if i > 0 {
+			left = n.children[i-1]
+		}
+
+		n = n.children[i]
+	}
+
+	i, found := n.find(key)
+
+	switch {
+	case found:
+		return n.result(i), true
+	case i > 0:
+		return n.result(i - 1), true
+	case left != nil:
+		l := left.last()
+		return l.result(len(l.keys) - 1), true
+	}
+
+	return Result{}, false
+}
+
+// Ceiling returns the entry with the lowest key greater than or equal to the
+// passed key. Returns false if there is none.
+func (t *Tree) Ceiling(key Key) (Result, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return Result{}, false
+	}
+
+	l := t.root.leafFor(key)
+
+	i, _ := l.find(key)
+
+	switch {
+	case i < len(l.keys):
+		return l.result(i), true
+	case l.next != nil:
+		return l.next.result(0), true
+	}
+
+	return Result{}, false
+}
+
+func (t *Tree) minKeys() int {
+	return t.order / 2
+}
+
+func (n *node) leaf() bool {
+	return len(n.children) == 0
+}
+
+// find returns the index of the first key of the leaf n which is not less than
+// key, and whether that key equals key.
+func (n *node) find(key Key) (int, bool) {
+	i := sort.Search(len(n.keys), func(i int) bool {
+		return !n.keys[i].Less(key)
+	})
+
+	return i, i < len(n.keys) && !key.Less(n.keys[i])
+}
+
+// childIndex returns the index of the child of the inner node n whose subtree
+// covers key.
+func (n *node) childIndex(key Key) int {
+	return sort.Search(len(n.keys), func(i int) bool {
+		return key.Less(n.keys[i])
+	})
+}
+
+// leafFor returns the leaf of the subtree rooted at n which key belongs to.
+func (n *node) leafFor(key Key) *node {
+	for !n.leaf() {
+		n = n.children[n.childIndex(key)]
+	}
+
+	return n
+}
+
+func (n *node) first() *node {
+	for !n.leaf() {
+		n = n.children[0]
+	}
+
+	return n
+}
+
+func (n *node) last() *node {
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+
+	return n
+}
+
+func (n *node) result(i int) Result {
+	return Result{
+		Key:     n.keys[i],
+		Payload: n.payloads[i],
+	}
+}
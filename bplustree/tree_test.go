@@ -0,0 +1,511 @@
+package bplustree
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the B+ tree invariants, including that the linked leaves hold
+// all keys in ascending order.
+func verify(t *testing.T, tree *Tree) {
+	if tree.root == nil {
+		require.Equal(t, 0, tree.size)
+		return
+	}
+
+	var (
+		leafDepth = -1
+		leaves    []*node
+		walk      func(n *node, depth int, lo, hi Key)
+	)
+
+	walk = func(n *node, depth int, lo, hi Key) {
+		if n != tree.root {
+			require.GreaterOrEqual(t, len(n.keys), tree.minKeys(), "underflow")
+		}
+
+		require.LessOrEqual(t, len(n.keys), tree.order, "overflow")
+
+		for i, k := range n.keys {
+			if i > 0 {
+				require.True(t, n.keys[i-1].Less(k), "keys not sorted")
+			}
+
+			if lo != nil {
+				require.False(t, k.Less(lo), "%v less than %v", k, lo)
+			}
+
+			if hi != nil {
+				require.True(t, k.Less(hi), "%v not less than %v", k, hi)
+			}
+		}
+
+		if n.leaf() {
+			require.NotEmpty(t, n.keys)
+			require.Len(t, n.payloads, len(n.keys))
+
+			if leafDepth == -1 {
+				leafDepth = depth
+			}
+
+			require.Equal(t, leafDepth, depth, "leaves at different depths")
+
+			leaves = append(leaves, n)
+
+			return
+		}
+
+		require.Nil(t, n.payloads)
+		require.Len(t, n.children, len(n.keys)+1)
+
+		for i, c := range n.children {
+			var l, h = lo, hi
+
+			if i > 0 {
+				l = n.keys[i-1]
+			}
+
+			if i < len(n.keys) {
+				h = n.keys[i]
+			}
+
+			walk(c, depth+1, l, h)
+		}
+	}
+
+	walk(tree.root, 0, nil, nil)
+
+	var count int
+
+	for i, l := range leaves {
+		count += len(l.keys)
+
+		if i+1 < len(leaves) {
+			require.Same(t, leaves[i+1], l.next, "leaves not linked")
+			require.True(t, l.keys[len(l.keys)-1].Less(leaves[i+1].keys[0]))
+		} else {
+			require.Nil(t, l.next)
+		}
+	}
+
+	require.Equal(t, tree.size, count)
+}
+
+func leaf(keys ...int) *node {
+	n := &node{payloads: []interface{}{}}
+
+	for _, k := range keys {
+		n.keys = append(n.keys, myInt(k))
+		n.payloads = append(n.payloads, k)
+	}
+
+	return n
+}
+
+func branch(keys []int, children ...*node) *node {
+	n := &node{children: children}
+
+	for _, k := range keys {
+		n.keys = append(n.keys, myInt(k))
+	}
+
+	return n
+}
+
+// newTree returns a tree of order 4 with the given root, linking its leaves.
+func newTree(root *node) *Tree {
+	var (
+		tree = NewBPlusTree(WithOrder(4))
+		prev *node
+		link func(n *node)
+	)
+
+	link = func(n *node) {
+		if n.leaf() {
+			if prev != nil {
+				prev.next = n
+			}
+
+			prev = n
+			tree.size += len(n.keys)
+
+			return
+		}
+
+		for _, c := range n.children {
+			link(c)
+		}
+	}
+
+	link(root)
+	tree.root = root
+
+	return tree
+}
+
+// shape renders the subtree rooted at n, with leaves in brackets and inner
+// nodes in parentheses, e.g. "([1 2] 3 [3 4])".
+func shape(n *node) string {
+	if n == nil {
+		return "[]"
+	}
+
+	var parts []string
+
+	if n.leaf() {
+		for _, k := range n.keys {
+			parts = append(parts, fmt.Sprint(k))
+		}
+
+		return "[" + strings.Join(parts, " ") + "]"
+	}
+
+	for i, c := range n.children {
+		if i > 0 {
+			parts = append(parts, fmt.Sprint(n.keys[i-1]))
+		}
+
+		parts = append(parts, shape(c))
+	}
+
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func TestTree_Upsert(t *testing.T) {
+	t.Run("overflowing root leaf gets split", func(t *testing.T) {
+		tree := NewBPlusTree(WithOrder(3))
+
+		for i := 1; i <= 3; i++ {
+			tree.Upsert(myInt(i), i)
+		}
+
+		assert.Equal(t, 0, tree.Height())
+
+		tree.Upsert(myInt(4), 4)
+
+		assert.Equal(t, 1, tree.Height())
+		assert.Equal(t, []Key{myInt(3)}, tree.root.keys)
+		assert.Equal(t, 3, tree.Root())
+		verify(t, tree)
+	})
+
+	t.Run("leaf split copies separator up, inner split moves it up", func(t *testing.T) {
+		tree := newTree(branch([]int{3, 5, 7, 9},
+			leaf(1, 2), leaf(3, 4), leaf(5, 6), leaf(7, 8), leaf(9, 10, 11, 12),
+		))
+
+		tree.Upsert(myInt(13), 13)
+
+		assert.Equal(t, "(([1 2] 3 [3 4] 5 [5 6]) 7 ([7 8] 9 [9 10] 11 [11 12 13]))", shape(tree.root))
+		verify(t, tree)
+	})
+}
+
+func TestTree_Delete(t *testing.T) {
+	// Nodes of order 4 hold two to four keys, so each of these trees is left
+	// with an underflowing node by the deletion.
+	tt := []struct {
+		name string
+		root *node
+		key  int
+		want string
+	}{
+		{
+			name: "leaf borrows from left sibling",
+			root: branch([]int{5}, leaf(1, 2, 3), leaf(5, 6)),
+			key:  6,
+			want: "([1 2] 3 [3 5])",
+		},
+		{
+			name: "leaf borrows from right sibling",
+			root: branch([]int{3}, leaf(1, 2), leaf(3, 4, 5)),
+			key:  1,
+			want: "([2 3] 4 [4 5])",
+		},
+		{
+			name: "leaf merges with left sibling",
+			root: branch([]int{3, 5}, leaf(1, 2), leaf(3, 4), leaf(5, 6)),
+			key:  4,
+			want: "([1 2 3] 5 [5 6])",
+		},
+		{
+			name: "first leaf merges with right sibling",
+			root: branch([]int{3, 5}, leaf(1, 2), leaf(3, 4), leaf(5, 6)),
+			key:  1,
+			want: "([2 3 4] 5 [5 6])",
+		},
+		{
+			name: "separator of deleted key is kept",
+			root: branch([]int{3}, leaf(1, 2), leaf(3, 4, 5)),
+			key:  3,
+			want: "([1 2] 3 [4 5])",
+		},
+		{
+			name: "merging the last children of the root shrinks the tree",
+			root: branch([]int{3}, leaf(1, 2), leaf(3, 4)),
+			key:  4,
+			want: "[1 2 3]",
+		},
+		{
+			name: "inner node borrows from right sibling",
+			root: branch([]int{7},
+				branch([]int{3, 5}, leaf(1, 2), leaf(3, 4), leaf(5, 6)),
+				branch([]int{9, 11, 13}, leaf(7, 8), leaf(9, 10), leaf(11, 12), leaf(13, 14)),
+			),
+			key:  1,
+			want: "(([2 3 4] 5 [5 6] 7 [7 8]) 9 ([9 10] 11 [11 12] 13 [13 14]))",
+		},
+		{
+			name: "inner node borrows from left sibling",
+			root: branch([]int{9},
+				branch([]int{3, 5, 7}, leaf(1, 2), leaf(3, 4), leaf(5, 6), leaf(7, 8)),
+				branch([]int{11, 13}, leaf(9, 10), leaf(11, 12), leaf(13, 14)),
+			),
+			key:  14,
+			want: "(([1 2] 3 [3 4] 5 [5 6]) 7 ([7 8] 9 [9 10] 11 [11 12 13]))",
+		},
+		{
+			name: "missing key leaves tree unchanged",
+			root: branch([]int{3}, leaf(1, 2), leaf(3, 4)),
+			key:  5,
+			want: "([1 2] 3 [3 4])",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTree(tc.root)
+
+			tree.Delete(myInt(tc.key))
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			assert.Nil(t, tree.Search(myInt(tc.key)))
+			verify(t, tree)
+		})
+	}
+}
+
+func TestTree_random(t *testing.T) {
+	for _, order := range []int{3, 4, 5, 64} {
+		var (
+			tree = NewBPlusTree(WithOrder(order))
+			want = make(map[myInt]bool)
+			rng  = rand.New(rand.NewSource(int64(order)))
+		)
+
+		for i := 0; i < 5000; i++ {
+			k := myInt(rng.Intn(1000))
+
+			if rng.Intn(3) == 0 {
+				tree.Delete(k)
+				delete(want, k)
+			} else {
+				tree.Upsert(k, i)
+				want[k] = true
+			}
+
+			if i%250 == 0 {
+				verify(t, tree)
+			}
+		}
+
+		verify(t, tree)
+		require.Equal(t, len(want), tree.Len(), "order %d", order)
+	}
+}
+
+func TestTree_FloorCeiling(t *testing.T) {
+	tree := NewBPlusTree(WithOrder(3))
+
+	_, ok := tree.Floor(myInt(1))
+	assert.False(t, ok)
+
+	_, ok = tree.Ceiling(myInt(1))
+	assert.False(t, ok)
+
+	for i := 10; i < 100; i += 10 {
+		tree.Upsert(myInt(i), i)
+	}
+
+	// Separators of deleted keys stay in the inner nodes.
+	tree.Delete(myInt(50))
+
+	tt := []struct {
+		key     myInt
+		floor   interface{}
+		ceiling interface{}
+	}{
+		{key: 5, floor: nil, ceiling: 10},
+		{key: 10, floor: 10, ceiling: 10},
+		{key: 15, floor: 10, ceiling: 20},
+		{key: 50, floor: 40, ceiling: 60},
+		{key: 55, floor: 40, ceiling: 60},
+		{key: 60, floor: 60, ceiling: 60},
+		{key: 61, floor: 60, ceiling: 70},
+		{key: 90, floor: 90, ceiling: 90},
+		{key: 95, floor: 90, ceiling: nil},
+	}
+
+	for _, tc := range tt {
+		r, ok := tree.Floor(tc.key)
+		assert.Equal(t, tc.floor != nil, ok, "floor of %d", tc.key)
+
+		if ok {
+			assert.Equal(t, tc.floor, r.Payload, "floor of %d", tc.key)
+			assert.Equal(t, myInt(tc.floor.(int)), r.Key)
+		}
+
+		r, ok = tree.Ceiling(tc.key)
+		assert.Equal(t, tc.ceiling != nil, ok, "ceiling of %d", tc.key)
+
+		if ok {
+			assert.Equal(t, tc.ceiling, r.Payload, "ceiling of %d", tc.key)
+		}
+	}
+
+	t.Run("random", func(t *testing.T) {
+		tree := NewBPlusTree(WithOrder(4))
+		rng := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 500; i++ {
+			tree.Upsert(myInt(rng.Intn(2000)), nil)
+		}
+
+		keys := tree.InOrder()
+
+		for k := -1; k <= 2001; k++ {
+			// Index of the first key not less than k.
+			i := 0
+			for i < len(keys) && keys[i].Key.Less(myInt(k)) {
+				i++
+			}
+
+			r, ok := tree.Ceiling(myInt(k))
+			require.Equal(t, i < len(keys), ok)
+
+			if ok {
+				require.Equal(t, keys[i].Key, r.Key)
+			}
+
+			if i < len(keys) && keys[i].Key == myInt(k) {
+				i++
+			}
+
+			r, ok = tree.Floor(myInt(k))
+			require.Equal(t, i > 0, ok)
+
+			if ok {
+				require.Equal(t, keys[i-1].Key, r.Key)
+			}
+		}
+	})
+}
+
+func TestTree_Range(t *testing.T) {
+	tree := NewBPlusTree(WithOrder(3))
+
+	for i := 0; i < 50; i += 2 {
+		tree.Upsert(myInt(i), i)
+	}
+
+	collect := func(from, to Key, limit int) []int {
+		var res []int
+
+		tree.Range(from, to, func(key Key, payload interface{}) bool {
+			res = append(res, payload.(int))
+			return len(res) < limit
+		})
+
+		return res
+	}
+
+	tt := []struct {
+		name  string
+		from  Key
+		to    Key
+		limit int
+		want  []int
+	}{
+		{name: "bounded range", from: myInt(10), to: myInt(20), limit: 100, want: []int{10, 12, 14, 16, 18}},
+		{name: "bounds between keys", from: myInt(9), to: myInt(15), limit: 100, want: []int{10, 12, 14}},
+		{name: "open lower bound", to: myInt(5), limit: 100, want: []int{0, 2, 4}},
+		{name: "open upper bound", from: myInt(44), limit: 100, want: []int{44, 46, 48}},
+		{name: "stops early", from: myInt(10), limit: 2, want: []int{10, 12}},
+		{name: "empty range", from: myInt(11), to: myInt(12), limit: 100},
+		{name: "from beyond highest key", from: myInt(49), limit: 100},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, collect(tc.from, tc.to, tc.limit))
+		})
+	}
+
+	t.Run("full range equals InOrder", func(t *testing.T) {
+		all := collect(nil, nil, 100)
+		assert.Len(t, all, tree.Len())
+	})
+}
+
+func TestTree_Load(t *testing.T) {
+	entries := func(n int) []Result {
+		res := make([]Result, n)
+		for i := range res {
+			res[i] = Result{Key: myInt(i), Payload: i}
+		}
+
+		return res
+	}
+
+	for _, order := range []int{3, 4, 7} {
+		for n := 0; n <= 200; n++ {
+			tree := NewBPlusTree(WithOrder(order))
+			tree.Upsert(myInt(-1), "replaced")
+
+			require.NoError(t, tree.Load(entries(n)))
+			verify(t, tree)
+
+			assert.Equal(t, entries(n), append([]Result{}, tree.InOrder()...), "order %d, %d entries", order, n)
+			assert.Nil(t, tree.Search(myInt(-1)))
+		}
+	}
+
+	t.Run("loaded tree can be modified", func(t *testing.T) {
+		tree := NewBPlusTree(WithOrder(3))
+		require.NoError(t, tree.Load(entries(100)))
+
+		for i := 0; i < 100; i += 3 {
+			tree.Delete(myInt(i))
+			tree.Upsert(myInt(i+1000), i)
+		}
+
+		verify(t, tree)
+	})
+
+	t.Run("unsorted keys are rejected", func(t *testing.T) {
+		tree := NewBPlusTree()
+		tree.Upsert(myInt(1), 1)
+
+		err := tree.Load([]Result{{Key: myInt(2)}, {Key: myInt(2)}})
+
+		assert.Equal(t, ErrNotSorted, err)
+		assert.Equal(t, 1, tree.Search(myInt(1)))
+	})
+}
+
+func TestWithOrder(t *testing.T) {
+	assert.Panics(t, func() { WithOrder(2) })
+	assert.Equal(t, DefaultOrder, NewBPlusTree().order)
+	assert.Equal(t, 5, NewBPlusTree(WithOrder(5)).order)
+}
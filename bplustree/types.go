@@ -0,0 +1,43 @@
+package bplustree
+
+import (
+	"errors"
+
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the tree have to implement. It is shared with
+// package redblack, so the same key types work with both trees.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the tree.
+type Result = redblack.Result
+
+// DefaultOrder is the maximum number of keys per node unless WithOrder is
+// passed.
+const DefaultOrder = 64
+
+// ErrNotSorted is returned by Load if the passed keys aren't strictly
+// ascending.
+var ErrNotSorted = errors.New("bplustree: keys are not in ascending order")
+
+// Tree represents a B+ tree with a root node and a lock to protect concurrent
+// access.
+type Tree struct {
+	lock  lock.RWMutex
+	root  *node
+	order int
+	size  int
+}
+
+// node holds between order/2 and order keys, the root may hold less. Inner
+// nodes only hold separator keys and have one child more than keys: all keys
+// of children[i] are at least keys[i-1] and less than keys[i]. Payloads live
+// in the leaves, which are linked in ascending order.
+type node struct {
+	keys     []Key
+	payloads []interface{}
+	children []*node
+	next     *node
+}
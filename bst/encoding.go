@@ -0,0 +1,157 @@
+package bst
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+// snapshotVersion is the version of the binary format written by WriteTo:
+//
+//	magic | version | count (uvarint) | count × (key (varint) | payload)
+//
+// Entries are written in ascending key order, payloads are length-prefixed
+// and encoded with the tree's codec.
+const snapshotVersion = 1
+
+var snapshotMagic = [4]byte{'B', 'S', 'T', 'S'}
+
+// maxPrealloc limits how many nodes are allocated up front when reading a
+// snapshot, so corrupted counts can't exhaust memory.
+const maxPrealloc = 1 << 16
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (t *BSTree) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. All existing entries
+// of the tree are replaced.
+func (t *BSTree) UnmarshalBinary(data []byte) error {
+	_, err := t.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// WriteTo implements io.WriterTo by writing a snapshot of the tree to w.
+func (t *BSTree) WriteTo(w io.Writer) (int64, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var (
+		e = codec.NewEncoder(w)
+		c = t.payloadCodec()
+	)
+
+	e.WriteHeader(snapshotMagic, snapshotVersion)
+	e.WriteUvarint(uint64(size(t.root)))
+
+	inorder(t.root, func(n *node) {
+		e.WriteVarint(n.key)
+		e.WriteValue(c, n.payload)
+	})
+
+	return e.Flush()
+}
+
+// ReadFrom implements io.ReaderFrom by reading a snapshot written by WriteTo.
+// All existing entries of the tree are replaced. Since snapshots are sorted,
+// the tree is built in O(n) time and is balanced afterwards.
+func (t *BSTree) ReadFrom(r io.Reader) (int64, error) {
+	var (
+		d = codec.NewDecoder(r)
+		c = t.payloadCodec()
+	)
+
+	if v := d.ReadHeader(snapshotMagic); d.Err() == nil && v != snapshotVersion {
+		d.Fail(fmt.Errorf("bst: unsupported snapshot version %d", v))
+	}
+
+	count := d.ReadUvarint()
+
+	nodes := make([]*node, 0, minUint64(count, maxPrealloc))
+
+	for i := uint64(0); i < count && d.Err() == nil; i++ {
+		n := &node{
+			key:     d.ReadVarint(),
+			payload: d.ReadValue(c),
+		}
+
+		if len(nodes) > 0 && nodes[len(nodes)-1].key >= n.key {
+			d.Fail(errors.New("bst: snapshot keys are not in ascending order"))
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	if err := d.Err(); err != nil {
+		return d.Count(), err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = build(nodes, nil)
+
+	return d.Count(), nil
+}
+
+// build links sorted nodes into a balanced tree and returns its root.
+func build(nodes []*node, parent *node) *node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	mid := len(nodes) / 2
+
+	n := nodes[mid]
+	n.parent = parent
+	n.left = build(nodes[:mid], n)
+	n.right = build(nodes[mid+1:], n)
+
+	return n
+}
+
+func inorder(n *node, fn func(*node)) {
+	if n == nil {
+		return
+	}
+
+	inorder(n.left, fn)
+	fn(n)
+	inorder(n.right, fn)
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	return 1 + size(n.left) + size(n.right)
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// payloadCodec returns the codec of the tree, which is codec.Gob for a zero
+// BSTree.
+func (t *BSTree) payloadCodec() codec.Codec {
+	if t.codec == nil {
+		return codec.Gob{}
+	}
+
+	return t.codec
+}
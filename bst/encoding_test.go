@@ -0,0 +1,122 @@
+package bst
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+type failingCodec struct{}
+
+func (failingCodec) Marshal(interface{}) ([]byte, error) {
+	return nil, errors.New("nope")
+}
+
+func (failingCodec) Unmarshal([]byte) (interface{}, error) {
+	return nil, errors.New("nope")
+}
+
+func TestBSTree_MarshalBinary(t *testing.T) {
+	tt := []struct {
+		name       string
+		items      []int64
+		wantHeight int
+	}{
+		{name: "empty tree", wantHeight: -1},
+		{name: "rooted tree", items: []int64{15}, wantHeight: 0},
+		{name: "degenerated tree gets balanced", items: []int64{1, 2, 3, 4, 5, 6, 7}, wantHeight: 2},
+		{name: "negative keys", items: []int64{-5, 3, -10, 0}, wantHeight: 2},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := NewBSTree()
+
+			for _, k := range tc.items {
+				tree.Upsert(k, k)
+			}
+
+			b, err := tree.MarshalBinary()
+			require.NoError(t, err)
+
+			got := NewBSTree()
+			require.NoError(t, got.UnmarshalBinary(b))
+
+			assert.Equal(t, tc.wantHeight, got.Height())
+
+			for _, k := range tc.items {
+				assert.Equal(t, k, got.Search(k))
+			}
+
+			if got.root != nil {
+				assert.Nil(t, got.root.parent)
+			}
+		})
+	}
+}
+
+func TestBSTree_ReadFrom(t *testing.T) {
+	t.Run("replaces existing entries", func(t *testing.T) {
+		src := NewBSTree()
+		src.Upsert(1, "1")
+
+		var buf bytes.Buffer
+
+		n, err := src.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		dst := NewBSTree()
+		dst.Upsert(2, "2")
+
+		read, err := dst.ReadFrom(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, n, read)
+
+		assert.Equal(t, "1", dst.Search(1))
+		assert.Nil(t, dst.Search(2))
+	})
+
+	t.Run("truncated snapshot returns error", func(t *testing.T) {
+		src := NewBSTree()
+		src.Upsert(1, "1")
+
+		b, err := src.MarshalBinary()
+		require.NoError(t, err)
+
+		assert.Error(t, NewBSTree().UnmarshalBinary(b[:len(b)-1]))
+	})
+
+	t.Run("invalid magic number returns error", func(t *testing.T) {
+		assert.Equal(t, codec.ErrInvalidMagic, NewBSTree().UnmarshalBinary([]byte("RBTS\x01\x00")))
+	})
+
+	t.Run("unsorted keys return error", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		e := codec.NewEncoder(&buf)
+		e.WriteHeader(snapshotMagic, snapshotVersion)
+		e.WriteUvarint(2)
+		e.WriteVarint(2)
+		e.WriteValue(codec.Gob{}, nil)
+		e.WriteVarint(2)
+		e.WriteValue(codec.Gob{}, nil)
+		_, err := e.Flush()
+		require.NoError(t, err)
+
+		assert.Error(t, NewBSTree().UnmarshalBinary(buf.Bytes()))
+	})
+
+	t.Run("custom codec is used", func(t *testing.T) {
+		tree := NewBSTree(WithCodec(failingCodec{}))
+		tree.Upsert(1, "1")
+
+		_, err := tree.MarshalBinary()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,21 @@
+package bst
+
+import "github.com/obitech/go-trees/codec"
+
+// Option configures a BSTree on construction.
+type Option func(*BSTree)
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *BSTree) {
+		t.lock.Disable()
+	}
+}
+
+// WithCodec sets the codec used to encode payloads when the tree is
+// serialized. Defaults to codec.Gob.
+func WithCodec(c codec.Codec) Option {
+	return func(t *BSTree) {
+		t.codec = c
+	}
+}
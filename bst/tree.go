@@ -3,21 +3,29 @@ package bst
 
 import (
 	"math"
-	"sync"
+
+	"github.com/obitech/go-trees/codec"
 )
 
-// NewBSTree returns an empty binary search tree.
-func NewBSTree() *BSTree {
-	return &BSTree{
-		RWMutex: sync.RWMutex{},
-		root:    nil,
+// NewBSTree returns an empty binary search tree. Unless WithoutLocking is
+// passed, all operations on the tree are safe to be accessed concurrently.
+func NewBSTree(opts ...Option) *BSTree {
+	t := &BSTree{
+		root:  nil,
+		codec: codec.Gob{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
 	}
+
+	return t
 }
 
 // Root returns the payload of the root node of the tree.
 func (t *BSTree) Root() interface{} {
-	t.RLock()
-	defer t.RUnlock()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 
 	if t.root == nil {
 		return nil
@@ -30,16 +38,16 @@ func (t *BSTree) Root() interface{} {
 // has no nodes. A (rooted) tree with only a node (the root) has a height of
 // zero.
 func (t *BSTree) Height() int {
-	t.RLock()
-	defer t.RUnlock()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 
 	return int(height(t.root))
 }
 
 // Upsert inserts or updates an item. Runs in O(lg n) time on average.
 func (t *BSTree) Upsert(key int64, payload interface{}) {
-	t.Lock()
-	defer t.Unlock()
+	t.lock.Lock()
+	defer t.lock.Unlock()
 
 	if existing := search(t.root, key); existing != nil {
 		existing.payload = payload
@@ -80,8 +88,8 @@ func (t *BSTree) Upsert(key int64, payload interface{}) {
 
 // Search searches for a node based on its key and returns the payload.
 func (t *BSTree) Search(key int64) interface{} {
-	t.RLock()
-	defer t.RUnlock()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 
 	if t.root == nil {
 		return nil
@@ -98,8 +106,8 @@ func (t *BSTree) Search(key int64) interface{} {
 
 // Min returns the payload of the Node with the lowest key, or nil.
 func (t *BSTree) Min() interface{} {
-	t.RLock()
-	defer t.RUnlock()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 
 	n := min(t.root)
 
@@ -112,8 +120,8 @@ func (t *BSTree) Min() interface{} {
 
 // Max returns the payload of the Node with the highest key, or nil.
 func (t *BSTree) Max() interface{} {
-	t.RLock()
-	defer t.RUnlock()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 
 	n := max(t.root)
 
@@ -127,8 +135,8 @@ func (t *BSTree) Max() interface{} {
 // Successor returns the next highest neighbour (key-wise) of the Node with the
 // passed key.
 func (t *BSTree) Successor(key int64) interface{} {
-	t.RLock()
-	defer t.RUnlock()
+	t.lock.RLock()
+	defer t.lock.RUnlock()
 
 	n := successor(search(t.root, key))
 
@@ -142,8 +150,8 @@ func (t *BSTree) Successor(key int64) interface{} {
 // Delete deletes a node with a given key. This runs in O(h) time with h being
 // the height of the tree.
 func (t *BSTree) Delete(key int64) {
-	t.Lock()
-	defer t.Unlock()
+	t.lock.Lock()
+	defer t.lock.Unlock()
 
 	if n := search(t.root, key); n != nil {
 		t.delete(n)
@@ -181,7 +189,7 @@ func (t *BSTree) delete(node *node) {
 		// the node by its successor, attaching node's left child.
 		t.transplant(node, succ)
 		succ.left = node.left
-		succ.left.parent = node
+		succ.left.parent = succ
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type item struct {
@@ -703,6 +704,7 @@ func TestTree_Delete(t *testing.T) {
 		assert.Nil(t, y.parent)
 		assert.Equal(t, 1, tree.Height())
 		assert.Equal(t, l, y.left)
+		assert.Equal(t, y, l.parent)
 		assert.Equal(t, x, y.right)
 	})
 
@@ -739,3 +741,37 @@ func TestTree_Delete(t *testing.T) {
 		assert.Equal(t, x, r.left)
 	})
 }
+
+func TestBSTree_zeroValue(t *testing.T) {
+	var tree BSTree
+
+	tree.Upsert(15, "15")
+	tree.Upsert(10, "10")
+	tree.Delete(15)
+
+	assert.Equal(t, "10", tree.Root())
+
+	var restored BSTree
+
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, restored.UnmarshalBinary(data))
+
+	assert.Equal(t, "10", restored.Search(10))
+}
+
+func TestNewBSTree_WithoutLocking(t *testing.T) {
+	tree := NewBSTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+
+	tree.Upsert(15, "15")
+	tree.Upsert(10, "10")
+	tree.Upsert(20, "20")
+	tree.Delete(10)
+
+	assert.Equal(t, "15", tree.Root())
+	assert.Equal(t, "15", tree.Min())
+	assert.Equal(t, "20", tree.Max())
+	assert.Nil(t, tree.Search(10))
+}
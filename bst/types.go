@@ -1,14 +1,16 @@
 package bst
 
 import (
-	"sync"
+	"github.com/obitech/go-trees/codec"
+	"github.com/obitech/go-trees/internal/lock"
 )
 
-// BSTree represents a binary search tree with a root node and Mutex to protect
-// concurrent access.
+// BSTree represents a binary search tree with a root node and a lock to
+// protect concurrent access.
 type BSTree struct {
-	sync.RWMutex
-	root *node
+	lock  lock.RWMutex
+	root  *node
+	codec codec.Codec
 }
 
 type node struct {
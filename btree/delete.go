@@ -0,0 +1,161 @@
+package btree
+
+// Delete deletes the given key.
+func (t *Tree) Delete(key Key) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.root == nil {
+		return
+	}
+
+	if t.delete(t.root, key) {
+		t.size--
+	}
+
+	// Merging the last two children of the root is the only way the tree
+	// shrinks in height.
+	if len(t.root.items) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+}
+
+// delete removes key from the subtree rooted at n, which holds at least degree
+// items unless it's the root. Children are refilled on the way down, so a key
+// can always be removed without underflowing a node. Returns true if the key
+// was found.
+func (t *Tree) delete(n *node, key Key) bool {
+	for {
+		i, found := n.find(key)
+
+		if n.leaf() {
+			if found {
+				n.removeItem(i)
+			}
+
+			return found
+		}
+
+		if found {
+			switch {
+			// Replace the key with its predecessor from the left subtree.
+			case len(n.children[i].items) >= t.degree:
+				pred := n.children[i].max()
+				n.items[i] = pred
+				n, key = n.children[i], pred.key
+			// Replace the key with its successor from the right subtree.
+			case len(n.children[i+1].items) >= t.degree:
+				succ := n.children[i+1].min()
+				n.items[i] = succ
+				n, key = n.children[i+1], succ.key
+			// Both children are minimal, so push the key down into them.
+			default:
+				t.merge(n, i)
+				n = n.children[i]
+			}
+
+			continue
+		}
+
+		if len(n.children[i].items) < t.degree {
+			i = t.fill(n, i)
+		}
+
+		n = n.children[i]
+	}
+}
+
+// fill ensures the i-th child of n has at least degree items by borrowing an
+// item from a sibling or merging it with one. Returns the index of the child
+// afterwards.
+func (t *Tree) fill(n *node, i int) int {
+	switch {
+	case i > 0 && len(n.children[i-1].items) >= t.degree:
+		t.rotateRight(n, i-1)
+	case i < len(n.items) && len(n.children[i+1].items) >= t.degree:
+		t.rotateLeft(n, i)
+	case i < len(n.items):
+		t.merge(n, i)
+	default:
+		t.merge(n, i-1)
+		i--
+	}
+
+	return i
+}
+
+// rotateRight moves the separator n.items[i] down into the right child and
+// the highest item of the left child up in its place.
+func (t *Tree) rotateRight(n *node, i int) {
+	var (
+		left  = n.children[i]
+		right = n.children[i+1]
+	)
+
+	right.items = append(right.items, item{})
+	copy(right.items[1:], right.items)
+	right.items[0] = n.items[i]
+
+	n.items[i] = left.items[len(left.items)-1]
+	left.removeItem(len(left.items) - 1)
+
+	if !left.leaf() {
+		right.children = append(right.children, nil)
+		copy(right.children[1:], right.children)
+		right.children[0] = left.children[len(left.children)-1]
+
+		left.removeChild(len(left.children) - 1)
+	}
+}
+
+// rotateLeft moves the separator n.items[i] down into the left child and the
+// lowest item of the right child up in its place.
+func (t *Tree) rotateLeft(n *node, i int) {
+	var (
+		left  = n.children[i]
+		right = n.children[i+1]
+	)
+
+	left.items = append(left.items, n.items[i])
+
+	n.items[i] = right.items[0]
+	right.removeItem(0)
+
+	if !right.leaf() {
+		left.children = append(left.children, right.children[0])
+
+		right.removeChild(0)
+	}
+}
+
+// merge merges the i+1-th child of n and the separator n.items[i] into the
+// i-th child.
+func (t *Tree) merge(n *node, i int) {
+	var (
+		left  = n.children[i]
+		right = n.children[i+1]
+	)
+
+	left.items = append(left.items, n.items[i])
+	left.items = append(left.items, right.items...)
+	left.children = append(left.children, right.children...)
+
+	n.removeItem(i)
+	n.removeChild(i + 1)
+}
+
+func (n *node) removeItem(i int) {
+	copy(n.items[i:], n.items[i+1:])
+	n.items[len(n.items)-1] = item{}
+	n.items = n.items[:len(n.items)-1]
+}
+
+func (n *node) removeChild(i int) {
+	copy(n.children[i:], n.children[i+1:])
+	n.children[len(n.children)-1] = nil
+	n.children = n.children[:len(n.children)-1]
+}
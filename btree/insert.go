@@ -0,0 +1,123 @@
+package btree
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+func (t *Tree) Upsert(key Key, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	it := item{key: key, payload: payload}
+
+	if t.root == nil {
+		t.root = t.newNode()
+		t.root.items = append(t.root.items, it)
+		t.size++
+
+		return
+	}
+
+	// Splitting a full root is the only way the tree grows in height.
+	if len(t.root.items) == t.maxItems() {
+		root := t.newNode()
+		root.children = append(root.children, t.root)
+
+		t.split(root, 0)
+		t.root = root
+	}
+
+	if t.insertNonFull(t.root, it) {
+		t.size++
+	}
+}
+
+// insertNonFull inserts or updates it in the subtree rooted at the non-full
+// node n. Full nodes are split on the way down, so there's always room for a
+// key moving up. Returns true if a new key was inserted.
+func (t *Tree) insertNonFull(n *node, it item) bool {
+	for {
+		i, found := n.find(it.key)
+
+		if found {
+			n.items[i].payload = it.payload
+			return false
+		}
+
+		if n.leaf() {
+			n.items = append(n.items, item{})
+			copy(n.items[i+1:], n.items[i:])
+			n.items[i] = it
+
+			return true
+		}
+
+		if len(n.children[i].items) == t.maxItems() {
+			t.split(n, i)
+
+			// The median of the child moved up to n.items[i].
+			switch {
+			case !n.items[i].key.Less(it.key) && !it.key.Less(n.items[i].key):
+				n.items[i].payload = it.payload
+				return false
+			case n.items[i].key.Less(it.key):
+				i++
+			}
+		}
+
+		n = n.children[i]
+	}
+}
+
+// split splits the full i-th child of n into two nodes, moving its median item
+// up into n.
+func (t *Tree) split(n *node, i int) {
+	var (
+		child = n.children[i]
+		mid   = t.degree - 1
+		right = t.newNode()
+	)
+
+	right.items = append(right.items, child.items[mid+1:]...)
+
+	if !child.leaf() {
+		right.children = append(right.children, child.children[mid+1:]...)
+
+		clearNodes(child.children[mid+1:])
+		child.children = child.children[:mid+1]
+	}
+
+	median := child.items[mid]
+
+	clearItems(child.items[mid:])
+	child.items = child.items[:mid]
+
+	n.items = append(n.items, item{})
+	copy(n.items[i+1:], n.items[i:])
+	n.items[i] = median
+
+	n.children = append(n.children, nil)
+	copy(n.children[i+2:], n.children[i+1:])
+	n.children[i+1] = right
+}
+
+func (t *Tree) newNode() *node {
+	return &node{
+		items: make([]item, 0, t.maxItems()),
+	}
+}
+
+func (t *Tree) maxItems() int {
+	return 2*t.degree - 1
+}
+
+// clearItems drops references to keys and payloads that were moved elsewhere,
+// so they can be garbage collected.
+func clearItems(items []item) {
+	for i := range items {
+		items[i] = item{}
+	}
+}
+
+func clearNodes(nodes []*node) {
+	for i := range nodes {
+		nodes[i] = nil
+	}
+}
@@ -0,0 +1,24 @@
+package btree
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithDegree sets the minimum degree t of the tree: every node but the root
+// holds at least t-1 and at most 2t-1 keys. Higher degrees result in flatter
+// trees with better cache locality. Panics if t is less than 2.
+func WithDegree(t int) Option {
+	if t < 2 {
+		panic("btree: degree must be at least 2")
+	}
+
+	return func(tree *Tree) {
+		tree.degree = t
+	}
+}
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
@@ -0,0 +1,70 @@
+package btree
+
+// InOrder returns an ordered list of all entries.
+func (t *Tree) InOrder() []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	res := make([]Result, 0, t.size)
+
+	t.root.ascend(nil, nil, func(it item) bool {
+		res = append(res, Result{
+			Key:     it.key,
+			Payload: it.payload,
+		})
+
+		return true
+	})
+
+	return res
+}
+
+// Range calls fn in ascending order for every key in the half-open range
+// [from, to). A nil bound leaves that side of the range open. Iteration stops
+// early if fn returns false. The tree must not be modified from within fn.
+func (t *Tree) Range(from, to Key, fn func(key Key, payload interface{}) bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return
+	}
+
+	t.root.ascend(from, to, func(it item) bool {
+		return fn(it.key, it.payload)
+	})
+}
+
+// ascend calls fn for all items of the subtree in [from, to), skipping
+// subtrees entirely below from. Returns false if iteration was stopped.
+func (n *node) ascend(from, to Key, fn func(item) bool) bool {
+	i := 0
+
+	if from != nil {
+		i, _ = n.find(from)
+	}
+
+	for ; i < len(n.items); i++ {
+		if !n.leaf() && !n.children[i].ascend(from, to, fn) {
+			return false
+		}
+
+		if to != nil && !n.items[i].key.Less(to) {
+			return false
+		}
+
+		if !fn(n.items[i]) {
+			return false
+		}
+	}
+
+	if !n.leaf() {
+		return n.children[len(n.children)-1].ascend(from, to, fn)
+	}
+
+	return true
+}
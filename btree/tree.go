@@ -0,0 +1,172 @@
+// Package btree implements an in-memory B-tree, which stores many keys per
+// node to reduce pointer chasing and improve cache behaviour compared to
+// binary search trees. All operations run in O(lg n) time.
+package btree
+
+import (
+	"sort"
+)
+
+// NewBTree returns a new B-tree. Unless WithoutLocking is passed, all
+// operations on the tree are safe to be accessed concurrently.
+func NewBTree(opts ...Option) *Tree {
+	t := &Tree{
+		degree: DefaultDegree,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Root returns the payload of the median key of the root node of the tree.
+func (t *Tree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.items[len(t.root.items)/2].payload
+}
+
+// Height returns the height (max depth) of the tree, counted in nodes rather
+// than keys. Returns -1 if the tree has no nodes. A tree with only a root node
+// has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	h := -1
+
+	// All leaves of a B-tree have the same depth.
+	for n := t.root; n != nil; h++ {
+		if n.leaf() {
+			n = nil
+		} else {
+			n = n.children[0]
+		}
+	}
+
+	return h
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *Tree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.min().payload
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *Tree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.max().payload
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *Tree) Search(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	for n := t.root; n != nil; {
+		i, found := n.find(key)
+
+		if found {
+			return n.items[i].payload
+		}
+
+		if n.leaf() {
+			break
+		}
+
+		n = n.children[i]
+	}
+
+	return nil
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (t *Tree) Successor(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	// The lowest key greater than all keys visited so far.
+	var candidate *item
+
+	for n := t.root; n != nil; {
+		i, found := n.find(key)
+
+		switch {
+		case found && !n.leaf():
+			return n.children[i+1].min().payload
+		case found && i+1 < len(n.items):
+			return n.items[i+1].payload
+		case found && candidate != nil:
+			return candidate.payload
+		case found, n.leaf():
+			return nil
+		}
+
+		if i < len(n.items) {
+			candidate = &n.items[i]
+		}
+
+		n = n.children[i]
+	}
+
+	return nil
+}
+
+func (n *node) leaf() bool {
+	return len(n.children) == 0
+}
+
+// find returns the index of the first item of n whose key is not less than
+// key, and whether that item's key equals key.
+func (n *node) find(key Key) (int, bool) {
+	i := sort.Search(len(n.items), func(i int) bool {
+		return !n.items[i].key.Less(key)
+	})
+
+	return i, i < len(n.items) && !key.Less(n.items[i].key)
+}
+
+func (n *node) min() item {
+	for !n.leaf() {
+		n = n.children[0]
+	}
+
+	return n.items[0]
+}
+
+func (n *node) max() item {
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+
+	return n.items[len(n.items)-1]
+}
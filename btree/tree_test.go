@@ -0,0 +1,346 @@
+package btree
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the B-tree invariants and returns the number of keys and the
+// depth of the leaves.
+func verify(t *testing.T, tree *Tree) {
+	if tree.root == nil {
+		require.Equal(t, 0, tree.size)
+		return
+	}
+
+	var (
+		leafDepth = -1
+		count     int
+		walk      func(n *node, depth int, lo, hi Key)
+	)
+
+	walk = func(n *node, depth int, lo, hi Key) {
+		if n != tree.root {
+			require.GreaterOrEqual(t, len(n.items), tree.degree-1, "underflow")
+		}
+
+		require.NotEmpty(t, n.items)
+		require.LessOrEqual(t, len(n.items), tree.maxItems(), "overflow")
+
+		for i, it := range n.items {
+			if i > 0 {
+				require.True(t, n.items[i-1].key.Less(it.key), "items not sorted")
+			}
+
+			if lo != nil {
+				require.True(t, lo.Less(it.key), "%v not greater than %v", it.key, lo)
+			}
+
+			if hi != nil {
+				require.True(t, it.key.Less(hi), "%v not less than %v", it.key, hi)
+			}
+		}
+
+		count += len(n.items)
+
+		if n.leaf() {
+			if leafDepth == -1 {
+				leafDepth = depth
+			}
+
+			require.Equal(t, leafDepth, depth, "leaves at different depths")
+
+			return
+		}
+
+		require.Len(t, n.children, len(n.items)+1)
+
+		for i, c := range n.children {
+			var l, h = lo, hi
+
+			if i > 0 {
+				l = n.items[i-1].key
+			}
+
+			if i < len(n.items) {
+				h = n.items[i].key
+			}
+
+			walk(c, depth+1, l, h)
+		}
+	}
+
+	walk(tree.root, 0, nil, nil)
+
+	require.Equal(t, tree.size, count)
+}
+
+func leaf(keys ...int) *node {
+	n := &node{}
+
+	for _, k := range keys {
+		n.items = append(n.items, item{key: myInt(k), payload: k})
+	}
+
+	return n
+}
+
+func branch(keys []int, children ...*node) *node {
+	n := leaf(keys...)
+	n.children = children
+
+	return n
+}
+
+// newTree returns a tree of degree 2 with the given root.
+func newTree(root *node) *Tree {
+	tree := NewBTree(WithDegree(2))
+	tree.root = root
+	tree.size = len(tree.InOrder())
+
+	return tree
+}
+
+// shape renders the subtree rooted at n, e.g. "((1) 2 (3 4))".
+func shape(n *node) string {
+	if n == nil {
+		return "()"
+	}
+
+	var parts []string
+
+	for i, it := range n.items {
+		if !n.leaf() {
+			parts = append(parts, shape(n.children[i]))
+		}
+
+		parts = append(parts, fmt.Sprint(it.key))
+	}
+
+	if !n.leaf() {
+		parts = append(parts, shape(n.children[len(n.items)]))
+	}
+
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+func TestTree_Upsert(t *testing.T) {
+	t.Run("full root gets split", func(t *testing.T) {
+		tree := NewBTree(WithDegree(2))
+
+		tree.Upsert(myInt(1), 1)
+		tree.Upsert(myInt(2), 2)
+		tree.Upsert(myInt(3), 3)
+
+		assert.Equal(t, 0, tree.Height())
+
+		tree.Upsert(myInt(4), 4)
+
+		assert.Equal(t, 1, tree.Height())
+		assert.Equal(t, 2, tree.Root())
+		verify(t, tree)
+	})
+
+	t.Run("update of key moved up by split", func(t *testing.T) {
+		tree := NewBTree(WithDegree(2))
+
+		for _, k := range []myInt{10, 20, 30, 40, 50, 60} {
+			tree.Upsert(k, int(k))
+		}
+
+		// The right child (30, 40, 50, 60) is full at this point.
+		tree.Upsert(myInt(50), "fifty")
+
+		assert.Equal(t, "fifty", tree.Search(myInt(50)))
+		assert.Equal(t, 6, tree.Len())
+		verify(t, tree)
+	})
+}
+
+func TestTree_Delete(t *testing.T) {
+	// Nodes of degree 2 hold one to three items, so each of these trees is
+	// left with nodes too small to delete from without refilling them first.
+	tt := []struct {
+		name string
+		root *node
+		key  int
+		want string
+	}{
+		{
+			name: "child borrows from left sibling",
+			root: branch([]int{3}, leaf(1, 2), leaf(4)),
+			key:  4,
+			want: "((1) 2 (3))",
+		},
+		{
+			name: "child borrows from right sibling",
+			root: branch([]int{2}, leaf(1), leaf(3, 4)),
+			key:  1,
+			want: "((2) 3 (4))",
+		},
+		{
+			name: "child merges with minimal siblings",
+			root: branch([]int{2, 4}, leaf(1), leaf(3), leaf(5)),
+			key:  3,
+			want: "((1) 2 (4 5))",
+		},
+		{
+			name: "last child merges with left sibling",
+			root: branch([]int{2, 4}, leaf(1), leaf(3), leaf(5)),
+			key:  5,
+			want: "((1) 2 (3 4))",
+		},
+		{
+			name: "merging the last children of the root shrinks the tree",
+			root: branch([]int{2}, leaf(1), leaf(3)),
+			key:  1,
+			want: "(2 3)",
+		},
+		{
+			name: "inner key is replaced by predecessor",
+			root: branch([]int{3}, leaf(1, 2), leaf(4)),
+			key:  3,
+			want: "((1) 2 (4))",
+		},
+		{
+			name: "inner key is replaced by successor",
+			root: branch([]int{2}, leaf(1), leaf(3, 4)),
+			key:  2,
+			want: "((1) 3 (4))",
+		},
+		{
+			name: "inner key is pushed down into minimal children",
+			root: branch([]int{2}, leaf(1), leaf(3)),
+			key:  2,
+			want: "(1 3)",
+		},
+		{
+			name: "inner child borrows subtree from right sibling",
+			root: branch([]int{4},
+				branch([]int{2}, leaf(1), leaf(3)),
+				branch([]int{6, 8}, leaf(5), leaf(7), leaf(9)),
+			),
+			key:  1,
+			want: "(((2 3) 4 (5)) 6 ((7) 8 (9)))",
+		},
+		{
+			name: "inner child borrows subtree from left sibling",
+			root: branch([]int{6},
+				branch([]int{2, 4}, leaf(1), leaf(3), leaf(5)),
+				branch([]int{8}, leaf(7), leaf(9)),
+			),
+			key:  9,
+			want: "(((1) 2 (3)) 4 ((5) 6 (7 8)))",
+		},
+		{
+			name: "missing key leaves refilled nodes",
+			root: branch([]int{3}, leaf(1, 2), leaf(4)),
+			key:  5,
+			want: "((1) 2 (3 4))",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTree(tc.root)
+
+			tree.Delete(myInt(tc.key))
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			assert.Nil(t, tree.Search(myInt(tc.key)))
+			verify(t, tree)
+		})
+	}
+}
+
+func TestTree_random(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, 32} {
+		var (
+			tree = NewBTree(WithDegree(degree))
+			want = make(map[myInt]int)
+			rng  = rand.New(rand.NewSource(int64(degree)))
+		)
+
+		for i := 0; i < 5000; i++ {
+			k := myInt(rng.Intn(1000))
+
+			if rng.Intn(3) == 0 {
+				tree.Delete(k)
+				delete(want, k)
+			} else {
+				tree.Upsert(k, i)
+				want[k] = i
+			}
+
+			if i%250 == 0 {
+				verify(t, tree)
+			}
+		}
+
+		verify(t, tree)
+
+		require.Equal(t, len(want), tree.Len(), "degree %d", degree)
+	}
+}
+
+func TestTree_Range(t *testing.T) {
+	tree := NewBTree(WithDegree(2))
+
+	for i := 0; i < 50; i += 2 {
+		tree.Upsert(myInt(i), i)
+	}
+
+	collect := func(from, to Key, limit int) []int {
+		var res []int
+
+		tree.Range(from, to, func(key Key, payload interface{}) bool {
+			res = append(res, payload.(int))
+			return len(res) < limit
+		})
+
+		return res
+	}
+
+	tt := []struct {
+		name  string
+		from  Key
+		to    Key
+		limit int
+		want  []int
+	}{
+		{name: "bounded range", from: myInt(10), to: myInt(20), limit: 100, want: []int{10, 12, 14, 16, 18}},
+		{name: "bounds between keys", from: myInt(9), to: myInt(15), limit: 100, want: []int{10, 12, 14}},
+		{name: "open lower bound", to: myInt(5), limit: 100, want: []int{0, 2, 4}},
+		{name: "open upper bound", from: myInt(44), limit: 100, want: []int{44, 46, 48}},
+		{name: "stops early", from: myInt(10), limit: 2, want: []int{10, 12}},
+		{name: "empty range", from: myInt(11), to: myInt(12), limit: 100},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, collect(tc.from, tc.to, tc.limit))
+		})
+	}
+
+	t.Run("full range equals InOrder", func(t *testing.T) {
+		all := collect(nil, nil, 100)
+		assert.Len(t, all, tree.Len())
+	})
+}
+
+func TestWithDegree(t *testing.T) {
+	assert.Panics(t, func() { WithDegree(1) })
+	assert.Equal(t, DefaultDegree, NewBTree().degree)
+	assert.Equal(t, 5, NewBTree(WithDegree(5)).degree)
+}
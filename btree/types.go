@@ -0,0 +1,37 @@
+package btree
+
+import (
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the tree have to implement. It is shared with
+// package redblack, so the same key types work with both trees.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the tree.
+type Result = redblack.Result
+
+// DefaultDegree is the minimum degree of a tree unless WithDegree is passed.
+const DefaultDegree = 32
+
+// Tree represents a B-tree with a root node and a lock to protect concurrent
+// access.
+type Tree struct {
+	lock   lock.RWMutex
+	root   *node
+	degree int
+	size   int
+}
+
+// node holds between degree-1 and 2*degree-1 items, the root may hold less.
+// Inner nodes have one child more than items.
+type node struct {
+	items    []item
+	children []*node
+}
+
+type item struct {
+	key     Key
+	payload interface{}
+}
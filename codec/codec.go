@@ -0,0 +1,41 @@
+// Package codec provides pluggable binary encodings for the keys and payloads
+// stored in the trees of this module.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// Codec converts arbitrary values to and from their binary representation.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte) (interface{}, error)
+}
+
+// Gob is a Codec based on encoding/gob. Values are encoded as interfaces, so
+// all concrete types besides the predeclared ones have to be registered with
+// gob.Register before they can be encoded.
+type Gob struct{}
+
+// Marshal encodes v with gob.
+func (Gob) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes a value previously encoded by Marshal.
+func (Gob) Unmarshal(data []byte) (interface{}, error) {
+	var v interface{}
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
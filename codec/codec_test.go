@@ -0,0 +1,52 @@
+package codec
+
+import (
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y int
+}
+
+func TestGob(t *testing.T) {
+	gob.Register(point{})
+
+	tt := []struct {
+		name string
+		v    interface{}
+	}{
+		{name: "nil", v: nil},
+		{name: "string", v: "test"},
+		{name: "int", v: 42},
+		{name: "float", v: 4.2},
+		{name: "registered struct", v: point{X: 1, Y: 2}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := Gob{}.Marshal(tc.v)
+			require.NoError(t, err)
+
+			v, err := Gob{}.Unmarshal(b)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.v, v)
+		})
+	}
+
+	t.Run("unregistered type returns error", func(t *testing.T) {
+		type unregistered struct{ A int }
+
+		_, err := Gob{}.Marshal(unregistered{A: 1})
+		assert.Error(t, err)
+	})
+
+	t.Run("garbage returns error", func(t *testing.T) {
+		_, err := Gob{}.Unmarshal([]byte{0xff, 0x00})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,233 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidMagic is returned by Decoder.ReadHeader if the stream doesn't
+// start with the expected magic number.
+var ErrInvalidMagic = errors.New("codec: invalid magic number")
+
+// Encoder writes the primitives of the snapshot formats used by the trees of
+// this module. The first error is sticky and returned by Flush.
+type Encoder struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+	buf [binary.MaxVarintLen64]byte
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// WriteHeader writes a magic number followed by a format version.
+func (e *Encoder) WriteHeader(magic [4]byte, version byte) {
+	e.write(append(magic[:], version))
+}
+
+// WriteUvarint writes an unsigned varint.
+func (e *Encoder) WriteUvarint(v uint64) {
+	e.write(e.buf[:binary.PutUvarint(e.buf[:], v)])
+}
+
+// WriteVarint writes a signed varint.
+func (e *Encoder) WriteVarint(v int64) {
+	e.write(e.buf[:binary.PutVarint(e.buf[:], v)])
+}
+
+// WriteBytes writes a length-prefixed byte slice.
+func (e *Encoder) WriteBytes(b []byte) {
+	e.WriteUvarint(uint64(len(b)))
+	e.write(b)
+}
+
+// WriteValue encodes v with c and writes it as a length-prefixed byte slice.
+func (e *Encoder) WriteValue(c Codec, v interface{}) {
+	if e.err != nil {
+		return
+	}
+
+	b, err := c.Marshal(v)
+	if err != nil {
+		e.err = fmt.Errorf("codec: encoding %T: %w", v, err)
+		return
+	}
+
+	e.WriteBytes(b)
+}
+
+// Flush flushes all buffered data and returns the number of bytes written
+// along with the first error encountered.
+func (e *Encoder) Flush() (int64, error) {
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+
+	return e.n, e.err
+}
+
+func (e *Encoder) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+
+	n, err := e.w.Write(b)
+	e.n += int64(n)
+	e.err = err
+}
+
+// Decoder reads the primitives written by an Encoder. The first error is
+// sticky and returned by Err.
+type Decoder struct {
+	r   io.ByteReader
+	n   int64
+	err error
+}
+
+// NewDecoder returns a Decoder reading from r. If r doesn't implement
+// io.ByteReader it gets buffered, in which case the Decoder may read past the
+// end of the encoded data.
+func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	return &Decoder{r: br}
+}
+
+// ReadHeader reads a header written by Encoder.WriteHeader and returns the
+// format version.
+func (d *Decoder) ReadHeader(magic [4]byte) byte {
+	var header [len(magic) + 1]byte
+
+	for i := range header {
+		header[i] = d.readByte()
+	}
+
+	if d.err == nil && !bytes.Equal(header[:len(magic)], magic[:]) {
+		d.err = ErrInvalidMagic
+	}
+
+	return header[len(magic)]
+}
+
+// ReadUvarint reads an unsigned varint.
+func (d *Decoder) ReadUvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, err := binary.ReadUvarint(d)
+	d.setErr(err)
+
+	return v
+}
+
+// ReadVarint reads a signed varint.
+func (d *Decoder) ReadVarint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, err := binary.ReadVarint(d)
+	d.setErr(err)
+
+	return v
+}
+
+// ReadBytes reads a length-prefixed byte slice.
+func (d *Decoder) ReadBytes() []byte {
+	n := d.ReadUvarint()
+	if d.err != nil {
+		return nil
+	}
+
+	// Grow the buffer as data arrives instead of trusting the length prefix,
+	// so corrupted input can't trigger huge allocations.
+	var buf bytes.Buffer
+
+	if r, ok := d.r.(io.Reader); ok {
+		read, err := io.CopyN(&buf, r, int64(n))
+		d.n += read
+		d.setErr(err)
+
+		return buf.Bytes()
+	}
+
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		buf.WriteByte(d.readByte())
+	}
+
+	return buf.Bytes()
+}
+
+// ReadValue reads a length-prefixed byte slice and decodes it with c.
+func (d *Decoder) ReadValue(c Codec) interface{} {
+	b := d.ReadBytes()
+	if d.err != nil {
+		return nil
+	}
+
+	v, err := c.Unmarshal(b)
+	if err != nil {
+		d.err = fmt.Errorf("codec: decoding value: %w", err)
+	}
+
+	return v
+}
+
+// Fail records err unless an earlier error has been encountered already.
+func (d *Decoder) Fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// Err returns the first error encountered.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Count returns the number of bytes read.
+func (d *Decoder) Count() int64 {
+	return d.n
+}
+
+// ReadByte implements io.ByteReader.
+func (d *Decoder) ReadByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err == nil {
+		d.n++
+	}
+
+	return b, err
+}
+
+func (d *Decoder) readByte() byte {
+	if d.err != nil {
+		return 0
+	}
+
+	b, err := d.ReadByte()
+	d.setErr(err)
+
+	return b
+}
+
+func (d *Decoder) setErr(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	if d.err == nil {
+		d.err = err
+	}
+}
@@ -0,0 +1,80 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMagic = [4]byte{'T', 'E', 'S', 'T'}
+
+func TestEncoderDecoder(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		e := NewEncoder(&buf)
+		e.WriteHeader(testMagic, 3)
+		e.WriteUvarint(300)
+		e.WriteVarint(-42)
+		e.WriteBytes([]byte("foo"))
+		e.WriteBytes(nil)
+		e.WriteValue(Gob{}, "bar")
+
+		n, err := e.Flush()
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		d := NewDecoder(&buf)
+
+		assert.Equal(t, byte(3), d.ReadHeader(testMagic))
+		assert.Equal(t, uint64(300), d.ReadUvarint())
+		assert.Equal(t, int64(-42), d.ReadVarint())
+		assert.Equal(t, []byte("foo"), d.ReadBytes())
+		assert.Empty(t, d.ReadBytes())
+		assert.Equal(t, "bar", d.ReadValue(Gob{}))
+		assert.NoError(t, d.Err())
+		assert.Equal(t, n, d.Count())
+	})
+
+	t.Run("invalid magic number", func(t *testing.T) {
+		d := NewDecoder(bytes.NewReader([]byte("NOPE\x01")))
+		d.ReadHeader(testMagic)
+
+		assert.Equal(t, ErrInvalidMagic, d.Err())
+	})
+
+	t.Run("truncated input returns ErrUnexpectedEOF", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		e := NewEncoder(&buf)
+		e.WriteBytes([]byte("foobar"))
+		_, err := e.Flush()
+		require.NoError(t, err)
+
+		d := NewDecoder(bytes.NewReader(buf.Bytes()[:4]))
+		d.ReadBytes()
+
+		assert.Equal(t, io.ErrUnexpectedEOF, d.Err())
+	})
+
+	t.Run("errors are sticky", func(t *testing.T) {
+		d := NewDecoder(bytes.NewReader(nil))
+		d.Fail(errors.New("first"))
+		d.Fail(errors.New("second"))
+
+		assert.Equal(t, uint64(0), d.ReadUvarint())
+		assert.EqualError(t, d.Err(), "first")
+	})
+
+	t.Run("encoding errors are returned by Flush", func(t *testing.T) {
+		e := NewEncoder(&bytes.Buffer{})
+		e.WriteValue(Gob{}, func() {})
+
+		_, err := e.Flush()
+		assert.Error(t, err)
+	})
+}
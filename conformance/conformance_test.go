@@ -0,0 +1,432 @@
+package conformance
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obitech/go-trees/avl"
+	"github.com/obitech/go-trees/bplustree"
+	"github.com/obitech/go-trees/bst"
+	"github.com/obitech/go-trees/btree"
+	"github.com/obitech/go-trees/llrb"
+	"github.com/obitech/go-trees/redblack"
+	"github.com/obitech/go-trees/scapegoat"
+	"github.com/obitech/go-trees/splay"
+	"github.com/obitech/go-trees/treap"
+	"github.com/obitech/go-trees/wbtree"
+)
+
+// orderedMap is the common surface of all ordered trees, using int64 keys.
+type orderedMap interface {
+	Upsert(key int64, payload interface{})
+	Search(key int64) interface{}
+	Delete(key int64)
+	Min() interface{}
+	Max() interface{}
+	Successor(key int64) interface{}
+	Height() int
+	Root() interface{}
+}
+
+type intKey int64
+
+func (i intKey) Less(v redblack.Key) bool {
+	return i < v.(intKey)
+}
+
+// keyed is implemented by all trees using redblack.Key.
+type keyed interface {
+	Upsert(key redblack.Key, payload interface{})
+	Search(key redblack.Key) interface{}
+	Delete(key redblack.Key)
+	Min() interface{}
+	Max() interface{}
+	Successor(key redblack.Key) interface{}
+	Height() int
+	Root() interface{}
+}
+
+// keyedMap adapts a tree using redblack.Key to orderedMap.
+type keyedMap struct {
+	t keyed
+}
+
+func (m keyedMap) Upsert(key int64, payload interface{}) { m.t.Upsert(intKey(key), payload) }
+func (m keyedMap) Search(key int64) interface{}          { return m.t.Search(intKey(key)) }
+func (m keyedMap) Delete(key int64)                      { m.t.Delete(intKey(key)) }
+func (m keyedMap) Min() interface{}                      { return m.t.Min() }
+func (m keyedMap) Max() interface{}                      { return m.t.Max() }
+func (m keyedMap) Successor(key int64) interface{}       { return m.t.Successor(intKey(key)) }
+func (m keyedMap) Height() int                           { return m.t.Height() }
+func (m keyedMap) Root() interface{}                     { return m.t.Root() }
+
+type implementation struct {
+	name string
+	new  func() orderedMap
+	// maxHeight returns the worst-case height for n keys, or -1 if the tree
+	// isn't balanced.
+	maxHeight func(n int) float64
+	// fanout is the maximum number of children per node. Zero means two.
+	fanout int
+}
+
+var implementations = []implementation{
+	{
+		name:      "bst",
+		new:       func() orderedMap { return bst.NewBSTree() },
+		maxHeight: func(n int) float64 { return -1 },
+	},
+	{
+		name:      "redblack",
+		new:       func() orderedMap { return keyedMap{redblack.NewRedBlackTree()} },
+		maxHeight: func(n int) float64 { return 2 * math.Log2(float64(n+1)) },
+	},
+	{
+		name:      "llrb",
+		new:       func() orderedMap { return keyedMap{llrb.NewLLRBTree()} },
+		maxHeight: func(n int) float64 { return 2 * math.Log2(float64(n+1)) },
+	},
+	{
+		name:      "avl",
+		new:       func() orderedMap { return keyedMap{avl.NewAVLTree()} },
+		maxHeight: func(n int) float64 { return 1.44 * math.Log2(float64(n+2)) },
+	},
+	{
+		// The smallest degree yields the tallest trees and the most splits
+		// and merges.
+		name:      "btree",
+		new:       func() orderedMap { return keyedMap{btree.NewBTree(btree.WithDegree(2))} },
+		maxHeight: func(n int) float64 { return math.Log2(float64(n+1) / 2) },
+		fanout:    4,
+	},
+	{
+		name:      "bplustree",
+		new:       func() orderedMap { return keyedMap{bplustree.NewBPlusTree(bplustree.WithOrder(3))} },
+		maxHeight: func(n int) float64 { return math.Log2(float64(n + 1)) },
+		fanout:    4,
+	},
+	{
+		// Treaps are only balanced with high probability.
+		name:      "treap",
+		new:       func() orderedMap { return keyedMap{treap.NewTreap(treap.WithSeed(1))} },
+		maxHeight: func(n int) float64 { return -1 },
+	},
+	{
+		// Splay trees are only balanced in the amortized sense.
+		name:      "splay",
+		new:       func() orderedMap { return splay.NewSplayTree() },
+		maxHeight: func(n int) float64 { return -1 },
+	},
+	{
+		name:      "scapegoat",
+		new:       func() orderedMap { return keyedMap{scapegoat.NewScapegoatTree()} },
+		maxHeight: func(n int) float64 { return math.Log(float64(n))/math.Log(1/scapegoat.DefaultAlpha) + 1 },
+	},
+	{
+		name:      "wbtree",
+		new:       func() orderedMap { return keyedMap{wbtree.NewWBTree()} },
+		maxHeight: func(n int) float64 { return math.Log(float64(n+1)) / math.Log(4.0/3) },
+	},
+}
+
+// op is a single operation applied to a tree. Operations which return a
+// value are checked against want.
+type op struct {
+	name    string
+	key     int64
+	payload interface{}
+	want    interface{}
+}
+
+func upsert(key int64, payload interface{}) op {
+	return op{name: "upsert", key: key, payload: payload}
+}
+
+func del(key int64) op {
+	return op{name: "delete", key: key}
+}
+
+func search(key int64, want interface{}) op {
+	return op{name: "search", key: key, want: want}
+}
+
+func successor(key int64, want interface{}) op {
+	return op{name: "successor", key: key, want: want}
+}
+
+func minimum(want interface{}) op {
+	return op{name: "min", want: want}
+}
+
+func maximum(want interface{}) op {
+	return op{name: "max", want: want}
+}
+
+func heightOf(want int) op {
+	return op{name: "height", want: want}
+}
+
+func rootIsNil() op {
+	return op{name: "root"}
+}
+
+func (o op) apply(t *testing.T, m orderedMap) {
+	t.Helper()
+
+	switch o.name {
+	case "upsert":
+		m.Upsert(o.key, o.payload)
+	case "delete":
+		m.Delete(o.key)
+	case "search":
+		assert.Equal(t, o.want, m.Search(o.key), "search(%d)", o.key)
+	case "successor":
+		assert.Equal(t, o.want, m.Successor(o.key), "successor(%d)", o.key)
+	case "min":
+		assert.Equal(t, o.want, m.Min(), "min")
+	case "max":
+		assert.Equal(t, o.want, m.Max(), "max")
+	case "height":
+		assert.Equal(t, o.want, m.Height(), "height")
+	case "root":
+		assert.Nil(t, m.Root(), "root")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	tt := []struct {
+		name string
+		ops  []op
+	}{
+		{
+			name: "empty tree",
+			ops: []op{
+				search(0, nil),
+				successor(0, nil),
+				minimum(nil),
+				maximum(nil),
+				heightOf(-1),
+				rootIsNil(),
+			},
+		},
+		{
+			name: "delete on empty tree is noop",
+			ops: []op{
+				del(15),
+				heightOf(-1),
+			},
+		},
+		{
+			name: "single key",
+			ops: []op{
+				upsert(15, "15"),
+				search(15, "15"),
+				search(0, nil),
+				successor(15, nil),
+				minimum("15"),
+				maximum("15"),
+				heightOf(0),
+			},
+		},
+		{
+			name: "upsert replaces payload",
+			ops: []op{
+				upsert(1, "test"),
+				upsert(2, "test2"),
+				upsert(1, "test3"),
+				search(1, "test3"),
+				search(2, "test2"),
+			},
+		},
+		{
+			name: "zero and negative keys",
+			ops: []op{
+				upsert(0, 0),
+				upsert(-5, -5),
+				upsert(5, 5),
+				search(0, 0),
+				minimum(-5),
+				maximum(5),
+				successor(-5, 0),
+				successor(0, 5),
+			},
+		},
+		{
+			name: "successor",
+			ops: []op{
+				upsert(15, 15), upsert(6, 6), upsert(18, 18), upsert(3, 3),
+				upsert(7, 7), upsert(17, 17), upsert(20, 20), upsert(2, 2),
+				upsert(4, 4), upsert(13, 13), upsert(9, 9),
+				successor(4, 6),
+				successor(13, 15),
+				successor(15, 17),
+				successor(9, 13),
+				successor(20, nil),
+				successor(5, nil),
+			},
+		},
+		{
+			name: "delete",
+			ops: []op{
+				upsert(15, 15), upsert(6, 6), upsert(18, 18), upsert(3, 3),
+				upsert(7, 7), upsert(17, 17), upsert(20, 20),
+				del(15),
+				search(15, nil),
+				successor(7, 17),
+				del(3),
+				minimum(6),
+				del(20),
+				maximum(18),
+				del(99),
+				search(6, 6),
+				search(7, 7),
+				search(17, 17),
+				search(18, 18),
+			},
+		},
+		{
+			name: "delete all keys",
+			ops: []op{
+				upsert(1, 1), upsert(2, 2), upsert(3, 3),
+				del(2), del(1), del(3),
+				heightOf(-1),
+				rootIsNil(),
+				minimum(nil),
+			},
+		},
+	}
+
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			for _, tc := range tt {
+				t.Run(tc.name, func(t *testing.T) {
+					m := impl.new()
+
+					for _, o := range tc.ops {
+						o.apply(t, m)
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestConformance_random applies the same random operations to all trees and
+// compares them against a map.
+func TestConformance_random(t *testing.T) {
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			var (
+				m    = impl.new()
+				want = make(map[int64]int)
+				rng  = rand.New(rand.NewSource(42))
+			)
+
+			for i := 0; i < 2000; i++ {
+				k := rng.Int63n(200)
+
+				if rng.Intn(3) == 0 {
+					m.Delete(k)
+					delete(want, k)
+				} else {
+					m.Upsert(k, i)
+					want[k] = i
+				}
+			}
+
+			keys := make([]int64, 0, len(want))
+
+			for k := range want {
+				keys = append(keys, k)
+			}
+
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+			require.NotEmpty(t, keys)
+			assert.Equal(t, want[keys[0]], m.Min())
+			assert.Equal(t, want[keys[len(keys)-1]], m.Max())
+
+			for i, k := range keys {
+				assert.Equal(t, want[k], m.Search(k), "search(%d)", k)
+
+				if i+1 < len(keys) {
+					assert.Equal(t, want[keys[i+1]], m.Successor(k), "successor(%d)", k)
+				} else {
+					assert.Nil(t, m.Successor(k), "successor(%d)", k)
+				}
+			}
+
+			for k := int64(0); k < 200; k++ {
+				if _, ok := want[k]; !ok {
+					assert.Nil(t, m.Search(k), "search(%d)", k)
+				}
+			}
+		})
+	}
+}
+
+// TestConformance_heights compares the heights of all trees after inserting
+// ascending, descending and random keys.
+func TestConformance_heights(t *testing.T) {
+	const n = 1023
+
+	orders := map[string]func() []int64{
+		"ascending": func() []int64 {
+			keys := make([]int64, n)
+			for i := range keys {
+				keys[i] = int64(i)
+			}
+
+			return keys
+		},
+		"descending": func() []int64 {
+			keys := make([]int64, n)
+			for i := range keys {
+				keys[i] = int64(n - i)
+			}
+
+			return keys
+		},
+		"random": func() []int64 {
+			keys := make([]int64, n)
+			for i, k := range rand.New(rand.NewSource(42)).Perm(n) {
+				keys[i] = int64(k)
+			}
+
+			return keys
+		},
+	}
+
+	for name, keys := range orders {
+		t.Run(name, func(t *testing.T) {
+			for _, impl := range implementations {
+				m := impl.new()
+
+				for _, k := range keys() {
+					m.Upsert(k, nil)
+				}
+
+				h := m.Height()
+				t.Logf("%-10s height %4d", impl.name, h)
+
+				fanout := impl.fanout
+				if fanout == 0 {
+					fanout = 2
+				}
+
+				// No tree can be lower than a complete one.
+				minHeight := math.Log(n+1)/math.Log(float64(fanout)) - 1
+				assert.GreaterOrEqual(t, float64(h), math.Floor(minHeight+1e-9), impl.name)
+
+				if limit := impl.maxHeight(n); limit >= 0 {
+					assert.LessOrEqual(t, float64(h), limit, impl.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package conformance contains the test suite shared by the ordered tree
+// implementations of this module. It runs identical table tests against every
+// tree to compare their behaviour and heights. The package itself is empty.
+package conformance
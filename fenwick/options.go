@@ -0,0 +1,30 @@
+package fenwick
+
+import "github.com/obitech/go-trees/internal/lock"
+
+// Option configures a Tree or Tree2D on construction.
+type Option func(*config)
+
+type config struct {
+	withoutLocking bool
+}
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(c *config) {
+		c.withoutLocking = true
+	}
+}
+
+// apply configures the lock of a new tree.
+func apply(l *lock.RWMutex, opts []Option) {
+	var c config
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.withoutLocking {
+		l.Disable()
+	}
+}
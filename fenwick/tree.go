@@ -0,0 +1,138 @@
+// Package fenwick implements Fenwick trees, also known as binary indexed
+// trees, which maintain prefix sums over a sequence of int64 values. Updating
+// a value and summing up a prefix both run in O(lg n) time, using only a
+// single slice of n values.
+package fenwick
+
+import (
+	"fmt"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// Tree represents a Fenwick tree over n values, all zero initially, and a lock
+// to protect concurrent access.
+type Tree struct {
+	lock lock.RWMutex
+	// sums is 1-based: sums[i] holds the sum of the values in
+	// [i-lowbit(i), i), lowbit(i) being the lowest set bit of i.
+	sums []int64
+}
+
+// NewFenwickTree returns a new Fenwick tree over n values which are all zero.
+// Unless WithoutLocking is passed, all operations on the tree are safe to be
+// accessed concurrently.
+func NewFenwickTree(n int, opts ...Option) *Tree {
+	if n < 0 {
+		panic("fenwick: negative length")
+	}
+
+	t := &Tree{
+		sums: make([]int64, n+1),
+	}
+
+	apply(&t.lock, opts)
+
+	return t
+}
+
+// Len returns the number of values in the tree.
+func (t *Tree) Len() int {
+	return len(t.sums) - 1
+}
+
+// Add adds delta to the value at index i. Panics if i is out of bounds.
+func (t *Tree) Add(i int, delta int64) {
+	checkIndex(i, t.Len())
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for i++; i < len(t.sums); i += i & -i {
+		t.sums[i] += delta
+	}
+}
+
+// PrefixSum returns the sum of the values in [0, i). Panics if i is out of
+// bounds.
+func (t *Tree) PrefixSum(i int) int64 {
+	checkPrefix(i, t.Len())
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.prefixSum(i)
+}
+
+// RangeSum returns the sum of the values in the half-open range [l, r).
+// Panics if the range is out of bounds.
+func (t *Tree) RangeSum(l, r int) int64 {
+	checkRange(l, r, t.Len())
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.prefixSum(r) - t.prefixSum(l)
+}
+
+// LowerBound returns the lowest index i for which the sum of the values in
+// [0, i] is at least sum, or Len if there is none. All values have to be
+// non-negative. Drawing sum uniformly from [1, PrefixSum(Len)] samples the
+// indices weighted by their values. Runs in O(lg n) time.
+func (t *Tree) LowerBound(sum int64) int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if sum <= 0 {
+		return 0
+	}
+
+	// Descend from the highest power of two, skipping over every block whose
+	// sum is still less than the remaining sum.
+	var (
+		pos  int
+		step = 1
+	)
+
+	for step*2 < len(t.sums) {
+		step *= 2
+	}
+
+	for ; step > 0; step /= 2 {
+		if next := pos + step; next < len(t.sums) && t.sums[next] < sum {
+			pos = next
+			sum -= t.sums[next]
+		}
+	}
+
+	// pos is the length of the longest prefix with a sum less than sum.
+	return pos
+}
+
+func (t *Tree) prefixSum(i int) int64 {
+	var sum int64
+
+	for ; i > 0; i -= i & -i {
+		sum += t.sums[i]
+	}
+
+	return sum
+}
+
+func checkIndex(i, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("fenwick: index %d out of bounds for length %d", i, n))
+	}
+}
+
+func checkPrefix(i, n int) {
+	if i < 0 || i > n {
+		panic(fmt.Sprintf("fenwick: prefix %d out of bounds for length %d", i, n))
+	}
+}
+
+func checkRange(l, r, n int) {
+	if l < 0 || r > n || l > r {
+		panic(fmt.Sprintf("fenwick: range [%d, %d) out of bounds for length %d", l, r, n))
+	}
+}
@@ -0,0 +1,94 @@
+package fenwick
+
+import "github.com/obitech/go-trees/internal/lock"
+
+// Tree2D represents a two-dimensional Fenwick tree over a grid of values, all
+// zero initially, and a lock to protect concurrent access. Updates and sums
+// run in O(lg rows * lg cols) time.
+type Tree2D struct {
+	lock       lock.RWMutex
+	rows, cols int
+	// sums is 1-based in both dimensions, see Tree.
+	sums [][]int64
+}
+
+// NewFenwickTree2D returns a new two-dimensional Fenwick tree over a grid of
+// rows x cols values which are all zero. Unless WithoutLocking is passed, all
+// operations on the tree are safe to be accessed concurrently.
+func NewFenwickTree2D(rows, cols int, opts ...Option) *Tree2D {
+	if rows < 0 || cols < 0 {
+		panic("fenwick: negative dimensions")
+	}
+
+	sums := make([][]int64, rows+1)
+	for i := range sums {
+		sums[i] = make([]int64, cols+1)
+	}
+
+	t := &Tree2D{
+		rows: rows,
+		cols: cols,
+		sums: sums,
+	}
+
+	apply(&t.lock, opts)
+
+	return t
+}
+
+// Dims returns the number of rows and columns of the grid.
+func (t *Tree2D) Dims() (rows, cols int) {
+	return t.rows, t.cols
+}
+
+// Add adds delta to the value at row r and column c. Panics if the position
+// is out of bounds.
+func (t *Tree2D) Add(r, c int, delta int64) {
+	checkIndex(r, t.rows)
+	checkIndex(c, t.cols)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for i := r + 1; i <= t.rows; i += i & -i {
+		for j := c + 1; j <= t.cols; j += j & -j {
+			t.sums[i][j] += delta
+		}
+	}
+}
+
+// PrefixSum returns the sum of the values in rows [0, r) and columns [0, c).
+// Panics if the prefix is out of bounds.
+func (t *Tree2D) PrefixSum(r, c int) int64 {
+	checkPrefix(r, t.rows)
+	checkPrefix(c, t.cols)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.prefixSum(r, c)
+}
+
+// RangeSum returns the sum of the values in rows [r1, r2) and columns
+// [c1, c2). Panics if the ranges are out of bounds.
+func (t *Tree2D) RangeSum(r1, c1, r2, c2 int) int64 {
+	checkRange(r1, r2, t.rows)
+	checkRange(c1, c2, t.cols)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.prefixSum(r2, c2) - t.prefixSum(r1, c2) - t.prefixSum(r2, c1) + t.prefixSum(r1, c1)
+}
+
+func (t *Tree2D) prefixSum(r, c int) int64 {
+	var sum int64
+
+	for i := r; i > 0; i -= i & -i {
+		for j := c; j > 0; j -= j & -j {
+			sum += t.sums[i][j]
+		}
+	}
+
+	return sum
+}
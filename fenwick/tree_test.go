@@ -0,0 +1,234 @@
+package fenwick
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var quickConfig = &quick.Config{
+	MaxCount: 200,
+	Rand:     rand.New(rand.NewSource(1)),
+}
+
+// naive sums up a slice for comparison.
+func naive(values []int64, l, r int) int64 {
+	var sum int64
+
+	for _, v := range values[l:r] {
+		sum += v
+	}
+
+	return sum
+}
+
+func TestTree(t *testing.T) {
+	tree := NewFenwickTree(6)
+
+	for i, v := range []int64{5, 3, 8, 1, 9, 2} {
+		tree.Add(i, v)
+	}
+
+	assert.Equal(t, 6, tree.Len())
+	assert.Equal(t, int64(0), tree.PrefixSum(0))
+	assert.Equal(t, int64(16), tree.PrefixSum(3))
+	assert.Equal(t, int64(28), tree.PrefixSum(6))
+	assert.Equal(t, int64(12), tree.RangeSum(1, 4))
+	assert.Equal(t, int64(0), tree.RangeSum(2, 2))
+
+	tree.Add(2, -8)
+
+	assert.Equal(t, int64(4), tree.RangeSum(1, 4))
+
+	t.Run("lower bound", func(t *testing.T) {
+		// Prefix sums are 5, 8, 8, 9, 18, 20.
+		tt := []struct {
+			sum  int64
+			want int
+		}{
+			{sum: 0, want: 0},
+			{sum: 1, want: 0},
+			{sum: 5, want: 0},
+			{sum: 6, want: 1},
+			{sum: 8, want: 1},
+			{sum: 9, want: 3},
+			{sum: 20, want: 5},
+			{sum: 21, want: 6},
+		}
+
+		for _, tc := range tt {
+			assert.Equal(t, tc.want, tree.LowerBound(tc.sum), "lower bound of %d", tc.sum)
+		}
+	})
+
+	t.Run("empty tree", func(t *testing.T) {
+		tree := NewFenwickTree(0)
+
+		assert.Equal(t, int64(0), tree.PrefixSum(0))
+		assert.Equal(t, 0, tree.LowerBound(1))
+	})
+
+	t.Run("out of bounds panics", func(t *testing.T) {
+		assert.Panics(t, func() { NewFenwickTree(-1) })
+		assert.Panics(t, func() { tree.Add(6, 1) })
+		assert.Panics(t, func() { tree.Add(-1, 1) })
+		assert.Panics(t, func() { tree.PrefixSum(7) })
+		assert.Panics(t, func() { tree.RangeSum(4, 3) })
+	})
+}
+
+func TestTree_quick(t *testing.T) {
+	t.Run("sums match naive implementation", func(t *testing.T) {
+		f := func(values []int16, updates []struct {
+			I uint8
+			D int16
+		}) bool {
+			var (
+				vs   = make([]int64, len(values))
+				tree = NewFenwickTree(len(values))
+			)
+
+			for i, v := range values {
+				vs[i] = int64(v)
+				tree.Add(i, int64(v))
+			}
+
+			for _, u := range updates {
+				if len(vs) == 0 {
+					break
+				}
+
+				i := int(u.I) % len(vs)
+				vs[i] += int64(u.D)
+				tree.Add(i, int64(u.D))
+			}
+
+			for l := 0; l <= len(vs); l++ {
+				if tree.PrefixSum(l) != naive(vs, 0, l) {
+					return false
+				}
+
+				for r := l; r <= len(vs); r++ {
+					if tree.RangeSum(l, r) != naive(vs, l, r) {
+						return false
+					}
+				}
+			}
+
+			return true
+		}
+
+		require.NoError(t, quick.Check(f, quickConfig))
+	})
+
+	t.Run("lower bound matches linear search", func(t *testing.T) {
+		f := func(values []uint8) bool {
+			tree := NewFenwickTree(len(values))
+
+			var total int64
+
+			for i, v := range values {
+				tree.Add(i, int64(v))
+				total += int64(v)
+			}
+
+			for sum := int64(0); sum <= total+1; sum++ {
+				// The first index whose inclusive prefix reaches sum.
+				var (
+					want   = len(values)
+					prefix int64
+				)
+
+				for i, v := range values {
+					if prefix += int64(v); prefix >= sum {
+						want = i
+						break
+					}
+				}
+
+				if tree.LowerBound(sum) != want {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		require.NoError(t, quick.Check(f, quickConfig))
+	})
+}
+
+func TestTree2D(t *testing.T) {
+	tree := NewFenwickTree2D(3, 4)
+
+	// 1 2 3 4
+	// 5 6 7 8
+	// 9 0 1 2
+	for r, row := range [][]int64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 0, 1, 2}} {
+		for c, v := range row {
+			tree.Add(r, c, v)
+		}
+	}
+
+	rows, cols := tree.Dims()
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 4, cols)
+
+	assert.Equal(t, int64(48), tree.PrefixSum(3, 4))
+	assert.Equal(t, int64(14), tree.PrefixSum(2, 2))
+	assert.Equal(t, int64(0), tree.PrefixSum(0, 4))
+	assert.Equal(t, int64(14), tree.RangeSum(1, 1, 3, 3))
+	assert.Equal(t, int64(7), tree.RangeSum(1, 2, 2, 3))
+
+	assert.Panics(t, func() { tree.Add(3, 0, 1) })
+	assert.Panics(t, func() { tree.PrefixSum(0, 5) })
+	assert.Panics(t, func() { tree.RangeSum(2, 0, 1, 1) })
+}
+
+func TestTree2D_quick(t *testing.T) {
+	f := func(rows, cols uint8, updates []struct {
+		R, C uint8
+		D    int16
+	}) bool {
+		var (
+			r, c = int(rows%8) + 1, int(cols%8) + 1
+			tree = NewFenwickTree2D(r, c)
+			grid = make([][]int64, r)
+		)
+
+		for i := range grid {
+			grid[i] = make([]int64, c)
+		}
+
+		for _, u := range updates {
+			i, j := int(u.R)%r, int(u.C)%c
+
+			grid[i][j] += int64(u.D)
+			tree.Add(i, j, int64(u.D))
+		}
+
+		for r1 := 0; r1 <= r; r1++ {
+			for r2 := r1; r2 <= r; r2++ {
+				for c1 := 0; c1 <= c; c1++ {
+					for c2 := c1; c2 <= c; c2++ {
+						var want int64
+						for _, row := range grid[r1:r2] {
+							want += naive(row, c1, c2)
+						}
+
+						if tree.RangeSum(r1, c1, r2, c2) != want {
+							return false
+						}
+					}
+				}
+			}
+		}
+
+		return true
+	}
+
+	require.NoError(t, quick.Check(f, quickConfig))
+}
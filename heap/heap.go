@@ -0,0 +1,227 @@
+// Package heap implements a pairing heap, a mergeable priority queue. Push,
+// Min and Merge run in O(1) time, PopMin and Delete in amortized O(lg n) time.
+// Push returns a handle to the new entry, which allows lowering its key in
+// amortized o(lg n) time.
+package heap
+
+// NewPairingHeap returns a new pairing heap. Unless WithoutLocking is passed,
+// all operations on the heap are safe to be accessed concurrently.
+func NewPairingHeap(opts ...Option) *Heap {
+	h := &Heap{
+		owner: &owner{},
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Len returns the number of entries in the heap.
+func (h *Heap) Len() int {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.size
+}
+
+// Push inserts a new entry and returns its handle.
+func (h *Heap) Push(key Key, payload interface{}) *Element {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	e := &Element{key: key, payload: payload, owner: h.owner}
+
+	h.root = meld(h.root, e)
+	h.size++
+
+	return e
+}
+
+// Min returns the entry with the lowest key. Returns false if the heap is
+// empty.
+func (h *Heap) Min() (Result, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	if h.root == nil {
+		return Result{}, false
+	}
+
+	return h.root.result(), true
+}
+
+// PopMin removes and returns the entry with the lowest key. Returns false if
+// the heap is empty.
+func (h *Heap) PopMin() (Result, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.root == nil {
+		return Result{}, false
+	}
+
+	e := h.root
+	h.remove(e)
+
+	return e.result(), true
+}
+
+// DecreaseKey lowers the key of the passed element. Returns ErrKeyIncreased if
+// key is greater than the current key of the element.
+func (h *Heap) DecreaseKey(e *Element, key Key) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if !h.owns(e) {
+		return ErrNotInHeap
+	}
+
+	if e.key.Less(key) {
+		return ErrKeyIncreased
+	}
+
+	e.key = key
+
+	if e != h.root {
+		cut(e)
+		h.root = meld(h.root, e)
+	}
+
+	return nil
+}
+
+// Delete removes the passed element from the heap.
+func (h *Heap) Delete(e *Element) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if !h.owns(e) {
+		return ErrNotInHeap
+	}
+
+	h.remove(e)
+
+	return nil
+}
+
+// Merge moves all entries of other into h, leaving other empty. Elements of
+// other stay valid and belong to h afterwards. Runs in O(1) time.
+//
+// Merge locks h before other, so concurrently merging the same heaps in
+// opposite order may deadlock.
+func (h *Heap) Merge(other *Heap) {
+	if h == other {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	other.lock.Lock()
+	defer other.lock.Unlock()
+
+	if other.root == nil {
+		return
+	}
+
+	other.owner.parent = h.owner
+
+	h.root = meld(h.root, other.root)
+	h.size += other.size
+
+	other.root, other.size, other.owner = nil, 0, &owner{}
+}
+
+func (h *Heap) owns(e *Element) bool {
+	return e.owner != nil && e.owner.find() == h.owner
+}
+
+// remove unlinks e from the heap and melds its children back in.
+func (h *Heap) remove(e *Element) {
+	if e == h.root {
+		h.root = combine(e.child)
+	} else {
+		cut(e)
+		h.root = meld(h.root, combine(e.child))
+	}
+
+	e.child, e.owner = nil, nil
+	h.size--
+}
+
+func (e *Element) result() Result {
+	return Result{Key: e.key, Payload: e.payload}
+}
+
+// meld links the heaps rooted at a and b, which have neither siblings nor a
+// parent, and returns the new root.
+func meld(a, b *Element) *Element {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case b.key.Less(a.key):
+		a, b = b, a
+	}
+
+	b.prev = a
+	b.next = a.child
+
+	if a.child != nil {
+		a.child.prev = b
+	}
+
+	a.child = b
+
+	return a
+}
+
+// cut unlinks the subtree rooted at e, which isn't the root, from its parent
+// and siblings.
+func cut(e *Element) {
+	if e.prev.child == e {
+		e.prev.child = e.next
+	} else {
+		e.prev.next = e.next
+	}
+
+	if e.next != nil {
+		e.next.prev = e.prev
+	}
+
+	e.prev, e.next = nil, nil
+}
+
+// combine melds the list of siblings starting at first into a single heap and
+// returns its root. The siblings are melded in pairs from left to right, and
+// the pairs from right to left, which keeps the amortized costs logarithmic.
+func combine(first *Element) *Element {
+	var pairs []*Element
+
+	for a := first; a != nil; {
+		b := a.next
+		a.prev, a.next = nil, nil
+
+		if b == nil {
+			pairs = append(pairs, a)
+			break
+		}
+
+		next := b.next
+		b.prev, b.next = nil, nil
+
+		pairs = append(pairs, meld(a, b))
+		a = next
+	}
+
+	var root *Element
+
+	for i := len(pairs) - 1; i >= 0; i-- {
+		root = meld(pairs[i], root)
+	}
+
+	return root
+}
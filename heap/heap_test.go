@@ -0,0 +1,231 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the heap order, the sibling links and the number of entries.
+func verify(t *testing.T, h *Heap) {
+	var walk func(e *Element) int
+
+	walk = func(e *Element) int {
+		n := 1
+
+		for c, prev := e.child, e; c != nil; prev, c = c, c.next {
+			require.Equal(t, prev, c.prev, "broken link to %v", c.key)
+			require.False(t, c.key.Less(e.key), "%v is less than its parent %v", c.key, e.key)
+			require.True(t, h.owns(c))
+
+			n += walk(c)
+		}
+
+		return n
+	}
+
+	if h.root == nil {
+		require.Equal(t, 0, h.size)
+		return
+	}
+
+	require.Nil(t, h.root.prev)
+	require.Nil(t, h.root.next)
+	require.Equal(t, h.size, walk(h.root))
+}
+
+// drain pops all entries and returns their keys.
+func drain(h *Heap) []int {
+	var res []int
+
+	for {
+		r, ok := h.PopMin()
+		if !ok {
+			return res
+		}
+
+		res = append(res, int(r.Key.(myInt)))
+	}
+}
+
+func TestHeap_PopMin(t *testing.T) {
+	h := NewPairingHeap()
+
+	_, ok := h.Min()
+	assert.False(t, ok)
+
+	_, ok = h.PopMin()
+	assert.False(t, ok)
+
+	for _, k := range []int{5, 3, 8, 1, 9, 2, 7, 3} {
+		h.Push(myInt(k), k)
+	}
+
+	verify(t, h)
+	assert.Equal(t, 8, h.Len())
+
+	r, ok := h.Min()
+	require.True(t, ok)
+	assert.Equal(t, Result{Key: myInt(1), Payload: 1}, r)
+
+	assert.Equal(t, []int{1, 2, 3, 3, 5, 7, 8, 9}, drain(h))
+	assert.Equal(t, 0, h.Len())
+}
+
+func TestHeap_DecreaseKey(t *testing.T) {
+	h := NewPairingHeap()
+
+	var elems []*Element
+	for i := 0; i < 10; i++ {
+		elems = append(elems, h.Push(myInt(10*i), i))
+	}
+
+	h.PopMin()
+	verify(t, h)
+
+	require.NoError(t, h.DecreaseKey(elems[7], myInt(5)))
+	verify(t, h)
+
+	r, _ := h.Min()
+	assert.Equal(t, Result{Key: myInt(5), Payload: 7}, r)
+
+	// Decreasing the root and keeping a key are fine as well.
+	require.NoError(t, h.DecreaseKey(elems[7], myInt(4)))
+	require.NoError(t, h.DecreaseKey(elems[3], myInt(30)))
+
+	assert.Equal(t, ErrKeyIncreased, h.DecreaseKey(elems[3], myInt(31)))
+	assert.Equal(t, ErrNotInHeap, h.DecreaseKey(elems[0], myInt(0)))
+
+	verify(t, h)
+	assert.Equal(t, []int{4, 10, 20, 30, 40, 50, 60, 80, 90}, drain(h))
+}
+
+func TestHeap_Delete(t *testing.T) {
+	h := NewPairingHeap()
+
+	var elems []*Element
+	for i := 0; i < 10; i++ {
+		elems = append(elems, h.Push(myInt(i), i))
+	}
+
+	h.PopMin()
+	h.Push(myInt(10), 10)
+
+	require.NoError(t, h.Delete(elems[5]))
+	require.NoError(t, h.Delete(elems[1]))
+	verify(t, h)
+
+	assert.Equal(t, ErrNotInHeap, h.Delete(elems[5]))
+	assert.Equal(t, ErrNotInHeap, h.Delete(elems[0]))
+	assert.Equal(t, ErrNotInHeap, NewPairingHeap().Delete(elems[2]))
+
+	assert.Equal(t, 8, h.Len())
+	assert.Equal(t, []int{2, 3, 4, 6, 7, 8, 9, 10}, drain(h))
+}
+
+func TestHeap_Merge(t *testing.T) {
+	var (
+		a = NewPairingHeap()
+		b = NewPairingHeap()
+		c = NewPairingHeap()
+	)
+
+	a.Push(myInt(4), nil)
+	a.Push(myInt(1), nil)
+
+	e := b.Push(myInt(5), nil)
+	b.Push(myInt(2), nil)
+
+	c.Push(myInt(3), nil)
+
+	a.Merge(a)
+	a.Merge(NewPairingHeap())
+	b.Merge(a)
+	c.Merge(b)
+
+	verify(t, c)
+	assert.Equal(t, 0, a.Len())
+	assert.Equal(t, 0, b.Len())
+	assert.Equal(t, 5, c.Len())
+
+	// Handles follow their entries into the merged heap.
+	assert.Equal(t, ErrNotInHeap, b.DecreaseKey(e, myInt(0)))
+	require.NoError(t, c.DecreaseKey(e, myInt(0)))
+
+	// Merged heaps can be reused.
+	f := b.Push(myInt(9), nil)
+	assert.Equal(t, ErrNotInHeap, c.Delete(f))
+	require.NoError(t, b.Delete(f))
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, drain(c))
+}
+
+func TestHeap_random(t *testing.T) {
+	var (
+		rng   = rand.New(rand.NewSource(1))
+		h     = NewPairingHeap(WithoutLocking())
+		elems = make(map[*Element]int)
+	)
+
+	for i := 0; i < 5000; i++ {
+		switch op := rng.Intn(10); {
+		case op < 4:
+			k := rng.Intn(1000)
+			elems[h.Push(myInt(k), nil)] = k
+		case op < 6 && len(elems) > 0:
+			r, ok := h.PopMin()
+			require.True(t, ok)
+
+			for e, k := range elems {
+				require.LessOrEqual(t, int(r.Key.(myInt)), k)
+
+				if e.owner == nil {
+					delete(elems, e)
+				}
+			}
+		default:
+			for e, k := range elems {
+				if op < 8 {
+					k -= rng.Intn(100)
+					require.NoError(t, h.DecreaseKey(e, myInt(k)))
+					elems[e] = k
+				} else {
+					require.NoError(t, h.Delete(e))
+					delete(elems, e)
+				}
+
+				break
+			}
+		}
+
+		if i%100 == 0 {
+			verify(t, h)
+		}
+	}
+
+	verify(t, h)
+	require.Equal(t, len(elems), h.Len())
+
+	want := make([]int, 0, len(elems))
+	for _, k := range elems {
+		want = append(want, k)
+	}
+
+	sort.Ints(want)
+	require.Equal(t, want, drain(h))
+}
+
+func TestNewPairingHeap_WithoutLocking(t *testing.T) {
+	h := NewPairingHeap(WithoutLocking())
+
+	assert.True(t, h.lock.Disabled())
+}
@@ -0,0 +1,11 @@
+package heap
+
+// Option configures a Heap on construction.
+type Option func(*Heap)
+
+// WithoutLocking disables the internal lock of the heap.
+func WithoutLocking() Option {
+	return func(h *Heap) {
+		h.lock.Disable()
+	}
+}
@@ -0,0 +1,68 @@
+package heap
+
+import (
+	"errors"
+
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+var (
+	// ErrNotInHeap is returned when passing an element which has been
+	// removed from the heap, or which belongs to another heap.
+	ErrNotInHeap = errors.New("heap: element is not in the heap")
+
+	// ErrKeyIncreased is returned by DecreaseKey if the new key is greater
+	// than the current one.
+	ErrKeyIncreased = errors.New("heap: new key is greater than the current key")
+)
+
+// Key is the interface keys of the heap have to implement. It is shared with
+// package redblack, so the same key types work with both.
+type Key = redblack.Key
+
+// Result is an entry of the heap.
+type Result = redblack.Result
+
+// Heap represents a pairing heap with a root element and a lock to protect
+// concurrent access.
+type Heap struct {
+	lock  lock.RWMutex
+	root  *Element
+	size  int
+	owner *owner
+}
+
+// Element is a handle to an entry of a heap, returned by Push. It stays valid
+// until the entry is removed, also when its heap is merged into another one.
+type Element struct {
+	key     Key
+	payload interface{}
+	owner   *owner
+
+	// child is the first child of the element, next its next sibling. prev
+	// is the previous sibling, or the parent for the first child.
+	child *Element
+	next  *Element
+	prev  *Element
+}
+
+// owner identifies the heap of an element. Merging a heap links its owner to
+// the one of the other heap, so its elements don't have to be updated.
+type owner struct {
+	parent *owner
+}
+
+// find returns the owner of the heap the element belongs to now, compressing
+// the path on the way.
+func (o *owner) find() *owner {
+	for o.parent != nil {
+		if o.parent.parent != nil {
+			o.parent = o.parent.parent
+		}
+
+		o = o.parent
+	}
+
+	return o
+}
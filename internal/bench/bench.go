@@ -0,0 +1,114 @@
+// Package bench runs the workloads of the redblack benchmarks against several
+// ordered maps, so new structures can be compared against package redblack.
+// All keys are drawn from seeded sources, which makes runs reproducible.
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Map is the surface shared by all compared structures.
+type Map interface {
+	Upsert(key redblack.Key, payload interface{})
+	Search(key redblack.Key) interface{}
+	Delete(key redblack.Key)
+}
+
+// Impl is a structure to benchmark.
+type Impl struct {
+	Name string
+	New  func() Map
+}
+
+// RedBlack is the baseline all structures are compared against.
+var RedBlack = Impl{
+	Name: "redblack",
+	New:  func() Map { return redblack.NewRedBlackTree() },
+}
+
+// Key is the key type used by all benchmarks.
+type Key int64
+
+// Less implements redblack.Key.
+func (k Key) Less(v redblack.Key) bool {
+	return k < v.(Key)
+}
+
+// sizes are the numbers of keys the maps are filled with for searches and
+// deletions.
+var sizes = []int64{10_000, 100_000, 1_000_000}
+
+var result interface{}
+
+// Upsert benchmarks inserting random keys into a growing map.
+func Upsert(b *testing.B, impls ...Impl) {
+	for _, impl := range impls {
+		b.Run(impl.Name, func(b *testing.B) {
+			var (
+				rng = rand.New(rand.NewSource(1))
+				m   = impl.New()
+			)
+
+			for n := 1; n <= b.N; n++ {
+				m.Upsert(Key(rng.Int63n(int64(n))), nil)
+			}
+		})
+	}
+}
+
+// Search benchmarks looking up random keys, half of which exist.
+func Search(b *testing.B, impls ...Impl) {
+	for _, size := range sizes {
+		for _, impl := range impls {
+			m := fill(impl, size)
+
+			b.Run(fmt.Sprintf("%s/keys=%d", impl.Name, size), func(b *testing.B) {
+				var (
+					rng = rand.New(rand.NewSource(2))
+					r   interface{}
+				)
+
+				for n := 0; n < b.N; n++ {
+					r = m.Search(Key(rng.Int63n(size)))
+				}
+
+				result = r
+			})
+		}
+	}
+}
+
+// Delete benchmarks deleting random keys until the map is mostly empty.
+func Delete(b *testing.B, impls ...Impl) {
+	for _, size := range sizes {
+		for _, impl := range impls {
+			m := fill(impl, size)
+
+			b.Run(fmt.Sprintf("%s/keys=%d", impl.Name, size), func(b *testing.B) {
+				rng := rand.New(rand.NewSource(2))
+
+				for n := 0; n < b.N; n++ {
+					m.Delete(Key(rng.Int63n(size)))
+				}
+			})
+		}
+	}
+}
+
+// fill returns a new map holding size random keys from [0, size).
+func fill(impl Impl, size int64) Map {
+	var (
+		rng = rand.New(rand.NewSource(1))
+		m   = impl.New()
+	)
+
+	for i := int64(1); i <= size; i++ {
+		m.Upsert(Key(rng.Int63n(size)), i)
+	}
+
+	return m
+}
@@ -0,0 +1,52 @@
+// Package lock provides the lock guarding the data structures of this module,
+// which can be disabled when locking is left to the caller.
+package lock
+
+import "sync"
+
+// RWMutex is a sync.RWMutex which can be disabled. Its zero value is an
+// unlocked, enabled mutex, so structures holding one are safe for concurrent
+// use without a constructor.
+type RWMutex struct {
+	mu       sync.RWMutex
+	disabled bool
+}
+
+// Disable turns all further operations into no-ops. It has to be called before
+// the mutex is used.
+func (m *RWMutex) Disable() {
+	m.disabled = true
+}
+
+// Disabled returns true if the mutex has been disabled.
+func (m *RWMutex) Disabled() bool {
+	return m.disabled
+}
+
+// Lock locks m for writing.
+func (m *RWMutex) Lock() {
+	if !m.disabled {
+		m.mu.Lock()
+	}
+}
+
+// Unlock unlocks m for writing.
+func (m *RWMutex) Unlock() {
+	if !m.disabled {
+		m.mu.Unlock()
+	}
+}
+
+// RLock locks m for reading.
+func (m *RWMutex) RLock() {
+	if !m.disabled {
+		m.mu.RLock()
+	}
+}
+
+// RUnlock undoes a single RLock call.
+func (m *RWMutex) RUnlock() {
+	if !m.disabled {
+		m.mu.RUnlock()
+	}
+}
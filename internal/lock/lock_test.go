@@ -0,0 +1,46 @@
+package lock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRWMutex(t *testing.T) {
+	t.Run("zero value excludes readers while locked", func(t *testing.T) {
+		var (
+			m        RWMutex
+			acquired = make(chan struct{})
+		)
+
+		m.Lock()
+
+		go func() {
+			m.RLock()
+			close(acquired)
+			m.RUnlock()
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("read lock acquired while locked for writing")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		m.Unlock()
+		<-acquired
+	})
+
+	t.Run("disabled mutex doesn't lock", func(t *testing.T) {
+		var m RWMutex
+
+		m.Disable()
+		assert.True(t, m.Disabled())
+
+		m.Lock()
+		m.Lock()
+		m.RLock()
+		m.Unlock()
+	})
+}
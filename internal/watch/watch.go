@@ -0,0 +1,178 @@
+// Package watch fans out the change events of a data structure to its
+// watchers. Events are queued per watcher without limit, so slow watchers
+// never block writers.
+package watch
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// EventType describes the kind of modification reported by an Event.
+type EventType int
+
+const (
+	// EventInsert is emitted when a new key is added.
+	EventInsert EventType = iota
+	// EventUpdate is emitted when the payload of an existing key is replaced.
+	EventUpdate
+	// EventDelete is emitted when a key is removed.
+	EventDelete
+)
+
+func (e EventType) String() string {
+	switch e {
+	case EventInsert:
+		return "insert"
+	case EventUpdate:
+		return "update"
+	case EventDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a single modification of a data structure. Key holds the
+// key type of the structure, e.g. a redblack.Key or an interval.Interval. Old
+// is nil for inserts, New is nil for deletes.
+type Event struct {
+	Type EventType
+	Key  interface{}
+	Old  interface{}
+	New  interface{}
+}
+
+// Hub keeps track of all active watchers of a data structure. The zero value
+// is ready to use.
+type Hub struct {
+	mu   sync.Mutex
+	n    int32
+	list []*watcher
+}
+
+// Watch returns a channel which receives all events for which match returns
+// true. A nil match accepts all events. The channel is closed once ctx is
+// done.
+func (h *Hub) Watch(ctx context.Context, match func(e Event) bool) <-chan Event {
+	var (
+		out = make(chan Event)
+		w   = h.add(match)
+	)
+
+	go func() {
+		defer close(out)
+		defer h.remove(w)
+
+		for {
+			e, ok := w.next(ctx)
+			if !ok {
+				return
+			}
+
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Notify queues e for all watchers accepting it. Without watchers, it returns
+// without taking a lock.
+func (h *Hub) Notify(e Event) {
+	if atomic.LoadInt32(&h.n) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, w := range h.list {
+		if w.match == nil || w.match(e) {
+			w.push(e)
+		}
+	}
+}
+
+// Len returns the number of active watchers.
+func (h *Hub) Len() int {
+	return int(atomic.LoadInt32(&h.n))
+}
+
+func (h *Hub) add(match func(e Event) bool) *watcher {
+	w := &watcher{
+		match: match,
+		wake:  make(chan struct{}, 1),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.list = append(h.list, w)
+	atomic.StoreInt32(&h.n, int32(len(h.list)))
+
+	return w
+}
+
+func (h *Hub) remove(w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, x := range h.list {
+		if x == w {
+			h.list = append(h.list[:i], h.list[i+1:]...)
+			break
+		}
+	}
+
+	atomic.StoreInt32(&h.n, int32(len(h.list)))
+}
+
+// watcher is a queue of events for a single watcher.
+type watcher struct {
+	match func(e Event) bool
+	mu    sync.Mutex
+	queue []Event
+	wake  chan struct{}
+}
+
+// next returns the oldest queued event, waiting for one if the queue is
+// empty. Returns false once ctx is done.
+func (w *watcher) next(ctx context.Context) (Event, bool) {
+	for {
+		w.mu.Lock()
+
+		if len(w.queue) > 0 {
+			e := w.queue[0]
+			w.queue[0] = Event{}
+			w.queue = w.queue[1:]
+			w.mu.Unlock()
+
+			return e, true
+		}
+
+		w.mu.Unlock()
+
+		select {
+		case <-w.wake:
+		case <-ctx.Done():
+			return Event{}, false
+		}
+	}
+}
+
+func (w *watcher) push(e Event) {
+	w.mu.Lock()
+	w.queue = append(w.queue, e)
+	w.mu.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
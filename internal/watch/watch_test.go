@@ -0,0 +1,61 @@
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub(t *testing.T) {
+	var h Hub
+
+	// Nobody is watching yet.
+	h.Notify(Event{Key: -1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	all := h.Watch(ctx, nil)
+	even := h.Watch(ctx, func(e Event) bool { return e.Key.(int)%2 == 0 })
+
+	assert.Equal(t, 2, h.Len())
+
+	for i := 0; i < 5; i++ {
+		h.Notify(Event{Type: EventInsert, Key: i})
+	}
+
+	for _, tc := range []struct {
+		ch   <-chan Event
+		want []int
+	}{
+		{ch: all, want: []int{0, 1, 2, 3, 4}},
+		{ch: even, want: []int{0, 2, 4}},
+	} {
+		for _, want := range tc.want {
+			select {
+			case e := <-tc.ch:
+				assert.Equal(t, Event{Type: EventInsert, Key: want}, e)
+			case <-time.After(time.Second):
+				require.FailNow(t, "timed out waiting for event", "key %d", want)
+			}
+		}
+	}
+
+	cancel()
+
+	for _, ch := range []<-chan Event{all, even} {
+		for range ch {
+		}
+	}
+
+	assert.Zero(t, h.Len())
+}
+
+func TestEventType_String(t *testing.T) {
+	assert.Equal(t, "insert", EventInsert.String())
+	assert.Equal(t, "update", EventUpdate.String())
+	assert.Equal(t, "delete", EventDelete.String())
+	assert.Equal(t, "unknown", EventType(-1).String())
+}
@@ -0,0 +1,124 @@
+package interval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+// intervalVersion is the version of the binary format written by
+// Interval.MarshalBinary:
+//
+//	version (uvarint) | 2 × (time (bytes) | location name (bytes))
+//
+// Times are encoded with time.Time.MarshalBinary, which only retains the zone
+// offset. The location name is stored alongside, so named locations survive
+// a round trip if they can be loaded on the decoding side.
+const intervalVersion = 1
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (i Interval) MarshalBinary() ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		e   = codec.NewEncoder(&buf)
+	)
+
+	e.WriteUvarint(intervalVersion)
+
+	for _, t := range []time.Time{i.low, i.high} {
+		b, err := t.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		e.WriteBytes(b)
+		e.WriteBytes([]byte(locationName(t)))
+	}
+
+	if _, err := e.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Like NewInterval, it
+// returns an error if end is before start.
+func (i *Interval) UnmarshalBinary(data []byte) error {
+	d := codec.NewDecoder(bytes.NewReader(data))
+
+	if v := d.ReadUvarint(); d.Err() == nil && v != intervalVersion {
+		return fmt.Errorf("interval: unsupported binary version %d", v)
+	}
+
+	var bounds [2]time.Time
+
+	for j := range bounds {
+		b, name := d.ReadBytes(), d.ReadBytes()
+		if d.Err() != nil {
+			return d.Err()
+		}
+
+		if err := bounds[j].UnmarshalBinary(b); err != nil {
+			return err
+		}
+
+		bounds[j] = withLocation(bounds[j], string(name))
+	}
+
+	if d.Count() != int64(len(data)) {
+		return errors.New("interval: trailing data after binary interval")
+	}
+
+	x, err := NewInterval(bounds[0], bounds[1])
+	if err != nil {
+		return err
+	}
+
+	*i = x
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (i Interval) GobEncode() ([]byte, error) {
+	return i.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder.
+func (i *Interval) GobDecode(data []byte) error {
+	return i.UnmarshalBinary(data)
+}
+
+// locationName returns the name of t's location worth preserving. UTC and the
+// local time zone are restored by time.Time.UnmarshalBinary already.
+func locationName(t time.Time) string {
+	switch loc := t.Location(); loc {
+	case time.UTC, time.Local:
+		return ""
+	default:
+		return loc.String()
+	}
+}
+
+// withLocation moves t into the location with the given name, provided it has
+// the same offset as t at that instant. Locations which can't be loaded, like
+// those created by time.FixedZone, are recreated as fixed zones.
+func withLocation(t time.Time, name string) time.Time {
+	if name == "" {
+		return t
+	}
+
+	_, offset := t.Zone()
+
+	if loc, err := time.LoadLocation(name); err == nil {
+		if _, o := t.In(loc).Zone(); o == offset {
+			return t.In(loc)
+		}
+	}
+
+	return t.In(time.FixedZone(name, offset))
+}
@@ -0,0 +1,131 @@
+package interval
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterval_MarshalBinary(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Logf("time zone database not available, skipping named location: %s", err)
+	}
+
+	tt := []struct {
+		name  string
+		start time.Time
+	}{
+		{name: "UTC", start: time.Date(2020, time.November, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "fixed zone with name", start: time.Date(2020, time.November, 1, 12, 0, 0, 0, time.FixedZone("ACST", 34200))},
+		{name: "fixed zone without name", start: time.Date(2020, time.November, 1, 12, 0, 0, 0, time.FixedZone("", -3*3600))},
+		{name: "local", start: time.Date(2020, time.November, 1, 12, 0, 0, 0, time.Local)},
+		{name: "monotonic clock", start: time.Now()},
+	}
+
+	if berlin != nil {
+		tt = append(tt, struct {
+			name  string
+			start time.Time
+		}{name: "named location", start: time.Date(2020, time.March, 28, 12, 0, 0, 0, berlin)})
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			// The interval spans a DST change for the named location.
+			want, err := NewInterval(tc.start, tc.start.Add(48*time.Hour))
+			require.NoError(t, err)
+
+			b, err := want.MarshalBinary()
+			require.NoError(t, err)
+
+			var got Interval
+
+			require.NoError(t, got.UnmarshalBinary(b))
+
+			assertSameInterval(t, want, got)
+
+			// Monotonic clock readings are stripped consistently.
+			assert.Equal(t, want.low.String(), got.low.String())
+			assert.Equal(t, want.high.String(), got.high.String())
+		})
+	}
+}
+
+func TestInterval_GobEncode(t *testing.T) {
+	start := time.Date(2020, time.November, 1, 12, 0, 0, 0, time.FixedZone("ACST", 34200))
+
+	want, err := NewInterval(start, start.Add(time.Hour))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	require.NoError(t, gob.NewEncoder(&buf).Encode(Result{Interval: want, Payload: "foo"}))
+
+	var got Result
+
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&got))
+
+	assertSameInterval(t, want, got.Interval)
+	assert.Equal(t, "foo", got.Payload)
+}
+
+func TestInterval_UnmarshalBinary(t *testing.T) {
+	valid, err := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Nov-02"))
+	require.NoError(t, err)
+
+	b, err := valid.MarshalBinary()
+	require.NoError(t, err)
+
+	inverted, err := Interval{low: valid.high, high: valid.low}.MarshalBinary()
+	require.NoError(t, err)
+
+	tt := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "unknown version", data: append([]byte{2}, b[1:]...)},
+		{name: "truncated", data: b[:len(b)-1]},
+		{name: "trailing data", data: append(append([]byte(nil), b...), 0)},
+		{name: "end before start", data: inverted},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var i Interval
+
+			assert.Error(t, i.UnmarshalBinary(tc.data))
+			assert.Equal(t, Interval{}, i)
+		})
+	}
+}
+
+func TestNewInterval_stripsMonotonicClock(t *testing.T) {
+	now := time.Now()
+
+	i, err := NewInterval(now, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, now.Round(0), i.Start())
+	assert.Equal(t, now.Round(0), i.Stop())
+}
+
+func assertSameInterval(t *testing.T, want, got Interval) {
+	t.Helper()
+
+	for _, p := range [][2]time.Time{{want.low, got.low}, {want.high, got.high}} {
+		assert.True(t, p[0].Equal(p[1]), "%s != %s", p[0], p[1])
+		assert.Equal(t, p[0].Location().String(), p[1].Location().String())
+
+		wantName, wantOffset := p[0].Zone()
+		gotName, gotOffset := p[1].Zone()
+
+		assert.Equal(t, wantName, gotName)
+		assert.Equal(t, wantOffset, gotOffset)
+	}
+}
@@ -11,6 +11,8 @@ func (t *Tree) Delete(key Interval) {
 
 	if n := t.findExact(key); n != nil {
 		t.delete(n)
+
+		t.watchers.Notify(Event{Type: EventDelete, Key: n.key, Old: n.payload})
 	}
 }
 
@@ -55,7 +57,9 @@ func (t *Tree) delete(z *node) {
 		y.color = z.color
 	}
 
-	t.recalcMax(x)
+	// The subtree of x is unchanged, but x may be the sentinel, whose parent
+	// is where the tree changed.
+	t.recalcMax(x.parent)
 
 	if yOriginalColor == black {
 		t.fixupDelete(x)
@@ -0,0 +1,116 @@
+package interval
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+func TestIntervalTree_MarshalBinary(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 100} {
+		tree := NewIntervalTree()
+		start := newTime(t, "2020-Jan-01")
+
+		for i := 0; i < n; i++ {
+			// Every other interval is long, to exercise max propagation.
+			d := 24 * time.Hour
+			if i%2 == 0 {
+				d *= time.Duration(n)
+			}
+
+			key, err := NewInterval(start.Add(time.Duration(i)*time.Hour), start.Add(time.Duration(i)*time.Hour+d))
+			require.NoError(t, err)
+
+			tree.Upsert(key, i)
+		}
+
+		b, err := tree.MarshalBinary()
+		require.NoError(t, err)
+
+		got := NewIntervalTree()
+		require.NoError(t, got.UnmarshalBinary(b))
+
+		want := tree.InOrder()
+		require.Len(t, got.InOrder(), len(want))
+
+		for i, r := range got.InOrder() {
+			assert.True(t, want[i].Interval.low.Equal(r.Interval.low))
+			assert.True(t, want[i].Interval.high.Equal(r.Interval.high))
+			assert.Equal(t, want[i].Payload, r.Payload)
+		}
+
+		if n > 0 {
+			assert.True(t, tree.root.max.Equal(got.root.max), "n=%d", n)
+
+			q, err := NewInterval(start.Add(time.Duration(n)*time.Hour), start.Add(time.Duration(n)*time.Hour))
+			require.NoError(t, err)
+
+			wantOverlapping, _ := tree.FindAllOverlapping(q)
+			gotOverlapping, _ := got.FindAllOverlapping(q)
+
+			assert.Equal(t, len(wantOverlapping), len(gotOverlapping), "n=%d", n)
+		}
+	}
+}
+
+func TestIntervalTree_ReadFrom(t *testing.T) {
+	t.Run("replaces existing entries", func(t *testing.T) {
+		nov, _ := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Nov-02"))
+		feb, _ := NewInterval(newTime(t, "2020-Feb-01"), newTime(t, "2020-Feb-02"))
+
+		src := NewIntervalTree()
+		src.Upsert(nov, "Nov")
+
+		var buf bytes.Buffer
+
+		n, err := src.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		dst := NewIntervalTree()
+		dst.Upsert(feb, "Feb")
+
+		read, err := dst.ReadFrom(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, n, read)
+
+		r, err := dst.FindExact(nov)
+		require.NoError(t, err)
+		assert.Equal(t, "Nov", r.Payload)
+
+		_, err = dst.FindExact(feb)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid magic number returns error", func(t *testing.T) {
+		assert.Equal(t, codec.ErrInvalidMagic, NewIntervalTree().UnmarshalBinary([]byte("RBTS\x01\x00")))
+	})
+
+	t.Run("inverted interval returns error", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		// NewInterval rejects inverted intervals, so the key is built
+		// directly.
+		b, err := Interval{low: newTime(t, "2020-Nov-02"), high: newTime(t, "2020-Nov-01")}.MarshalBinary()
+		require.NoError(t, err)
+
+		enc := codec.NewEncoder(&buf)
+		enc.WriteHeader(snapshotMagic, snapshotVersion)
+		enc.WriteUvarint(1)
+		enc.WriteBytes(b)
+		enc.WriteValue(codec.Gob{}, "inverted")
+		_, err = enc.Flush()
+		require.NoError(t, err)
+
+		assert.Error(t, NewIntervalTree().UnmarshalBinary(buf.Bytes()))
+	})
+
+	t.Run("unknown version returns error", func(t *testing.T) {
+		assert.Error(t, NewIntervalTree().UnmarshalBinary([]byte("IVTS\x02\x00")))
+	})
+}
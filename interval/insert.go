@@ -6,16 +6,15 @@ func (t *Tree) Upsert(key Interval, payload interface{}) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	if t.root == t.sentinel {
-		t.insert(t.newLeaf(key, payload))
-
-		return
-	}
-
 	if n := t.findExact(key); n != nil {
+		old := n.payload
 		n.payload = payload
+
+		t.watchers.Notify(Event{Type: EventUpdate, Key: key, Old: old, New: payload})
 	} else {
 		t.insert(t.newLeaf(key, payload))
+
+		t.watchers.Notify(Event{Type: EventInsert, Key: key, New: payload})
 	}
 }
 
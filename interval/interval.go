@@ -2,7 +2,6 @@ package interval
 
 import (
 	"errors"
-	"fmt"
 	"time"
 )
 
@@ -13,14 +12,17 @@ type Interval struct {
 }
 
 // NewInterval returns a new Interval or an error if end is before start.
+// Monotonic clock readings are stripped from start and end, so intervals
+// compare equal regardless of how their times were obtained or whether they
+// were decoded.
 func NewInterval(start, end time.Time) (Interval, error) {
 	if end.Before(start) {
 		return Interval{}, errors.New("start must be before end")
 	}
 
 	return Interval{
-		low:  start,
-		high: end,
+		low:  start.Round(0),
+		high: end.Round(0),
 	}, nil
 }
 
@@ -34,8 +36,15 @@ func (i Interval) Stop() time.Time {
 	return i.high
 }
 
+// less orders intervals by their start and then by their end. Like equal, it
+// compares instants, regardless of the zones of the times.
 func (i Interval) less(x Interval) bool {
-	return i.low.Before(x.low) || i.low == x.low && i.high.Before(x.high)
+	return i.low.Before(x.low) || i.low.Equal(x.low) && i.high.Before(x.high)
+}
+
+// equal reports whether two intervals are the same key of a tree.
+func (i Interval) equal(x Interval) bool {
+	return i.low.Equal(x.low) && i.high.Equal(x.high)
 }
 
 func (i Interval) overlaps(x Interval) bool {
@@ -46,8 +55,10 @@ func (i Interval) intersects(t time.Time) bool {
 	return (i.low.Equal(t) || i.low.Before(t)) && (i.high.Equal(t) || i.high.After(t))
 }
 
+// String returns the interval in the ISO 8601 "start/end" format with RFC 3339
+// timestamps, which can be parsed by ParseInterval.
 func (i Interval) String() string {
-	return fmt.Sprintf("{start: %s, end: %s}", i.low, i.high)
+	return i.low.Format(time.RFC3339Nano) + "/" + i.high.Format(time.RFC3339Nano)
 }
 
 func greaterOrEqual(t1, t2 time.Time) bool {
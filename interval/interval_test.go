@@ -156,6 +156,21 @@ func TestInterval_less(t *testing.T) {
 				high: newTime(t, "2020-Jan-04"),
 			},
 		},
+
+		// x |--|
+		// y |---|
+		{
+			name: "x less y: same low in another zone, x high less y returns true",
+			x: Interval{
+				low:  newTime(t, "2020-Jan-01").In(time.FixedZone("", 7200)),
+				high: newTime(t, "2020-Jan-03"),
+			},
+			y: Interval{
+				low:  newTime(t, "2020-Jan-01"),
+				high: newTime(t, "2020-Jan-04"),
+			},
+			want: true,
+		},
 	}
 
 	for _, tc := range tt {
@@ -0,0 +1,437 @@
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is an ISO 8601 duration such as "P1Y2M3DT4H5M6.5S" or "P2W".
+// Calendar components are kept separately from the clock component, as their
+// length depends on the point in time they are applied to.
+type Duration struct {
+	Years  int
+	Months int
+	Weeks  int
+	Days   int
+	Clock  time.Duration
+}
+
+// timeLayouts are the ISO 8601 date and time representations accepted by
+// ParseInterval, in extended and basic format. Timestamps without a zone
+// designator are interpreted as UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15",
+	"2006-01-02",
+	"20060102T150405Z0700",
+	"20060102T1504Z0700",
+	"20060102T150405",
+	"20060102T1504",
+	"20060102",
+}
+
+// clockLayouts are the time of day representations accepted in an
+// abbreviated end of an interval, in extended and basic format. Fractional
+// seconds are accepted after the seconds of each layout.
+var clockLayouts = []string{
+	"15:04:05Z07:00",
+	"15:04Z07:00",
+	"15Z07:00",
+	"15:04:05",
+	"15:04",
+	"15",
+	"150405Z0700",
+	"1504Z0700",
+	"150405",
+	"1504",
+}
+
+// ParseInterval parses an ISO 8601 time interval in one of the forms
+// start/end, start/duration or duration/end, e.g.
+//
+//	2020-11-01T00:00Z/2020-11-02T00:00Z
+//	2020-11-01T00:00Z/P1D
+//	P1DT12H/2020-11-02T00:00Z
+//
+// Both "/" and "--" are accepted as separator. The end of a start/end
+// interval may omit leading components, which are then taken from the start,
+// e.g. "2020-11-01T09:00Z/17:00Z". Without a zone designator, such an end is
+// in the zone of the start. The result of Interval.String can always be
+// parsed.
+func ParseInterval(s string) (Interval, error) {
+	start, end, ok := cut(s, "/")
+	if !ok {
+		if start, end, ok = cut(s, "--"); !ok {
+			return Interval{}, fmt.Errorf("interval: %q is missing a separator", s)
+		}
+	}
+
+	var (
+		low, high time.Time
+		err       error
+	)
+
+	switch {
+	case isDuration(start) && isDuration(end):
+		return Interval{}, fmt.Errorf("interval: %q consists of two durations", s)
+	case isDuration(start):
+		if high, err = parseTime(end); err != nil {
+			return Interval{}, err
+		}
+
+		d, err := ParseDuration(start)
+		if err != nil {
+			return Interval{}, err
+		}
+
+		low = d.SubFrom(high)
+	case isDuration(end):
+		if low, err = parseTime(start); err != nil {
+			return Interval{}, err
+		}
+
+		d, err := ParseDuration(end)
+		if err != nil {
+			return Interval{}, err
+		}
+
+		high = d.AddTo(low)
+	default:
+		if low, err = parseTime(start); err != nil {
+			return Interval{}, err
+		}
+
+		if high, err = parseTime(end); err != nil {
+			if high, err = completeEnd(low, strings.Contains(start, "T"), end); err != nil {
+				return Interval{}, err
+			}
+		}
+	}
+
+	return NewInterval(low, high)
+}
+
+// ParseDuration parses an ISO 8601 duration of the form PnYnMnWnDTnHnMnS.
+// Components which are zero may be omitted, but at least one has to be
+// present. Only hours, minutes and seconds may have a decimal fraction.
+func ParseDuration(s string) (Duration, error) {
+	var (
+		d       Duration
+		rest    = s
+		inClock bool
+		seen    bool
+		order   = "YMWD"
+	)
+
+	if !strings.HasPrefix(rest, "P") {
+		return Duration{}, fmt.Errorf("interval: duration %q doesn't start with P", s)
+	}
+
+	rest = rest[1:]
+
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inClock {
+				return Duration{}, fmt.Errorf("interval: duration %q has multiple time designators", s)
+			}
+
+			inClock = true
+			order = "HMS"
+			rest = rest[1:]
+
+			if rest == "" {
+				return Duration{}, fmt.Errorf("interval: duration %q has an empty time part", s)
+			}
+
+			continue
+		}
+
+		i := strings.IndexFunc(rest, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if i <= 0 {
+			return Duration{}, fmt.Errorf("interval: invalid duration %q", s)
+		}
+
+		var (
+			num  = strings.Replace(rest[:i], ",", ".", 1)
+			unit = rest[i]
+		)
+
+		rest = rest[i+1:]
+
+		// Components have to appear in order and at most once.
+		pos := strings.IndexByte(order, unit)
+		if pos < 0 {
+			return Duration{}, fmt.Errorf("interval: unexpected designator %q in duration %q", unit, s)
+		}
+
+		order = order[pos+1:]
+		seen = true
+
+		if !inClock {
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return Duration{}, fmt.Errorf("interval: invalid component %q in duration %q", num+string(unit), s)
+			}
+
+			switch unit {
+			case 'Y':
+				d.Years = n
+			case 'M':
+				d.Months = n
+			case 'W':
+				d.Weeks = n
+			case 'D':
+				d.Days = n
+			}
+
+			continue
+		}
+
+		var unitLen time.Duration
+
+		switch unit {
+		case 'H':
+			unitLen = time.Hour
+		case 'M':
+			unitLen = time.Minute
+		case 'S':
+			unitLen = time.Second
+		}
+
+		c, err := parseClock(num, unitLen)
+		if err == nil && c > math.MaxInt64-d.Clock {
+			err = strconv.ErrRange
+		}
+
+		switch {
+		case errors.Is(err, strconv.ErrRange):
+			return Duration{}, fmt.Errorf("interval: duration %q is out of range", s)
+		case err != nil:
+			return Duration{}, fmt.Errorf("interval: invalid component %q in duration %q", num+string(unit), s)
+		}
+
+		d.Clock += c
+	}
+
+	if !seen {
+		return Duration{}, fmt.Errorf("interval: duration %q has no components", s)
+	}
+
+	return d, nil
+}
+
+// parseClock returns the decimal number num of units, truncated to whole
+// nanoseconds. Integer and fraction are parsed separately, so no precision is
+// lost to floating point. Returns strconv.ErrRange if the result doesn't fit
+// into a time.Duration.
+func parseClock(num string, unit time.Duration) (time.Duration, error) {
+	integer, frac, _ := cut(num, ".")
+	if integer == "" || strings.Trim(frac, "0123456789") != "" {
+		return 0, strconv.ErrSyntax
+	}
+
+	n, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	// Sum up the fractional digits from the last one, dividing by ten at each
+	// step. Nesting the truncating divisions yields the same result as
+	// truncating the exact fraction once.
+	var f time.Duration
+
+	for i := len(frac) - 1; i >= 0; i-- {
+		f = (f + time.Duration(frac[i]-'0')*unit) / 10
+	}
+
+	if n > int64((math.MaxInt64-f)/unit) {
+		return 0, strconv.ErrRange
+	}
+
+	return time.Duration(n)*unit + f, nil
+}
+
+// AddTo returns t shifted forward by d.
+func (d Duration) AddTo(t time.Time) time.Time {
+	return t.AddDate(d.Years, d.Months, 7*d.Weeks+d.Days).Add(d.Clock)
+}
+
+// SubFrom returns t shifted backward by d.
+func (d Duration) SubFrom(t time.Time) time.Time {
+	return t.Add(-d.Clock).AddDate(-d.Years, -d.Months, -7*d.Weeks-d.Days)
+}
+
+// String returns the ISO 8601 representation of d, e.g. "P1DT12H".
+func (d Duration) String() string {
+	var b strings.Builder
+
+	b.WriteByte('P')
+
+	for _, c := range []struct {
+		n    int
+		unit byte
+	}{{d.Years, 'Y'}, {d.Months, 'M'}, {d.Weeks, 'W'}, {d.Days, 'D'}} {
+		if c.n != 0 {
+			b.WriteString(strconv.Itoa(c.n))
+			b.WriteByte(c.unit)
+		}
+	}
+
+	if d.Clock != 0 {
+		var (
+			h = d.Clock / time.Hour
+			m = (d.Clock % time.Hour) / time.Minute
+			s = d.Clock % time.Minute
+		)
+
+		b.WriteByte('T')
+
+		if h != 0 {
+			fmt.Fprintf(&b, "%dH", h)
+		}
+
+		if m != 0 {
+			fmt.Fprintf(&b, "%dM", m)
+		}
+
+		if s != 0 {
+			b.WriteString(strconv.FormatFloat(s.Seconds(), 'f', -1, 64))
+			b.WriteByte('S')
+		}
+	}
+
+	if b.Len() == 1 {
+		return "PT0S"
+	}
+
+	return b.String()
+}
+
+// completeEnd parses the end of an interval which omits leading components,
+// taking them from start. A date may omit its year, or its year and month, and
+// a time of day may omit the date entirely. Trailing components can't be
+// omitted, so the end has a time of day if and only if the start has one. If
+// the end has no zone designator, it's in the zone of the start.
+func completeEnd(start time.Time, hasClock bool, end string) (time.Time, error) {
+	invalid := fmt.Errorf("interval: %q is not an ISO 8601 timestamp", end)
+
+	date, clock, hasT := cut(end, "T")
+	if !hasT {
+		if hasClock {
+			date, clock = "", end
+		} else {
+			date, clock = end, ""
+		}
+	}
+
+	if hasClock == (clock == "") {
+		return time.Time{}, invalid
+	}
+
+	var (
+		year, month, day = start.Date()
+		fields           []string
+	)
+
+	// Split the date into its fields, the last one being the day.
+	switch {
+	case strings.Contains(date, "-"):
+		fields = strings.Split(date, "-")
+	case len(date) == 8:
+		fields = []string{date[:4], date[4:6], date[6:]}
+	case len(date) == 4:
+		fields = []string{date[:2], date[2:]}
+	case len(date) == 2:
+		fields = []string{date}
+	case date != "":
+		return time.Time{}, invalid
+	}
+
+	if len(fields) > 3 {
+		return time.Time{}, invalid
+	}
+
+	for i, f := range fields {
+		width := 2
+		if i == 0 && len(fields) == 3 {
+			width = 4
+		}
+
+		if len(f) != width || strings.Trim(f, "0123456789") != "" {
+			return time.Time{}, invalid
+		}
+
+		n, _ := strconv.Atoi(f)
+
+		switch len(fields) - i {
+		case 3:
+			year = n
+		case 2:
+			month = time.Month(n)
+		case 1:
+			day = n
+		}
+	}
+
+	if month < time.January || month > time.December || day < 1 || day > daysIn(year, month) {
+		return time.Time{}, invalid
+	}
+
+	if !hasClock {
+		return time.Date(year, month, day, 0, 0, 0, 0, start.Location()), nil
+	}
+
+	for _, layout := range clockLayouts {
+		c, err := time.Parse(layout, clock)
+		if err != nil {
+			continue
+		}
+
+		loc := start.Location()
+		if strings.Contains(layout, "Z07") {
+			loc = c.Location()
+		}
+
+		return time.Date(year, month, day, c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), loc), nil
+	}
+
+	return time.Time{}, invalid
+}
+
+// daysIn returns the number of days of month in year.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("interval: %q is not an ISO 8601 timestamp", s)
+}
+
+func isDuration(s string) bool {
+	return strings.HasPrefix(s, "P")
+}
+
+// cut slices s around the first instance of sep.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
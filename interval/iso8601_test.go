@@ -0,0 +1,249 @@
+package interval
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterval(t *testing.T) {
+	var (
+		nov1 = time.Date(2020, time.November, 1, 0, 0, 0, 0, time.UTC)
+		nov2 = time.Date(2020, time.November, 2, 0, 0, 0, 0, time.UTC)
+	)
+
+	tt := []struct {
+		name      string
+		s         string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "start/end",
+			s:         "2020-11-01T00:00:00Z/2020-11-02T00:00:00Z",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "start/end without seconds",
+			s:         "2020-11-01T00:00Z/2020-11-02T00:00Z",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "start/end dates",
+			s:         "2020-11-01/2020-11-02",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "start/end in basic format",
+			s:         "20201101T000000Z/20201102T000000Z",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "double hyphen separator",
+			s:         "2020-11-01T00:00Z--2020-11-02T00:00Z",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "start/duration",
+			s:         "2020-11-01T00:00Z/P1D",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "duration/end",
+			s:         "PT24H/2020-11-02T00:00Z",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "start/duration with calendar months",
+			s:         "2020-01-31/P1M",
+			wantStart: time.Date(2020, time.January, 31, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "abbreviated end time",
+			s:         "2020-11-01T09:00Z/17:00Z",
+			wantStart: nov1.Add(9 * time.Hour),
+			wantEnd:   nov1.Add(17 * time.Hour),
+		},
+		{
+			name:      "abbreviated end date",
+			s:         "2020-11-01/02",
+			wantStart: nov1,
+			wantEnd:   nov2,
+		},
+		{
+			name:      "abbreviated end time with zoned start",
+			s:         "2020-11-01T10:00:00+02:00/12:00",
+			wantStart: nov1.Add(8 * time.Hour),
+			wantEnd:   nov1.Add(10 * time.Hour),
+		},
+		{
+			name:      "abbreviated end time with own zone",
+			s:         "2020-11-01T10:00+02:00/11:00Z",
+			wantStart: nov1.Add(8 * time.Hour),
+			wantEnd:   nov1.Add(11 * time.Hour),
+		},
+		{
+			name:      "abbreviated end time with fractional start",
+			s:         "2020-11-01T09:00:00.25Z/17:00Z",
+			wantStart: nov1.Add(9*time.Hour + 250*time.Millisecond),
+			wantEnd:   nov1.Add(17 * time.Hour),
+		},
+		{
+			name:      "abbreviated end time with fractional seconds",
+			s:         "2020-11-01T09:00Z/17:00:30.5",
+			wantStart: nov1.Add(9 * time.Hour),
+			wantEnd:   nov1.Add(17*time.Hour + 30500*time.Millisecond),
+		},
+		{
+			name:      "abbreviated end with day and time",
+			s:         "2020-11-01T09:00Z/02T17:00",
+			wantStart: nov1.Add(9 * time.Hour),
+			wantEnd:   nov2.Add(17 * time.Hour),
+		},
+		{
+			name:      "abbreviated end in basic format",
+			s:         "20201101T0900Z/1700Z",
+			wantStart: nov1.Add(9 * time.Hour),
+			wantEnd:   nov1.Add(17 * time.Hour),
+		},
+		{
+			name:      "abbreviated end month and day",
+			s:         "2020-11-01/12-01",
+			wantStart: nov1,
+			wantEnd:   time.Date(2020, time.December, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "fractional seconds and offsets",
+			s:         "2020-11-01T01:00:00.5+01:00/2020-11-01T19:00:00-05:00",
+			wantStart: nov1.Add(500 * time.Millisecond),
+			wantEnd:   nov2,
+		},
+		{name: "missing separator", s: "2020-11-01T00:00Z", wantErr: true},
+		{name: "end before start", s: "2020-11-02/2020-11-01", wantErr: true},
+		{name: "two durations", s: "P1D/P2D", wantErr: true},
+		{name: "repeating interval", s: "R5/2020-11-01/P1D", wantErr: true},
+		{name: "invalid start", s: "yesterday/2020-11-01", wantErr: true},
+		{name: "invalid end", s: "2020-11-01/tomorrow", wantErr: true},
+		{name: "invalid duration", s: "2020-11-01/P1X", wantErr: true},
+		{name: "abbreviated end without time", s: "2020-11-01T09:00Z/02", wantErr: true},
+		{name: "abbreviated end with time", s: "2020-11-01/17:00", wantErr: true},
+		{name: "abbreviated end with invalid day", s: "2020-11-01/31", wantErr: true},
+		{name: "abbreviated end with invalid month", s: "2020-11-01/13-01", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseInterval(tc.s)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tc.wantStart.Equal(got.Start()), "start: got %s", got.Start())
+			assert.True(t, tc.wantEnd.Equal(got.Stop()), "end: got %s", got.Stop())
+		})
+	}
+}
+
+func TestInterval_String(t *testing.T) {
+	start := time.Date(2020, time.November, 1, 12, 30, 0, 500, time.FixedZone("", -7200))
+
+	i, err := NewInterval(start, start.Add(time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2020-11-01T12:30:00.0000005-02:00/2020-11-01T13:30:00.0000005-02:00", i.String())
+
+	got, err := ParseInterval(i.String())
+	require.NoError(t, err)
+	assert.True(t, i.equal(got))
+}
+
+func TestParseDuration(t *testing.T) {
+	tt := []struct {
+		name    string
+		s       string
+		want    Duration
+		wantErr bool
+	}{
+		{name: "days", s: "P1D", want: Duration{Days: 1}},
+		{name: "weeks", s: "P2W", want: Duration{Weeks: 2}},
+		{
+			name: "all components",
+			s:    "P1Y2M3W4DT5H6M7.5S",
+			want: Duration{Years: 1, Months: 2, Weeks: 3, Days: 4, Clock: 5*time.Hour + 6*time.Minute + 7500*time.Millisecond},
+		},
+		{name: "minutes only", s: "PT90M", want: Duration{Clock: 90 * time.Minute}},
+		{name: "decimal comma", s: "PT0,5H", want: Duration{Clock: 30 * time.Minute}},
+		{name: "zero", s: "PT0S", want: Duration{}},
+		{name: "inexact binary fraction", s: "PT0.3S", want: Duration{Clock: 300 * time.Millisecond}},
+		{name: "nanoseconds", s: "PT0.000000001S", want: Duration{Clock: time.Nanosecond}},
+		{name: "fraction beyond nanoseconds", s: "PT1.0000000019S", want: Duration{Clock: time.Second + time.Nanosecond}},
+		{name: "fractional hours", s: "PT0.0000000001H", want: Duration{Clock: 360 * time.Nanosecond}},
+		{name: "maximum", s: "PT2562047H47M16.854775807S", want: Duration{Clock: math.MaxInt64}},
+		{name: "overflowing component", s: "PT2562048H", wantErr: true},
+		{name: "overflowing sum", s: "PT2562047H47M16.854775808S", wantErr: true},
+		{name: "overflowing integer", s: "PT99999999999999999999S", wantErr: true},
+		{name: "missing integer", s: "PT.5S", wantErr: true},
+		{name: "multiple decimal signs", s: "PT1.5.5S", wantErr: true},
+		{name: "missing P", s: "1D", wantErr: true},
+		{name: "no components", s: "P", wantErr: true},
+		{name: "empty time part", s: "P1DT", wantErr: true},
+		{name: "wrong order", s: "P1D2Y", wantErr: true},
+		{name: "duplicate component", s: "P1D2D", wantErr: true},
+		{name: "hours without T", s: "P1H", wantErr: true},
+		{name: "fractional days", s: "P1.5D", wantErr: true},
+		{name: "missing number", s: "PTS", wantErr: true},
+		{name: "multiple T", s: "PT1HT1M", wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseDuration(tc.s)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDuration_String(t *testing.T) {
+	tt := []struct {
+		d    Duration
+		want string
+	}{
+		{d: Duration{}, want: "PT0S"},
+		{d: Duration{Days: 1}, want: "P1D"},
+		{d: Duration{Weeks: 2}, want: "P2W"},
+		{d: Duration{Clock: 90 * time.Minute}, want: "PT1H30M"},
+		{d: Duration{Years: 1, Months: 2, Days: 3, Clock: 4*time.Hour + 5500*time.Millisecond}, want: "P1Y2M3DT4H5.5S"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.d.String())
+
+			got, err := ParseDuration(tc.want)
+			require.NoError(t, err)
+			assert.Equal(t, tc.d.String(), got.String())
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package interval
+
+import (
+	"encoding/json"
+	"errors"
+	"math/bits"
+	"sort"
+	"time"
+)
+
+type jsonInterval struct {
+	Start *time.Time `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+// MarshalJSON implements json.Marshaler. The interval is encoded as an object
+// with RFC 3339 "start" and "end" timestamps.
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonInterval{
+		Start: &i.low,
+		End:   &i.high,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Like NewInterval, it returns an
+// error if end is before start.
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var v jsonInterval
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if v.Start == nil || v.End == nil {
+		return errors.New("interval: start and end are required")
+	}
+
+	x, err := NewInterval(*v.Start, *v.End)
+	if err != nil {
+		return err
+	}
+
+	*i = x
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The interval is encoded as
+// returned by String.
+func (i Interval) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. All formats accepted by
+// ParseInterval are supported.
+func (i *Interval) UnmarshalText(text []byte) error {
+	x, err := ParseInterval(string(text))
+	if err != nil {
+		return err
+	}
+
+	*i = x
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The tree is encoded as an ordered
+// array of {"interval": ..., "payload": ...} objects.
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	res := t.InOrder()
+
+	if res == nil {
+		res = []Result{}
+	}
+
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON implements json.Unmarshaler for the format written by
+// MarshalJSON. All existing entries of the tree are replaced, without
+// notifying watchers. If an interval occurs multiple times, possibly in
+// different zones, the last payload wins. Payloads are decoded into their
+// generic JSON representation.
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	var res []Result
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Interval.less(res[j].Interval)
+	})
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	nodes := make([]*node, 0, len(res))
+
+	for _, r := range res {
+		if len(nodes) > 0 && nodes[len(nodes)-1].key.equal(r.Interval) {
+			nodes[len(nodes)-1].payload = r.Payload
+			continue
+		}
+
+		nodes = append(nodes, t.newLeaf(r.Interval, r.Payload))
+	}
+
+	t.root = t.build(nodes, t.sentinel, 0, bits.Len(uint(len(nodes)))-1)
+	t.root.color = black
+
+	return nil
+}
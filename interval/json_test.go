@@ -0,0 +1,163 @@
+package interval
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterval_MarshalJSON(t *testing.T) {
+	nov, err := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Nov-02"))
+	require.NoError(t, err)
+
+	b, err := json.Marshal(nov)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start": "2020-11-01T00:00:00Z", "end": "2020-11-02T00:00:00Z"}`, string(b))
+
+	var got Interval
+
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, nov, got)
+
+	b, err = json.Marshal(Result{Interval: nov, Payload: "Nov"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"interval": {"start": "2020-11-01T00:00:00Z", "end": "2020-11-02T00:00:00Z"}, "payload": "Nov"}`, string(b))
+}
+
+func TestInterval_UnmarshalJSON(t *testing.T) {
+	tt := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "valid interval", data: `{"start": "2020-11-01T00:00:00+01:00", "end": "2020-11-02T00:00:00Z"}`},
+		{name: "empty interval", data: `{"start": "2020-11-01T00:00:00Z", "end": "2020-11-01T00:00:00Z"}`},
+		{name: "end before start", data: `{"start": "2020-11-02T00:00:00Z", "end": "2020-11-01T00:00:00Z"}`, wantErr: true},
+		{name: "missing end", data: `{"start": "2020-11-02T00:00:00Z"}`, wantErr: true},
+		{name: "invalid timestamp", data: `{"start": "yesterday", "end": "2020-11-01T00:00:00Z"}`, wantErr: true},
+		{name: "not an object", data: `"2020-11-01T00:00:00Z"`, wantErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var i Interval
+
+			err := json.Unmarshal([]byte(tc.data), &i)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, Interval{}, i)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInterval_MarshalText(t *testing.T) {
+	start := time.Date(2020, time.November, 1, 12, 30, 0, 500, time.FixedZone("", 3600))
+	i, err := NewInterval(start, start.Add(36*time.Hour))
+	require.NoError(t, err)
+
+	b, err := i.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2020-11-01T12:30:00.0000005+01:00/2020-11-03T00:30:00.0000005+01:00", string(b))
+
+	var got Interval
+
+	require.NoError(t, got.UnmarshalText(b))
+	assert.True(t, i.equal(got))
+
+	assert.Error(t, got.UnmarshalText([]byte("2020-11-01T00:00:00Z")))
+	assert.Error(t, got.UnmarshalText([]byte("2020-11-02T00:00:00Z/2020-11-01T00:00:00Z")))
+	assert.Error(t, got.UnmarshalText([]byte("2020-11-02T00:00:00Z/tomorrow")))
+
+	// Intervals can be used as JSON object keys.
+	b, err = json.Marshal(map[Interval]string{i: "foo"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"2020-11-01T12:30:00.0000005+01:00/2020-11-03T00:30:00.0000005+01:00": "foo"}`, string(b))
+}
+
+func TestIntervalTree_MarshalJSON(t *testing.T) {
+	t.Run("empty tree encodes to empty array", func(t *testing.T) {
+		b, err := json.Marshal(NewIntervalTree())
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(b))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		tree := NewIntervalTree()
+
+		nov, _ := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Nov-02"))
+		feb, _ := NewInterval(newTime(t, "2020-Feb-01"), newTime(t, "2020-Feb-02"))
+		dec, _ := NewInterval(newTime(t, "2020-Dec-01"), newTime(t, "2020-Dec-02"))
+
+		tree.Upsert(nov, "Nov")
+		tree.Upsert(feb, 2.0)
+		tree.Upsert(dec, map[string]interface{}{"foo": "bar"})
+
+		b, err := json.Marshal(tree)
+		require.NoError(t, err)
+
+		got := NewIntervalTree()
+		require.NoError(t, json.Unmarshal(b, got))
+
+		assert.Equal(t, tree.InOrder(), got.InOrder())
+		assert.Equal(t, tree.root.max, got.root.max)
+	})
+
+	t.Run("unsorted input with duplicates", func(t *testing.T) {
+		tree := NewIntervalTree()
+
+		data := `[
+			{"interval": {"start": "2020-12-01T00:00:00Z", "end": "2020-12-02T00:00:00Z"}, "payload": "Dec"},
+			{"interval": {"start": "2020-02-01T00:00:00Z", "end": "2020-02-02T00:00:00Z"}, "payload": "Feb"},
+			{"interval": {"start": "2020-12-01T00:00:00Z", "end": "2020-12-02T00:00:00Z"}, "payload": "December"}
+		]`
+
+		require.NoError(t, json.Unmarshal([]byte(data), tree))
+
+		res := tree.InOrder()
+		require.Len(t, res, 2)
+		assert.Equal(t, "Feb", res[0].Payload)
+		assert.Equal(t, "December", res[1].Payload)
+	})
+
+	t.Run("equal instants in different zones are one key", func(t *testing.T) {
+		tree := NewIntervalTree()
+
+		data := `[
+			{"interval": {"start": "2020-12-01T00:00:00Z", "end": "2020-12-02T00:00:00Z"}, "payload": "UTC"},
+			{"interval": {"start": "2020-12-01T02:00:00+02:00", "end": "2020-12-02T02:00:00+02:00"}, "payload": "CEST"}
+		]`
+
+		require.NoError(t, json.Unmarshal([]byte(data), tree))
+
+		res := tree.InOrder()
+		require.Len(t, res, 1)
+		assert.Equal(t, "CEST", res[0].Payload)
+
+		// Lookups agree with the deduplication, whichever zone they use.
+		utc, _ := NewInterval(res[0].Interval.Start().UTC(), res[0].Interval.Stop().UTC())
+
+		got, err := tree.FindExact(utc)
+		require.NoError(t, err)
+		assert.Equal(t, "CEST", got.Payload)
+
+		tree.Upsert(utc, "updated")
+		require.Len(t, tree.InOrder(), 1)
+		assert.Equal(t, "updated", tree.InOrder()[0].Payload)
+
+		tree.Delete(utc)
+		assert.Empty(t, tree.InOrder())
+	})
+
+	t.Run("invalid interval returns error", func(t *testing.T) {
+		data := `[{"interval": {"start": "2020-12-02T00:00:00Z", "end": "2020-12-01T00:00:00Z"}, "payload": "Dec"}]`
+
+		assert.Error(t, json.Unmarshal([]byte(data), NewIntervalTree()))
+	})
+}
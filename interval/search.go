@@ -7,7 +7,6 @@ import (
 const noIntervalErrMsg = "no interval found for %q"
 
 type inorderResult struct {
-	nodes   map[Interval]*node
 	results []Result
 }
 
@@ -46,7 +45,6 @@ func (t *Tree) FindAllOverlapping(key Interval) ([]Result, error) {
 	}
 
 	res := &inorderResult{
-		nodes:   make(map[Interval]*node),
 		results: make([]Result, 0),
 	}
 
@@ -154,23 +152,25 @@ func (t *Tree) resultsInorder(z *node, res *[]Result) {
 	}
 }
 
+// findExact returns the node whose interval is equal to key, or nil. Like the
+// order of the tree, equality compares instants, so key may be in a different
+// zone than the stored interval.
 func (t *Tree) findExact(key Interval) *node {
-	if t.root == t.sentinel {
-		return nil
-	}
+	x := t.root
 
-	res := &inorderResult{
-		nodes:   make(map[Interval]*node),
-		results: make([]Result, 0),
+	for x != t.sentinel && !key.equal(x.key) {
+		if key.less(x.key) {
+			x = x.left
+		} else {
+			x = x.right
+		}
 	}
 
-	t.searchInorder(t.root, key, res)
-
-	if n, ok := res.nodes[key]; ok {
-		return n
+	if x == t.sentinel {
+		return nil
 	}
 
-	return nil
+	return x
 }
 
 func (t *Tree) searchInorder(z *node, key Interval, result *inorderResult) {
@@ -183,7 +183,6 @@ func (t *Tree) searchInorder(z *node, key Interval, result *inorderResult) {
 	}
 
 	if z.key.overlaps(key) {
-		result.nodes[z.key] = z
 		result.results = append(result.results, Result{
 			Interval: z.key,
 			Payload:  z.payload,
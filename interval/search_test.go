@@ -1,9 +1,11 @@
 package interval
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntervalTree_FindAllOverlapping(t *testing.T) {
@@ -392,3 +394,43 @@ func TestTree_Successor(t *testing.T) {
 		})
 	}
 }
+
+func TestIntervalTree_FindAllOverlapping_random(t *testing.T) {
+	var (
+		rng     = rand.New(rand.NewSource(1))
+		tree    = NewIntervalTree()
+		entries = make(map[Interval]bool)
+		random  = func() Interval {
+			low := rng.Int63n(100)
+			return span(t, low, low+rng.Int63n(20))
+		}
+	)
+
+	// Rotations and deletions have to keep the max of every node up to date,
+	// otherwise searches skip subtrees holding overlapping intervals.
+	for i := 0; i < 3000; i++ {
+		k := random()
+
+		if rng.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(entries, k)
+		} else {
+			tree.Upsert(k, i)
+			entries[k] = true
+		}
+
+		var (
+			q    = random()
+			want int
+		)
+
+		for k := range entries {
+			if k.overlaps(q) {
+				want++
+			}
+		}
+
+		res, _ := tree.FindAllOverlapping(q)
+		require.Len(t, res, want, "step %d", i)
+	}
+}
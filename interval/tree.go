@@ -2,7 +2,10 @@ package interval
 
 import (
 	"math"
-	"sync"
+
+	"github.com/obitech/go-trees/codec"
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/internal/watch"
 )
 
 type color int
@@ -19,29 +22,39 @@ func (e ErrNotFound) Error() string {
 	return string(e)
 }
 
-// Tree represents an Interval tree with a root node and Mutex to
+// Tree represents an Interval tree with a root node and a lock to
 // protect concurrent access.
 type Tree struct {
-	lock     sync.RWMutex
+	lock     lock.RWMutex
 	root     *node
 	sentinel *node
+	watchers watch.Hub
+	codec    codec.Codec
 }
 
 // Result is a search result when looking up an interval in the tree.
 type Result struct {
-	Interval Interval
-	Payload  interface{}
+	Interval Interval    `json:"interval"`
+	Payload  interface{} `json:"payload"`
 }
 
-// NewIntervalTree returns an initialized but empty interval tree.
-func NewIntervalTree() *Tree {
+// NewIntervalTree returns an initialized but empty interval tree. Unless
+// WithoutLocking is passed, all operations on the tree are safe to be accessed
+// concurrently.
+func NewIntervalTree(opts ...Option) *Tree {
 	sentinel := &node{color: black, payload: sentinelPayload}
 
-	return &Tree{
-		lock:     sync.RWMutex{},
+	t := &Tree{
 		root:     sentinel,
 		sentinel: sentinel,
+		codec:    codec.Gob{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
 	}
+
+	return t
 }
 
 // Root returns a Result of the payload of the root node of the tree or an
@@ -120,7 +133,9 @@ func (t *Tree) rotateLeft(x *node) {
 	y.left = x
 	x.parent = y
 
+	// x is now below y, so it has to be updated first.
 	t.updateMax(x)
+	t.updateMax(y)
 }
 
 func (t *Tree) rotateRight(x *node) {
@@ -145,6 +160,7 @@ func (t *Tree) rotateRight(x *node) {
 	y.right = x
 	x.parent = y
 
+	t.updateMax(x)
 	t.updateMax(y)
 }
 
@@ -0,0 +1,180 @@
+package interval
+
+import (
+	"fmt"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// Tree2D stores payloads under a pair of intervals, e.g. the time and the
+// version range a record is valid for, and finds all payloads overlapping in
+// both dimensions. It's built from nested trees: an outer tree over the
+// primary intervals holds a tree over the secondary intervals for each of
+// them.
+type Tree2D struct {
+	lock  *lock.RWMutex
+	outer *Tree
+	size  int
+}
+
+// Result2D is a search result when looking up a pair of intervals in a
+// Tree2D.
+type Result2D struct {
+	Interval  Interval    `json:"interval"`
+	Secondary Interval    `json:"secondary"`
+	Payload   interface{} `json:"payload"`
+}
+
+// NewIntervalTree2D returns an initialized but empty two-dimensional interval
+// tree. Unless WithoutLocking is passed, all operations on the tree are safe to
+// be accessed concurrently.
+func NewIntervalTree2D(opts ...Option) *Tree2D {
+	outer := NewIntervalTree(opts...)
+
+	return &Tree2D{
+		lock:  &outer.lock,
+		outer: outer,
+	}
+}
+
+// Len returns the number of payloads in the tree.
+func (t *Tree2D) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Upsert updates an existing payload, or inserts a new one with the given
+// pair of intervals.
+func (t *Tree2D) Upsert(key, secondary Interval, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	n := t.outer.findExact(key)
+	if n == nil {
+		n = t.outer.newLeaf(key, NewIntervalTree(WithoutLocking()))
+		t.outer.insert(n)
+	}
+
+	inner := n.payload.(*Tree)
+
+	if m := inner.findExact(secondary); m != nil {
+		m.payload = payload
+		return
+	}
+
+	inner.insert(inner.newLeaf(secondary, payload))
+	t.size++
+}
+
+// Delete deletes the payload with the given pair of intervals.
+func (t *Tree2D) Delete(key, secondary Interval) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	n := t.outer.findExact(key)
+	if n == nil {
+		return
+	}
+
+	inner := n.payload.(*Tree)
+
+	m := inner.findExact(secondary)
+	if m == nil {
+		return
+	}
+
+	inner.delete(m)
+	t.size--
+
+	// Empty inner trees are removed, so searches only descend into trees
+	// holding at least one payload.
+	if inner.root == inner.sentinel {
+		t.outer.delete(n)
+	}
+}
+
+// FindExact returns the Result2D exactly matching the given pair of
+// intervals. Returns an ErrNotFound if not found.
+func (t *Tree2D) FindExact(key, secondary Interval) (Result2D, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if n := t.outer.findExact(key); n != nil {
+		if m := n.payload.(*Tree).findExact(secondary); m != nil {
+			return Result2D{
+				Interval:  key,
+				Secondary: m.key,
+				Payload:   m.payload,
+			}, nil
+		}
+	}
+
+	return Result2D{}, ErrNotFound(fmt.Sprintf("intervals %q and %q do not exist", key, secondary))
+}
+
+// FindAllOverlapping returns all payloads whose primary interval overlaps key
+// and whose secondary interval overlaps secondary, ordered by their primary
+// and then by their secondary interval. Returns an ErrNotFound if no such
+// payload is found.
+func (t *Tree2D) FindAllOverlapping(key, secondary Interval) ([]Result2D, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var res []Result2D
+
+	if t.outer.root != t.outer.sentinel {
+		outer := &inorderResult{
+			results: make([]Result, 0),
+		}
+
+		t.outer.searchInorder(t.outer.root, key, outer)
+
+		for _, r := range outer.results {
+			inner := &inorderResult{
+				results: make([]Result, 0),
+			}
+
+			tree := r.Payload.(*Tree)
+			tree.searchInorder(tree.root, secondary, inner)
+
+			for _, s := range inner.results {
+				res = append(res, Result2D{
+					Interval:  r.Interval,
+					Secondary: s.Interval,
+					Payload:   s.Payload,
+				})
+			}
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, ErrNotFound(fmt.Sprintf("no intervals found for %q and %q", key, secondary))
+	}
+
+	return res, nil
+}
+
+// InOrder returns all entries ordered by their primary and then by their
+// secondary interval.
+func (t *Tree2D) InOrder() []Result2D {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	res := make([]Result2D, 0, t.size)
+
+	t.outer.inorder(t.outer.root, func(n *node) {
+		inner := n.payload.(*Tree)
+
+		inner.inorder(inner.root, func(m *node) {
+			res = append(res, Result2D{
+				Interval:  n.key,
+				Secondary: m.key,
+				Payload:   m.payload,
+			})
+		})
+	})
+
+	return res
+}
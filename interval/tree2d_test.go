@@ -0,0 +1,133 @@
+package interval
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// span returns an interval between two points in time given in seconds.
+func span(t *testing.T, low, high int64) Interval {
+	i, err := NewInterval(time.Unix(low, 0).UTC(), time.Unix(high, 0).UTC())
+	require.NoError(t, err)
+
+	return i
+}
+
+func TestIntervalTree2D(t *testing.T) {
+	var (
+		tree = NewIntervalTree2D()
+		jan  = NewIntervalTree2D(WithoutLocking())
+	)
+
+	assert.True(t, jan.lock.Disabled())
+
+	_, err := tree.FindAllOverlapping(span(t, 0, 10), span(t, 0, 10))
+	assert.Error(t, err)
+	assert.Empty(t, tree.InOrder())
+
+	tree.Upsert(span(t, 0, 10), span(t, 1, 2), "a")
+	tree.Upsert(span(t, 0, 10), span(t, 5, 7), "b")
+	tree.Upsert(span(t, 20, 30), span(t, 1, 2), "c")
+	tree.Upsert(span(t, 0, 10), span(t, 1, 2), "A")
+
+	assert.Equal(t, 3, tree.Len())
+
+	r, err := tree.FindExact(span(t, 0, 10), span(t, 1, 2))
+	require.NoError(t, err)
+	assert.Equal(t, Result2D{Interval: span(t, 0, 10), Secondary: span(t, 1, 2), Payload: "A"}, r)
+
+	_, err = tree.FindExact(span(t, 0, 10), span(t, 1, 3))
+	assert.Error(t, err)
+
+	res, err := tree.FindAllOverlapping(span(t, 5, 25), span(t, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []Result2D{
+		{Interval: span(t, 0, 10), Secondary: span(t, 1, 2), Payload: "A"},
+		{Interval: span(t, 20, 30), Secondary: span(t, 1, 2), Payload: "c"},
+	}, res)
+
+	_, err = tree.FindAllOverlapping(span(t, 20, 30), span(t, 5, 7))
+	assert.Error(t, err)
+
+	tree.Delete(span(t, 20, 30), span(t, 1, 2))
+	tree.Delete(span(t, 20, 30), span(t, 1, 2))
+	tree.Delete(span(t, 0, 10), span(t, 3, 4))
+
+	assert.Equal(t, 2, tree.Len())
+	assert.Equal(t, []Result2D{
+		{Interval: span(t, 0, 10), Secondary: span(t, 1, 2), Payload: "A"},
+		{Interval: span(t, 0, 10), Secondary: span(t, 5, 7), Payload: "b"},
+	}, tree.InOrder())
+
+	// Removing the last secondary interval removes the primary one as well.
+	_, err = tree.outer.FindExact(span(t, 20, 30))
+	assert.Error(t, err)
+}
+
+func TestIntervalTree2D_random(t *testing.T) {
+	type key struct {
+		primary, secondary Interval
+	}
+
+	var (
+		rng     = rand.New(rand.NewSource(1))
+		tree    = NewIntervalTree2D()
+		entries = make(map[key]int)
+		random  = func() Interval {
+			low := rng.Int63n(100)
+			return span(t, low, low+rng.Int63n(20))
+		}
+	)
+
+	for i := 0; i < 2000; i++ {
+		k := key{random(), random()}
+
+		if rng.Intn(3) == 0 {
+			tree.Delete(k.primary, k.secondary)
+			delete(entries, k)
+		} else {
+			tree.Upsert(k.primary, k.secondary, i)
+			entries[k] = i
+		}
+
+		if i%50 != 0 {
+			continue
+		}
+
+		var (
+			q    = key{random(), random()}
+			want []int
+			got  []int
+		)
+
+		for k, v := range entries {
+			if k.primary.overlaps(q.primary) && k.secondary.overlaps(q.secondary) {
+				want = append(want, v)
+			}
+		}
+
+		res, err := tree.FindAllOverlapping(q.primary, q.secondary)
+		if len(want) == 0 {
+			require.Error(t, err)
+			continue
+		}
+
+		require.NoError(t, err)
+
+		for _, r := range res {
+			got = append(got, r.Payload.(int))
+		}
+
+		sort.Ints(want)
+		sort.Ints(got)
+		require.Equal(t, want, got)
+	}
+
+	assert.Equal(t, len(entries), tree.Len())
+	assert.Len(t, tree.InOrder(), len(entries))
+}
@@ -289,3 +289,23 @@ func TestIntervalTree_rotateRight(t *testing.T) {
 func TestIntervalTree_Min(t *testing.T) {
 	// TODO: write tests
 }
+
+func TestNewIntervalTree_WithoutLocking(t *testing.T) {
+	tree := NewIntervalTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+
+	nov, _ := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Nov-02"))
+	feb, _ := NewInterval(newTime(t, "2020-Feb-01"), newTime(t, "2020-Feb-02"))
+
+	tree.Upsert(nov, "Nov")
+	tree.Upsert(feb, "Feb")
+	tree.Delete(nov)
+
+	r, err := tree.Root()
+	assert.NoError(t, err)
+	assert.Equal(t, "Feb", r.Payload)
+
+	_, err = tree.FindExact(nov)
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package interval
+
+import (
+	"context"
+
+	"github.com/obitech/go-trees/internal/watch"
+)
+
+// EventType describes the kind of modification reported by an Event.
+type EventType = watch.EventType
+
+const (
+	// EventInsert is emitted when a new key is added to the tree.
+	EventInsert = watch.EventInsert
+	// EventUpdate is emitted when the payload of an existing key is replaced.
+	EventUpdate = watch.EventUpdate
+	// EventDelete is emitted when a key is removed from the tree.
+	EventDelete = watch.EventDelete
+)
+
+// Event describes a single modification of the tree. Key holds the Interval
+// of the modified entry. Old is nil for inserts, New is nil for deletes.
+type Event = watch.Event
+
+// Filter reports whether events for the given interval key should be
+// delivered to a watcher.
+type Filter func(key Interval) bool
+
+// Overlapping returns a Filter matching all interval keys which overlap with
+// the passed interval.
+func Overlapping(i Interval) Filter {
+	return func(key Interval) bool {
+		return key.overlaps(i)
+	}
+}
+
+// Watch returns a channel which receives an Event for every modification of an
+// interval key matching filter. A nil filter matches all keys. Events are
+// queued without limit, so slow receivers never block writers on the tree. The
+// channel is closed once ctx is done.
+func (t *Tree) Watch(ctx context.Context, filter Filter) <-chan Event {
+	if filter == nil {
+		return t.watchers.Watch(ctx, nil)
+	}
+
+	return t.watchers.Watch(ctx, func(e Event) bool {
+		return filter(e.Key.(Interval))
+	})
+}
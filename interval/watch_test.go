@@ -0,0 +1,81 @@
+package interval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	select {
+	case e, ok := <-ch:
+		require.True(t, ok, "channel closed unexpectedly")
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for event")
+	}
+
+	return Event{}
+}
+
+func TestIntervalTree_Watch(t *testing.T) {
+	t.Run("watcher receives insert, update and delete events", func(t *testing.T) {
+		tree := NewIntervalTree()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := tree.Watch(ctx, nil)
+
+		nov, _ := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Nov-02"))
+
+		tree.Upsert(nov, "foo")
+		tree.Upsert(nov, "bar")
+		tree.Delete(nov)
+
+		assert.Equal(t, Event{Type: EventInsert, Key: nov, New: "foo"}, receive(t, events))
+		assert.Equal(t, Event{Type: EventUpdate, Key: nov, Old: "foo", New: "bar"}, receive(t, events))
+		assert.Equal(t, Event{Type: EventDelete, Key: nov, Old: "bar"}, receive(t, events))
+	})
+
+	t.Run("overlapping filter drops events of other intervals", func(t *testing.T) {
+		tree := NewIntervalTree()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		q, _ := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Dec-01"))
+		events := tree.Watch(ctx, Overlapping(q))
+
+		feb, _ := NewInterval(newTime(t, "2020-Feb-01"), newTime(t, "2020-Feb-02"))
+		nov, _ := NewInterval(newTime(t, "2020-Nov-01"), newTime(t, "2020-Nov-02"))
+
+		tree.Upsert(feb, "Feb")
+		tree.Upsert(nov, "Nov")
+
+		assert.Equal(t, nov, receive(t, events).Key)
+
+		select {
+		case e := <-events:
+			assert.Failf(t, "unexpected event", "%+v", e)
+		case <-time.After(10 * time.Millisecond):
+		}
+	})
+
+	t.Run("cancelling the context closes the channel", func(t *testing.T) {
+		tree := NewIntervalTree()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events := tree.Watch(ctx, nil)
+
+		cancel()
+
+		for range events {
+		}
+
+		assert.Zero(t, tree.watchers.Len())
+	})
+}
@@ -0,0 +1,19 @@
+package kdtree
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithMetric sets the metric used by nearest neighbour searches. Euclidean is
+// used by default.
+func WithMetric(m Metric) Option {
+	return func(t *Tree) {
+		t.metric = m
+	}
+}
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
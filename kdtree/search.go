@@ -0,0 +1,161 @@
+package kdtree
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// Nearest returns the point closest to p according to the metric of the
+// tree. Returns false if the tree is empty.
+func (t *Tree) Nearest(p Point) (Result, bool) {
+	res := t.KNearest(p, 1)
+
+	if len(res) == 0 {
+		return Result{}, false
+	}
+
+	return res[0], true
+}
+
+// KNearest returns the k points closest to p according to the metric of the
+// tree, ordered by ascending distance. Returns fewer points if the tree holds
+// less than k.
+func (t *Tree) KNearest(p Point, k int) []Result {
+	t.checkDims(p)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if k <= 0 {
+		return nil
+	}
+
+	s := &search{
+		query:  p,
+		k:      k,
+		metric: t.metric,
+	}
+
+	s.visit(t.root)
+
+	res := make([]Result, len(s.best))
+	for i := len(res) - 1; i >= 0; i-- {
+		r := heap.Pop(&s.best).(Result)
+		r.Point = append(Point(nil), r.Point...)
+
+		res[i] = r
+	}
+
+	return res
+}
+
+// RangeSearch returns all points within b, ordered by their coordinates.
+func (t *Tree) RangeSearch(b Box) []Result {
+	t.checkDims(b.Min)
+	t.checkDims(b.Max)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var res []Result
+
+	rangeSearch(t.root, b, &res)
+
+	sort.Slice(res, func(i, j int) bool {
+		return less(res[i].Point, res[j].Point)
+	})
+
+	return res
+}
+
+func rangeSearch(n *node, b Box, res *[]Result) {
+	if n == nil {
+		return
+	}
+
+	if b.Contains(n.point) {
+		*res = append(*res, Result{
+			Point:   append(Point(nil), n.point...),
+			Payload: n.payload,
+		})
+	}
+
+	if b.Min[n.axis] < n.point[n.axis] {
+		rangeSearch(n.left, b, res)
+	}
+
+	if b.Max[n.axis] >= n.point[n.axis] {
+		rangeSearch(n.right, b, res)
+	}
+}
+
+// search holds the state of a k nearest neighbour search.
+type search struct {
+	query  Point
+	k      int
+	metric Metric
+	best   maxHeap
+}
+
+// visit descends into the subtree on the side of the query first, and only
+// visits the other side if the splitting plane is closer than the k-th best
+// point found so far.
+func (s *search) visit(n *node) {
+	if n == nil {
+		return
+	}
+
+	s.offer(n)
+
+	var (
+		delta     = s.query[n.axis] - n.point[n.axis]
+		near, far = n.left, n.right
+	)
+
+	if delta >= 0 {
+		near, far = far, near
+	}
+
+	s.visit(near)
+
+	if len(s.best) < s.k || s.metric.AxisDistance(delta) <= s.worst() {
+		s.visit(far)
+	}
+}
+
+func (s *search) offer(n *node) {
+	d := s.metric.Distance(s.query, n.point)
+
+	switch {
+	case len(s.best) < s.k:
+		heap.Push(&s.best, Result{Point: n.point, Payload: n.payload, Distance: d})
+	case d < s.worst():
+		s.best[0] = Result{Point: n.point, Payload: n.payload, Distance: d}
+		heap.Fix(&s.best, 0)
+	}
+}
+
+func (s *search) worst() float64 {
+	if len(s.best) == 0 {
+		return math.Inf(1)
+	}
+
+	return s.best[0].Distance
+}
+
+// maxHeap holds results with the greatest distance on top.
+type maxHeap []Result
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].Distance > h[j].Distance }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(Result)) }
+
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return x
+}
@@ -0,0 +1,325 @@
+// Package kdtree implements a k-d tree, a binary space partitioning tree for
+// points in k-dimensional space. Each level of the tree splits the points
+// along the next axis, which allows nearest neighbour and range searches to
+// skip large parts of the space. On balanced trees these run in O(lg n) time
+// on average.
+package kdtree
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// Tree represents a k-d tree with a root node and a lock to protect concurrent
+// access.
+type Tree struct {
+	lock   lock.RWMutex
+	root   *node
+	dims   int
+	metric Metric
+	size   int
+}
+
+// node splits space along axis at point[axis]. All points of the left subtree
+// have a lower coordinate on that axis, all points of the right one an equal or
+// higher coordinate.
+type node struct {
+	point   Point
+	payload interface{}
+	axis    int
+	left    *node
+	right   *node
+}
+
+// NewKDTree returns a new k-d tree for points with the given number of
+// dimensions. Unless WithoutLocking is passed, all operations on the tree are
+// safe to be accessed concurrently. Panics if dims is less than 1.
+func NewKDTree(dims int, opts ...Option) *Tree {
+	if dims < 1 {
+		panic("kdtree: dimensions must be at least 1")
+	}
+
+	t := &Tree{
+		dims:   dims,
+		metric: Euclidean{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of points in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a single node has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return height(t.root)
+}
+
+// Load replaces the contents of the tree with the passed points, building a
+// balanced tree by splitting at the median on every level. Runs in
+// O(n lg² n) time. Of duplicate points, the last one is kept. Panics if a
+// point has the wrong number of dimensions.
+func (t *Tree) Load(points []Result) {
+	nodes := make([]*node, 0, len(points))
+
+	for _, p := range points {
+		t.checkDims(p.Point)
+
+		nodes = append(nodes, &node{
+			point:   append(Point(nil), p.Point...),
+			payload: p.Payload,
+		})
+	}
+
+	// Remove duplicates, keeping the last one.
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return less(nodes[i].point, nodes[j].point)
+	})
+
+	unique := nodes[:0]
+
+	for i, n := range nodes {
+		if i+1 < len(nodes) && equal(n.point, nodes[i+1].point) {
+			continue
+		}
+
+		unique = append(unique, n)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = t.build(unique, 0)
+	t.size = len(unique)
+}
+
+// Insert inserts a point with a payload, or updates the payload if the point
+// exists already. The point is copied. Panics if the point has the wrong
+// number of dimensions.
+func (t *Tree) Insert(p Point, payload interface{}) {
+	t.checkDims(p)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var (
+		link = &t.root
+		axis int
+	)
+
+	for *link != nil {
+		n := *link
+
+		if equal(n.point, p) {
+			n.payload = payload
+			return
+		}
+
+		if p[n.axis] < n.point[n.axis] {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+
+		axis = (n.axis + 1) % t.dims
+	}
+
+	*link = &node{
+		point:   append(Point(nil), p...),
+		payload: payload,
+		axis:    axis,
+	}
+
+	t.size++
+}
+
+// Search returns the payload of the given point and whether it exists.
+func (t *Tree) Search(p Point) (interface{}, bool) {
+	t.checkDims(p)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	for n := t.root; n != nil; {
+		if equal(n.point, p) {
+			return n.payload, true
+		}
+
+		if p[n.axis] < n.point[n.axis] {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	return nil, false
+}
+
+// Delete deletes the given point and returns whether it existed.
+func (t *Tree) Delete(p Point) bool {
+	t.checkDims(p)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var deleted bool
+
+	t.root = t.delete(t.root, p, &deleted)
+
+	if deleted {
+		t.size--
+	}
+
+	return deleted
+}
+
+// delete removes p from the subtree rooted at n and returns its new root. The
+// node of p is replaced by the point with the lowest coordinate on its axis
+// from one of its subtrees, which is deleted from there recursively.
+func (t *Tree) delete(n *node, p Point, deleted *bool) *node {
+	if n == nil {
+		return nil
+	}
+
+	if !equal(n.point, p) {
+		if p[n.axis] < n.point[n.axis] {
+			n.left = t.delete(n.left, p, deleted)
+		} else {
+			n.right = t.delete(n.right, p, deleted)
+		}
+
+		return n
+	}
+
+	*deleted = true
+
+	switch {
+	case n.right != nil:
+		m := findMin(n.right, n.axis)
+
+		n.point, n.payload = m.point, m.payload
+		n.right = t.delete(n.right, m.point, new(bool))
+	case n.left != nil:
+		// Moving the left subtree to the right keeps equal coordinates on
+		// the right side.
+		m := findMin(n.left, n.axis)
+
+		n.point, n.payload = m.point, m.payload
+		n.right = t.delete(n.left, m.point, new(bool))
+		n.left = nil
+	default:
+		return nil
+	}
+
+	return n
+}
+
+// build returns a balanced tree of the sorted, unique points of nodes.
+func (t *Tree) build(nodes []*node, axis int) *node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].point[axis] < nodes[j].point[axis]
+	})
+
+	// Points equal to the median on this axis have to go to the right.
+	m := len(nodes) / 2
+	for m > 0 && nodes[m-1].point[axis] == nodes[m].point[axis] {
+		m--
+	}
+
+	var (
+		n    = nodes[m]
+		next = (axis + 1) % t.dims
+	)
+
+	n.axis = axis
+	n.left = t.build(nodes[:m], next)
+	n.right = t.build(nodes[m+1:], next)
+
+	return n
+}
+
+func (t *Tree) checkDims(p Point) {
+	if len(p) != t.dims {
+		panic(fmt.Sprintf("kdtree: point has %d dimensions, want %d", len(p), t.dims))
+	}
+}
+
+// findMin returns the node of the subtree rooted at n with the lowest
+// coordinate on axis.
+func findMin(n *node, axis int) *node {
+	if n == nil {
+		return nil
+	}
+
+	if n.axis == axis {
+		if n.left == nil {
+			return n
+		}
+
+		return findMin(n.left, axis)
+	}
+
+	min := n
+
+	for _, c := range []*node{findMin(n.left, axis), findMin(n.right, axis)} {
+		if c != nil && c.point[axis] < min.point[axis] {
+			min = c
+		}
+	}
+
+	return min
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+
+	l, r := height(n.left), height(n.right)
+
+	if l > r {
+		return l + 1
+	}
+
+	return r + 1
+}
+
+func equal(a, b Point) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// less orders points lexicographically.
+func less(a, b Point) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return false
+}
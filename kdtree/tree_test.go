@@ -0,0 +1,295 @@
+package kdtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verify checks the splitting invariants of all nodes and returns the number
+// of nodes.
+func verify(t *testing.T, tree *Tree) {
+	var walk func(n *node, axis int) []Point
+
+	walk = func(n *node, axis int) []Point {
+		if n == nil {
+			return nil
+		}
+
+		require.Equal(t, axis, n.axis)
+
+		var (
+			next  = (axis + 1) % tree.dims
+			left  = walk(n.left, next)
+			right = walk(n.right, next)
+		)
+
+		for _, p := range left {
+			require.Less(t, p[axis], n.point[axis], "left of %v", n.point)
+		}
+
+		for _, p := range right {
+			require.GreaterOrEqual(t, p[axis], n.point[axis], "right of %v", n.point)
+		}
+
+		return append(append(left, right...), n.point)
+	}
+
+	require.Len(t, walk(tree.root, 0), tree.size)
+}
+
+func randomPoints(rng *rand.Rand, n, dims int) []Point {
+	res := make([]Point, n)
+
+	for i := range res {
+		res[i] = make(Point, dims)
+
+		// Coarse coordinates produce lots of ties.
+		for j := range res[i] {
+			res[i][j] = float64(rng.Intn(50))
+		}
+	}
+
+	return res
+}
+
+// bruteForce returns the distances of the k nearest points.
+func bruteForce(points map[[3]float64]Point, m Metric, q Point, k int) []float64 {
+	var res []float64
+
+	for _, p := range points {
+		res = append(res, m.Distance(q, p))
+	}
+
+	sort.Float64s(res)
+
+	if len(res) > k {
+		res = res[:k]
+	}
+
+	return res
+}
+
+func key(p Point) [3]float64 {
+	var k [3]float64
+	copy(k[:], p)
+
+	return k
+}
+
+func TestTree_Insert(t *testing.T) {
+	tree := NewKDTree(2)
+
+	tree.Insert(Point{5, 5}, "a")
+	tree.Insert(Point{2, 8}, "b")
+	tree.Insert(Point{8, 1}, "c")
+	tree.Insert(Point{5, 2}, "d")
+
+	assert.Equal(t, 4, tree.Len())
+	assert.Equal(t, 2, tree.Height())
+	verify(t, tree)
+
+	// Equal coordinates on the splitting axis go right.
+	assert.Equal(t, Point{5, 2}, tree.root.right.right.point)
+
+	tree.Insert(Point{5, 2}, "e")
+
+	v, ok := tree.Search(Point{5, 2})
+	assert.True(t, ok)
+	assert.Equal(t, "e", v)
+	assert.Equal(t, 4, tree.Len())
+
+	_, ok = tree.Search(Point{5, 3})
+	assert.False(t, ok)
+
+	t.Run("points are copied", func(t *testing.T) {
+		p := Point{1, 1}
+		tree.Insert(p, "f")
+		p[0] = 100
+
+		_, ok := tree.Search(Point{1, 1})
+		assert.True(t, ok)
+	})
+
+	t.Run("wrong dimensions panic", func(t *testing.T) {
+		assert.Panics(t, func() { NewKDTree(0) })
+		assert.Panics(t, func() { tree.Insert(Point{1}, nil) })
+		assert.Panics(t, func() { tree.Nearest(Point{1, 2, 3}) })
+	})
+}
+
+func TestTree_Load(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		var (
+			tree   = NewKDTree(3)
+			points []Result
+			unique = make(map[[3]float64]int)
+		)
+
+		for i, p := range randomPoints(rng, n, 3) {
+			points = append(points, Result{Point: p, Payload: i})
+			unique[key(p)] = i
+		}
+
+		tree.Insert(Point{-1, -1, -1}, "replaced")
+		tree.Load(points)
+
+		verify(t, tree)
+		assert.Equal(t, len(unique), tree.Len())
+
+		for k, i := range unique {
+			v, ok := tree.Search(k[:])
+
+			assert.True(t, ok)
+			assert.Equal(t, i, v, "last duplicate wins")
+		}
+
+		// Ties on the splitting axis shift the median, so the tree isn't
+		// perfectly balanced.
+		if n > 0 {
+			assert.LessOrEqual(t, float64(tree.Height()), 2*math.Log2(float64(n)))
+		}
+	}
+}
+
+func TestTree_Delete(t *testing.T) {
+	t.Run("delete of non-existing point", func(t *testing.T) {
+		tree := NewKDTree(2)
+
+		assert.False(t, tree.Delete(Point{1, 1}))
+
+		tree.Insert(Point{1, 1}, nil)
+
+		assert.False(t, tree.Delete(Point{1, 2}))
+		assert.Equal(t, 1, tree.Len())
+	})
+
+	t.Run("random deletes keep invariants", func(t *testing.T) {
+		var (
+			rng    = rand.New(rand.NewSource(2))
+			tree   = NewKDTree(2)
+			points = make(map[[3]float64]Point)
+		)
+
+		for _, p := range randomPoints(rng, 500, 2) {
+			tree.Insert(p, nil)
+			points[key(p)] = p
+		}
+
+		for _, p := range randomPoints(rng, 1000, 2) {
+			_, exists := points[key(p)]
+
+			assert.Equal(t, exists, tree.Delete(p))
+			delete(points, key(p))
+
+			_, ok := tree.Search(p)
+			assert.False(t, ok)
+		}
+
+		verify(t, tree)
+		assert.Equal(t, len(points), tree.Len())
+
+		for _, p := range points {
+			_, ok := tree.Search(p)
+			assert.True(t, ok)
+		}
+	})
+}
+
+func TestTree_Nearest(t *testing.T) {
+	tree := NewKDTree(2)
+
+	_, ok := tree.Nearest(Point{0, 0})
+	assert.False(t, ok)
+
+	for i, p := range []Point{{2, 3}, {5, 4}, {9, 6}, {4, 7}, {8, 1}, {7, 2}} {
+		tree.Insert(p, i)
+	}
+
+	r, ok := tree.Nearest(Point{9, 2})
+	assert.True(t, ok)
+	assert.Equal(t, Point{8, 1}, r.Point)
+	assert.Equal(t, 4, r.Payload)
+	assert.InDelta(t, math.Sqrt2, r.Distance, 1e-9)
+
+	res := tree.KNearest(Point{5, 5}, 3)
+	require.Len(t, res, 3)
+	assert.Equal(t, Point{5, 4}, res[0].Point)
+	assert.Equal(t, Point{4, 7}, res[1].Point)
+
+	assert.Len(t, tree.KNearest(Point{0, 0}, 10), 6)
+	assert.Empty(t, tree.KNearest(Point{0, 0}, 0))
+}
+
+func TestTree_KNearest_random(t *testing.T) {
+	for _, m := range []Metric{Euclidean{}, Manhattan{}, Chebyshev{}} {
+		for _, dims := range []int{2, 3} {
+			var (
+				rng    = rand.New(rand.NewSource(int64(dims)))
+				tree   = NewKDTree(dims, WithMetric(m))
+				points = make(map[[3]float64]Point)
+			)
+
+			for _, p := range randomPoints(rng, 1000, dims) {
+				tree.Insert(p, nil)
+				points[key(p)] = p
+			}
+
+			for _, q := range randomPoints(rng, 50, dims) {
+				q[0] += 0.5
+
+				for _, k := range []int{1, 5, 20} {
+					var got []float64
+					for _, r := range tree.KNearest(q, k) {
+						got = append(got, r.Distance)
+						require.Equal(t, m.Distance(q, r.Point), r.Distance)
+					}
+
+					require.Equal(t, bruteForce(points, m, q, k), got, "%T, %d dims, k=%d", m, dims, k)
+				}
+			}
+		}
+	}
+}
+
+func TestTree_RangeSearch(t *testing.T) {
+	var (
+		rng    = rand.New(rand.NewSource(3))
+		tree   = NewKDTree(2)
+		points = make(map[[3]float64]Point)
+	)
+
+	for _, p := range randomPoints(rng, 1000, 2) {
+		tree.Insert(p, nil)
+		points[key(p)] = p
+	}
+
+	for i := 0; i < 50; i++ {
+		var (
+			lo  = randomPoints(rng, 1, 2)[0]
+			box = Box{Min: lo, Max: Point{lo[0] + float64(rng.Intn(20)), lo[1] + float64(rng.Intn(20))}}
+		)
+
+		var want []Point
+		for _, p := range points {
+			if box.Contains(p) {
+				want = append(want, p)
+			}
+		}
+
+		sort.Slice(want, func(i, j int) bool { return less(want[i], want[j]) })
+
+		var got []Point
+		for _, r := range tree.RangeSearch(box) {
+			got = append(got, r.Point)
+		}
+
+		assert.Equal(t, want, got, "box %v", box)
+	}
+}
@@ -0,0 +1,100 @@
+package kdtree
+
+import "math"
+
+// Point is a point in k-dimensional space.
+type Point []float64
+
+// Box is an axis-aligned box, including its bounds.
+type Box struct {
+	Min Point
+	Max Point
+}
+
+// Contains returns true if p lies within b.
+func (b Box) Contains(p Point) bool {
+	for i, v := range p {
+		if v < b.Min[i] || v > b.Max[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Result is a point found in the tree along with its payload. Distance is the
+// distance to the query point of a nearest neighbour search.
+type Result struct {
+	Point    Point
+	Payload  interface{}
+	Distance float64
+}
+
+// Metric measures distances between points.
+type Metric interface {
+	// Distance returns the distance between a and b.
+	Distance(a, b Point) float64
+
+	// AxisDistance returns the distance between two points which only differ
+	// by delta on a single axis. It has to be a lower bound of Distance for
+	// all points whose coordinates differ by at least delta on that axis.
+	AxisDistance(delta float64) float64
+}
+
+// Euclidean is the straight-line distance.
+type Euclidean struct{}
+
+// Distance implements Metric.
+func (Euclidean) Distance(a, b Point) float64 {
+	var sum float64
+
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
+
+// AxisDistance implements Metric.
+func (Euclidean) AxisDistance(delta float64) float64 {
+	return math.Abs(delta)
+}
+
+// Manhattan is the sum of the distances on all axes.
+type Manhattan struct{}
+
+// Distance implements Metric.
+func (Manhattan) Distance(a, b Point) float64 {
+	var sum float64
+
+	for i := range a {
+		sum += math.Abs(a[i] - b[i])
+	}
+
+	return sum
+}
+
+// AxisDistance implements Metric.
+func (Manhattan) AxisDistance(delta float64) float64 {
+	return math.Abs(delta)
+}
+
+// Chebyshev is the greatest distance on any axis.
+type Chebyshev struct{}
+
+// Distance implements Metric.
+func (Chebyshev) Distance(a, b Point) float64 {
+	var max float64
+
+	for i := range a {
+		max = math.Max(max, math.Abs(a[i]-b[i]))
+	}
+
+	return max
+}
+
+// AxisDistance implements Metric.
+func (Chebyshev) AxisDistance(delta float64) float64 {
+	return math.Abs(delta)
+}
@@ -0,0 +1,24 @@
+package llrb
+
+import (
+	"testing"
+
+	"github.com/obitech/go-trees/internal/bench"
+)
+
+var impls = []bench.Impl{
+	bench.RedBlack,
+	{Name: "llrb", New: func() bench.Map { return NewLLRBTree() }},
+}
+
+func BenchmarkUpsert(b *testing.B) {
+	bench.Upsert(b, impls...)
+}
+
+func BenchmarkSearch(b *testing.B) {
+	bench.Search(b, impls...)
+}
+
+func BenchmarkDelete(b *testing.B) {
+	bench.Delete(b, impls...)
+}
@@ -0,0 +1,105 @@
+package llrb
+
+// Delete deletes the node with the given key.
+func (t *Tree) Delete(key Key) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Deletion restructures the tree on the way down, which only works if
+	// the key is known to exist.
+	if search(t.root, key) == nil {
+		return
+	}
+
+	if !isRed(t.root.left) && !isRed(t.root.right) {
+		t.root.red = true
+	}
+
+	t.root = remove(t.root, key)
+	t.size--
+
+	if t.root != nil {
+		t.root.red = false
+	}
+}
+
+// remove deletes key, which has to exist, from the subtree rooted at n and
+// returns its new root. Either n or its left child is red, so the key is never
+// removed from a 2-node.
+func remove(n *node, key Key) *node {
+	if key.Less(n.key) {
+		if !isRed(n.left) && !isRed(n.left.left) {
+			n = moveRedLeft(n)
+		}
+
+		n.left = remove(n.left, key)
+
+		return fixUp(n)
+	}
+
+	if isRed(n.left) {
+		n = rotateRight(n)
+	}
+
+	if !n.key.Less(key) && n.right == nil {
+		return nil
+	}
+
+	if !isRed(n.right) && !isRed(n.right.left) {
+		n = moveRedRight(n)
+	}
+
+	if n.key.Less(key) {
+		n.right = remove(n.right, key)
+	} else {
+		// Replace n by its successor.
+		m := min(n.right)
+		n.key, n.payload = m.key, m.payload
+		n.right = removeMin(n.right)
+	}
+
+	return fixUp(n)
+}
+
+// removeMin deletes the lowest key from the subtree rooted at n and returns
+// its new root.
+func removeMin(n *node) *node {
+	if n.left == nil {
+		return nil
+	}
+
+	if !isRed(n.left) && !isRed(n.left.left) {
+		n = moveRedLeft(n)
+	}
+
+	n.left = removeMin(n.left)
+
+	return fixUp(n)
+}
+
+// moveRedLeft makes the left child of n or one of its children red, assuming
+// n is red and both its children are black.
+func moveRedLeft(n *node) *node {
+	flip(n)
+
+	if isRed(n.right.left) {
+		n.right = rotateRight(n.right)
+		n = rotateLeft(n)
+		flip(n)
+	}
+
+	return n
+}
+
+// moveRedRight makes the right child of n or one of its children red, assuming
+// n is red and both its children are black.
+func moveRedRight(n *node) *node {
+	flip(n)
+
+	if isRed(n.left.left) {
+		n = rotateRight(n)
+		flip(n)
+	}
+
+	return n
+}
@@ -0,0 +1,30 @@
+package llrb
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+func (t *Tree) Upsert(key Key, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = t.insert(t.root, key, payload)
+	t.root.red = false
+}
+
+// insert adds key to the subtree rooted at n, or updates its payload, and
+// returns the new root of the subtree.
+func (t *Tree) insert(n *node, key Key, payload interface{}) *node {
+	if n == nil {
+		t.size++
+		return &node{key: key, payload: payload, red: true}
+	}
+
+	switch {
+	case key.Less(n.key):
+		n.left = t.insert(n.left, key, payload)
+	case n.key.Less(key):
+		n.right = t.insert(n.right, key, payload)
+	default:
+		n.payload = payload
+	}
+
+	return fixUp(n)
+}
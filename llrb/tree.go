@@ -0,0 +1,210 @@
+// Package llrb implements a left-leaning red-black tree, Sedgewick's variant
+// of the red-black tree which corresponds one to one to a 2-3 tree. Red links
+// only ever lean left, which removes most of the case analysis of the classic
+// algorithm: insertion and deletion are short recursive functions without
+// parent pointers or a sentinel. All operations run in O(lg n) time.
+package llrb
+
+// NewLLRBTree returns a new left-leaning red-black tree. Unless WithoutLocking
+// is passed, all operations on the tree are safe to be accessed concurrently.
+func NewLLRBTree(opts ...Option) *Tree {
+	t := &Tree{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree. Runs in O(1) time.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Root returns the payload of the root node of the tree.
+func (t *Tree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a single node has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return height(t.root)
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *Tree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return min(t.root).payload
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *Tree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return max(t.root).payload
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *Tree) Search(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if n := search(t.root, key); n != nil {
+		return n.payload
+	}
+
+	return nil
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (t *Tree) Successor(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	// The lowest ancestor whose left subtree holds key.
+	var candidate *node
+
+	for n := t.root; n != nil; {
+		switch {
+		case key.Less(n.key):
+			candidate = n
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		case n.right != nil:
+			return min(n.right).payload
+		case candidate != nil:
+			return candidate.payload
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func search(n *node, key Key) *node {
+	for n != nil {
+		switch {
+		case key.Less(n.key):
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+func min(n *node) *node {
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+func max(n *node) *node {
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+
+	l, r := height(n.left), height(n.right)
+
+	if l > r {
+		return l + 1
+	}
+
+	return r + 1
+}
+
+func isRed(n *node) bool {
+	return n != nil && n.red
+}
+
+// rotateLeft turns a right-leaning red link of n into a left-leaning one and
+// returns the new root of the subtree.
+func rotateLeft(n *node) *node {
+	x := n.right
+	n.right = x.left
+	x.left = n
+	x.red = n.red
+	n.red = true
+
+	return x
+}
+
+// rotateRight turns a left-leaning red link of n into a right-leaning one and
+// returns the new root of the subtree.
+func rotateRight(n *node) *node {
+	x := n.left
+	n.left = x.right
+	x.right = n
+	x.red = n.red
+	n.red = true
+
+	return x
+}
+
+// flip flips the colors of n and its children, which splits a temporary 4-node
+// or merges three 2-nodes into one.
+func flip(n *node) {
+	n.red = !n.red
+	n.left.red = !n.left.red
+	n.right.red = !n.right.red
+}
+
+// fixUp restores the left-leaning invariants at n on the way up the tree and
+// returns the new root of the subtree.
+func fixUp(n *node) *node {
+	if isRed(n.right) && !isRed(n.left) {
+		n = rotateLeft(n)
+	}
+
+	if isRed(n.left) && isRed(n.left.left) {
+		n = rotateRight(n)
+	}
+
+	if isRed(n.left) && isRed(n.right) {
+		flip(n)
+	}
+
+	return n
+}
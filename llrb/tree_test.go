@@ -0,0 +1,168 @@
+package llrb
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the number of keys and the invariants of the corresponding
+// 2-3 tree: red links lean left, no node has two red links and all paths have
+// the same number of black links.
+func verify(t *testing.T, tree *Tree) {
+	var walk func(n *node) (int, int)
+
+	// walk returns the number of nodes and the black height of the subtree.
+	walk = func(n *node) (int, int) {
+		if n == nil {
+			return 0, 0
+		}
+
+		require.False(t, isRed(n.right), "%v has a right-leaning red link", n.key)
+		require.False(t, isRed(n) && isRed(n.left), "%v has two red links in a row", n.key)
+
+		ls, lb := walk(n.left)
+		rs, rb := walk(n.right)
+
+		require.Equal(t, lb, rb, "%v has unequal black heights", n.key)
+
+		if !n.red {
+			lb++
+		}
+
+		return ls + rs + 1, lb
+	}
+
+	require.False(t, isRed(tree.root), "root is red")
+
+	size, _ := walk(tree.root)
+	require.Equal(t, tree.size, size)
+
+	if size > 0 {
+		require.LessOrEqual(t, float64(tree.Height()), 2*math.Log2(float64(size+1)))
+	}
+}
+
+func newTree(keys ...int) *Tree {
+	tree := NewLLRBTree()
+
+	for _, k := range keys {
+		tree.Upsert(myInt(k), k)
+	}
+
+	return tree
+}
+
+// shape renders the subtree rooted at n, marking red nodes with a star, e.g.
+// "2(1* -)" for a 3-node of the keys 1 and 2.
+func shape(n *node) string {
+	if n == nil {
+		return "-"
+	}
+
+	s := fmt.Sprint(n.key)
+	if n.red {
+		s += "*"
+	}
+
+	if n.left == nil && n.right == nil {
+		return s
+	}
+
+	return fmt.Sprintf("%s(%s %s)", s, shape(n.left), shape(n.right))
+}
+
+func TestTree_Upsert(t *testing.T) {
+	tt := []struct {
+		name string
+		keys []int
+		want string
+	}{
+		{name: "second key forms 3-node leaning left", keys: []int{1, 2}, want: "2(1* -)"},
+		{name: "ascending keys split 4-node", keys: []int{1, 2, 3}, want: "2(1 3)"},
+		{name: "descending keys split 4-node", keys: []int{3, 2, 1}, want: "2(1 3)"},
+		{name: "middle key splits 4-node", keys: []int{3, 1, 2}, want: "2(1 3)"},
+		{name: "split passes middle key up into 2-node", keys: []int{1, 2, 3, 4, 5}, want: "4(2*(1 3) 5)"},
+		{name: "split of root grows the tree", keys: []int{1, 2, 3, 4, 5, 6, 7}, want: "4(2(1 3) 6(5 7))"},
+		{name: "updating a key keeps the shape", keys: []int{1, 2, 2}, want: "2(1* -)"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTree(tc.keys...)
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			verify(t, tree)
+		})
+	}
+}
+
+func TestTree_Delete(t *testing.T) {
+	tt := []struct {
+		name string
+		keys []int
+		key  int
+		want string
+	}{
+		{name: "deleting from 3-node leaf leaves 2-node", keys: []int{1, 2}, key: 1, want: "2"},
+		{name: "deleting upper key of 3-node leaf", keys: []int{1, 2}, key: 2, want: "1"},
+		{name: "2-node leaf borrows from its sibling", keys: []int{1, 2, 3}, key: 1, want: "3(2* -)"},
+		{name: "inner key is replaced by its successor", keys: []int{1, 2, 3}, key: 2, want: "3(1* -)"},
+		{name: "missing key leaves tree unchanged", keys: []int{1, 2, 3}, key: 4, want: "2(1 3)"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTree(tc.keys...)
+
+			tree.Delete(myInt(tc.key))
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			assert.Nil(t, tree.Search(myInt(tc.key)))
+			verify(t, tree)
+		})
+	}
+}
+
+func TestTree_random(t *testing.T) {
+	var (
+		rng  = rand.New(rand.NewSource(1))
+		tree = NewLLRBTree(WithoutLocking())
+		want = make(map[int]bool)
+	)
+
+	for i := 0; i < 5000; i++ {
+		k := rng.Intn(500)
+
+		if rng.Intn(3) == 0 {
+			tree.Delete(myInt(k))
+			delete(want, k)
+		} else {
+			tree.Upsert(myInt(k), i)
+			want[k] = true
+		}
+
+		if i%100 == 0 {
+			verify(t, tree)
+		}
+	}
+
+	verify(t, tree)
+	require.Equal(t, len(want), tree.Len())
+}
+
+func TestNewLLRBTree_WithoutLocking(t *testing.T) {
+	tree := NewLLRBTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+}
@@ -0,0 +1,31 @@
+package llrb
+
+import (
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the tree have to implement. It is shared with
+// package redblack, so the same key types work with both trees.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the tree.
+type Result = redblack.Result
+
+// Tree represents a left-leaning red-black tree with a root node and a lock to
+// protect concurrent access.
+type Tree struct {
+	lock lock.RWMutex
+	root *node
+	size int
+}
+
+// node carries neither a parent pointer nor a full color field: the color of
+// a node is the color of the link from its parent.
+type node struct {
+	key     Key
+	payload interface{}
+	left    *node
+	right   *node
+	red     bool
+}
@@ -0,0 +1,99 @@
+package merkle
+
+import "bytes"
+
+// Difference is a key whose value differs between two trees. Value is nil if
+// the key is missing in the tree Diff is called on, Other is nil if it's
+// missing in the other tree.
+type Difference struct {
+	Key, Value, Other []byte
+}
+
+// Diff returns all keys whose values differ between t and other, ordered by
+// key. Subtrees with equal hashes are skipped, so the cost depends on the
+// number of differences rather than on the size of the trees.
+func (t *Tree) Diff(other *Tree) []Difference {
+	// Nodes are immutable, so the roots can be compared without holding
+	// either lock.
+	t.lock.RLock()
+	a := t.root
+	t.lock.RUnlock()
+
+	other.lock.RLock()
+	b := other.root
+	other.lock.RUnlock()
+
+	d := &differ{}
+	d.diff(a, b)
+
+	return d.res
+}
+
+// differ collects the differences between two subtrees. visited counts the
+// pairs of subtrees compared.
+type differ struct {
+	res     []Difference
+	visited int
+}
+
+// diff compares two subtrees. The node with the higher priority is the root of
+// the merged key range, so the other subtree is split at its key and both
+// halves are compared recursively. Identical subtrees end up aligned, where
+// their hashes stop the recursion.
+func (d *differ) diff(a, b *node) {
+	d.visited++
+
+	switch {
+	case hashOf(a) == hashOf(b):
+	case a == nil:
+		walk(b, func(key, value []byte) bool {
+			d.add(key, nil, value)
+			return true
+		})
+	case b == nil:
+		walk(a, func(key, value []byte) bool {
+			d.add(key, value, nil)
+			return true
+		})
+	case higher(b, a):
+		l, m, r := split(a, b.key)
+
+		d.diff(l, b.left)
+
+		if m == nil {
+			d.add(b.key, nil, b.value)
+		} else if !bytes.Equal(m.value, b.value) {
+			d.add(b.key, m.value, b.value)
+		}
+
+		d.diff(r, b.right)
+	default:
+		l, m, r := split(b, a.key)
+
+		d.diff(a.left, l)
+
+		if m == nil {
+			d.add(a.key, a.value, nil)
+		} else if !bytes.Equal(a.value, m.value) {
+			d.add(a.key, a.value, m.value)
+		}
+
+		d.diff(a.right, r)
+	}
+}
+
+// add records a difference, copying the slices of the nodes. Missing values
+// stay nil.
+func (d *differ) add(key, value, other []byte) {
+	diff := Difference{Key: clone(key)}
+
+	if value != nil {
+		diff.Value = clone(value)
+	}
+
+	if other != nil {
+		diff.Other = clone(other)
+	}
+
+	d.res = append(d.res, diff)
+}
@@ -0,0 +1,85 @@
+package merkle
+
+import "bytes"
+
+// Proof proves that a key is stored with a value in a tree with a given root
+// hash. It holds the hashes of the children of the node storing the key, and
+// the entries and sibling hashes of all of its ancestors.
+type Proof struct {
+	Key, Value  []byte
+	Left, Right Hash
+	// Path holds the ancestors of the node, starting with its parent.
+	Path []Step
+}
+
+// Step is an ancestor of the node proven by a Proof.
+type Step struct {
+	Key, Value []byte
+	// Sibling is the hash of the child of the ancestor which is not on the
+	// path to the proven node.
+	Sibling Hash
+	// Right is true if the path continues from the right child of the
+	// ancestor.
+	Right bool
+}
+
+// Prove returns a proof that key is stored in the tree along with its value.
+// Returns false if the key doesn't exist.
+func (t *Tree) Prove(key []byte) (Proof, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var path []Step
+
+	for n := t.root; n != nil; {
+		c := bytes.Compare(key, n.key)
+
+		if c == 0 {
+			// Reverse the path, so it leads from the node to the root.
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+
+			return Proof{
+				Key:   clone(n.key),
+				Value: clone(n.value),
+				Left:  hashOf(n.left),
+				Right: hashOf(n.right),
+				Path:  path,
+			}, true
+		}
+
+		step := Step{Key: clone(n.key), Value: clone(n.value), Right: c > 0}
+
+		if c < 0 {
+			step.Sibling, n = hashOf(n.right), n.left
+		} else {
+			step.Sibling, n = hashOf(n.left), n.right
+		}
+
+		path = append(path, step)
+	}
+
+	return Proof{}, false
+}
+
+// Verify returns true if the proof shows that its key is stored with its
+// value in a tree with the given root hash.
+func (p Proof) Verify(root Hash) bool {
+	h := hashNode(p.Key, p.Value, p.Left, p.Right)
+
+	for _, s := range p.Path {
+		// The path has to be consistent with the order of the keys.
+		if c := bytes.Compare(p.Key, s.Key); c == 0 || (c > 0) != s.Right {
+			return false
+		}
+
+		if s.Right {
+			h = hashNode(s.Key, s.Value, s.Sibling, h)
+		} else {
+			h = hashNode(s.Key, s.Value, h, s.Sibling)
+		}
+	}
+
+	return h == root
+}
@@ -0,0 +1,280 @@
+// Package merkle implements a Merkle search tree, a treap whose nodes carry a
+// hash of their entry and of the hashes of their children. Priorities are
+// derived from the hash of the keys, so the shape of the tree only depends on
+// its contents: trees holding the same entries have the same root hash, no
+// matter in which order the entries were inserted. This allows replicas to
+// verify each other's state with a single hash, to prove that an entry is part
+// of a tree, and to find the differences between two trees by only walking
+// subtrees whose hashes differ.
+//
+// Nodes are never modified after construction. Updates copy the path from the
+// root to the modified node, so reading a tree never observes a concurrent
+// update.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// Hash is a SHA-256 hash of a subtree. The zero Hash is the hash of an empty
+// tree.
+type Hash [sha256.Size]byte
+
+// Tree represents a Merkle search tree with a root node and a lock to protect
+// concurrent access.
+type Tree struct {
+	lock lock.RWMutex
+	root *node
+	size int
+}
+
+// node holds a key along with its value. Its priority is derived from the
+// key, and its hash covers the key, the value and the hashes of both
+// children.
+type node struct {
+	key, value  []byte
+	priority    uint64
+	hash        Hash
+	left, right *node
+}
+
+// NewMerkleTree returns a new Merkle search tree. Unless WithoutLocking is
+// passed, all operations on the tree are safe to be accessed concurrently.
+func NewMerkleTree(opts ...Option) *Tree {
+	t := &Tree{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// RootHash returns the hash of the tree, which is equal for all trees holding
+// the same entries.
+func (t *Tree) RootHash() Hash {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return hashOf(t.root)
+}
+
+// Get returns a copy of the value stored for key and whether it exists.
+func (t *Tree) Get(key []byte) ([]byte, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	for n := t.root; n != nil; {
+		switch c := bytes.Compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return clone(n.value), true
+		}
+	}
+
+	return nil, false
+}
+
+// Put stores a copy of value under a copy of key, replacing any value stored
+// before.
+func (t *Tree) Put(key, value []byte) {
+	n := newNode(clone(key), clone(value), nil, nil)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var inserted bool
+
+	t.root, inserted = put(t.root, n)
+
+	if inserted {
+		t.size++
+	}
+}
+
+// Delete removes key from the tree. Returns false if the key doesn't exist.
+func (t *Tree) Delete(key []byte) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	root, ok := remove(t.root, key)
+	if !ok {
+		return false
+	}
+
+	t.root = root
+	t.size--
+
+	return true
+}
+
+// Walk calls fn for all entries in ascending order of their keys until fn
+// returns false. The slices passed to fn must not be modified.
+func (t *Tree) Walk(fn func(key, value []byte) bool) {
+	t.lock.RLock()
+	root := t.root
+	t.lock.RUnlock()
+
+	walk(root, fn)
+}
+
+func walk(n *node, fn func(key, value []byte) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return walk(n.left, fn) && fn(n.key, n.value) && walk(n.right, fn)
+}
+
+// put returns a copy of the subtree rooted at n with x inserted, and whether
+// the key of x didn't exist before.
+func put(n, x *node) (*node, bool) {
+	if n == nil {
+		return x, true
+	}
+
+	c := bytes.Compare(x.key, n.key)
+
+	switch {
+	case c == 0:
+		return newNode(n.key, x.value, n.left, n.right), false
+	case higher(x, n):
+		l, m, r := split(n, x.key)
+		return newNode(x.key, x.value, l, r), m == nil
+	case c < 0:
+		l, inserted := put(n.left, x)
+		return newNode(n.key, n.value, l, n.right), inserted
+	default:
+		r, inserted := put(n.right, x)
+		return newNode(n.key, n.value, n.left, r), inserted
+	}
+}
+
+// remove returns a copy of the subtree rooted at n without key, and whether
+// the key existed.
+func remove(n *node, key []byte) (*node, bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	switch c := bytes.Compare(key, n.key); {
+	case c < 0:
+		l, ok := remove(n.left, key)
+		if !ok {
+			return n, false
+		}
+
+		return newNode(n.key, n.value, l, n.right), true
+	case c > 0:
+		r, ok := remove(n.right, key)
+		if !ok {
+			return n, false
+		}
+
+		return newNode(n.key, n.value, n.left, r), true
+	default:
+		return merge(n.left, n.right), true
+	}
+}
+
+// split returns copies of the subtree rooted at n holding all keys less and
+// greater than key, along with the node holding key, if any.
+func split(n *node, key []byte) (l, m, r *node) {
+	if n == nil {
+		return nil, nil, nil
+	}
+
+	switch c := bytes.Compare(key, n.key); {
+	case c < 0:
+		l, m, r = split(n.left, key)
+		return l, m, newNode(n.key, n.value, r, n.right)
+	case c > 0:
+		l, m, r = split(n.right, key)
+		return newNode(n.key, n.value, n.left, l), m, r
+	default:
+		return n.left, n, n.right
+	}
+}
+
+// merge joins two subtrees where all keys of l are less than all keys of r.
+func merge(l, r *node) *node {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case higher(l, r):
+		return newNode(l.key, l.value, l.left, merge(l.right, r))
+	default:
+		return newNode(r.key, r.value, merge(l, r.left), r.right)
+	}
+}
+
+// higher returns true if a has to be placed above b. Ties between priorities
+// are broken by the keys, so the shape of the tree is unique.
+func higher(a, b *node) bool {
+	return a.priority > b.priority || a.priority == b.priority && bytes.Compare(a.key, b.key) < 0
+}
+
+func newNode(key, value []byte, left, right *node) *node {
+	sum := sha256.Sum256(key)
+
+	return &node{
+		key:      key,
+		value:    value,
+		priority: binary.BigEndian.Uint64(sum[:8]),
+		hash:     hashNode(key, value, hashOf(left), hashOf(right)),
+		left:     left,
+		right:    right,
+	}
+}
+
+// hashNode hashes an entry along with the hashes of its children. Key and
+// value are length-prefixed, so their boundary is unambiguous.
+func hashNode(key, value []byte, left, right Hash) Hash {
+	var (
+		h   = sha256.New()
+		buf [binary.MaxVarintLen64]byte
+	)
+
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(key)))])
+	h.Write(key)
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(value)))])
+	h.Write(value)
+	h.Write(left[:])
+	h.Write(right[:])
+
+	var sum Hash
+	h.Sum(sum[:0])
+
+	return sum
+}
+
+func hashOf(n *node) Hash {
+	if n == nil {
+		return Hash{}
+	}
+
+	return n.hash
+}
+
+// clone returns a copy of b which is never nil, so empty values can be told
+// apart from missing ones.
+func clone(b []byte) []byte {
+	return append(make([]byte, 0, len(b)), b...)
+}
@@ -0,0 +1,296 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verify checks the order of the keys, the heap order of the priorities and
+// the hashes of all nodes.
+func verify(t *testing.T, tree *Tree) {
+	var (
+		size int
+		walk func(n *node, low, high []byte)
+	)
+
+	walk = func(n *node, low, high []byte) {
+		if n == nil {
+			return
+		}
+
+		size++
+
+		if low != nil {
+			require.True(t, bytes.Compare(low, n.key) < 0, "%q below %q", n.key, low)
+		}
+
+		if high != nil {
+			require.True(t, bytes.Compare(n.key, high) < 0, "%q above %q", n.key, high)
+		}
+
+		for _, c := range []*node{n.left, n.right} {
+			if c != nil {
+				require.True(t, higher(n, c), "%q below child %q", n.key, c.key)
+			}
+		}
+
+		require.Equal(t, hashNode(n.key, n.value, hashOf(n.left), hashOf(n.right)), n.hash)
+
+		walk(n.left, low, n.key)
+		walk(n.right, n.key, high)
+	}
+
+	walk(tree.root, nil, nil)
+	require.Equal(t, tree.size, size)
+}
+
+func key(i int) []byte {
+	return []byte(fmt.Sprintf("key-%05d", i))
+}
+
+func TestTree(t *testing.T) {
+	var (
+		rng      = rand.New(rand.NewSource(1))
+		tree     = NewMerkleTree()
+		contents = make(map[string]string)
+	)
+
+	assert.Equal(t, Hash{}, tree.RootHash())
+
+	for i := 0; i < 5000; i++ {
+		k := key(rng.Intn(1000))
+
+		if rng.Intn(3) == 0 {
+			_, ok := contents[string(k)]
+			require.Equal(t, ok, tree.Delete(k))
+			delete(contents, string(k))
+		} else {
+			v := fmt.Sprint(i)
+			tree.Put(k, []byte(v))
+			contents[string(k)] = v
+		}
+
+		if i%500 == 0 {
+			verify(t, tree)
+		}
+	}
+
+	verify(t, tree)
+	assert.Equal(t, len(contents), tree.Len())
+
+	for k, v := range contents {
+		got, ok := tree.Get([]byte(k))
+		require.True(t, ok)
+		require.Equal(t, v, string(got))
+	}
+
+	_, ok := tree.Get([]byte("missing"))
+	assert.False(t, ok)
+	assert.False(t, tree.Delete([]byte("missing")))
+
+	var keys []string
+
+	tree.Walk(func(key, value []byte) bool {
+		keys = append(keys, string(key))
+		return len(keys) < 10
+	})
+
+	assert.Len(t, keys, 10)
+	assert.True(t, sort.StringsAreSorted(keys))
+}
+
+func TestTree_Put_copies(t *testing.T) {
+	var (
+		tree       = NewMerkleTree(WithoutLocking())
+		key, value = []byte("key"), []byte("value")
+	)
+
+	tree.Put(key, value)
+	key[0], value[0] = 'x', 'x'
+
+	got, ok := tree.Get([]byte("key"))
+	require.True(t, ok)
+	assert.Equal(t, []byte("value"), got)
+
+	got[0] = 'x'
+	got, _ = tree.Get([]byte("key"))
+	assert.Equal(t, []byte("value"), got)
+
+	t.Run("empty values are stored", func(t *testing.T) {
+		tree.Put([]byte("empty"), nil)
+
+		got, ok := tree.Get([]byte("empty"))
+		assert.True(t, ok)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+	})
+}
+
+func TestTree_RootHash(t *testing.T) {
+	var (
+		a, b = NewMerkleTree(), NewMerkleTree()
+		rng  = rand.New(rand.NewSource(2))
+	)
+
+	for _, i := range rng.Perm(500) {
+		a.Put(key(i), key(i))
+	}
+
+	// Insert the same entries in a different order, with detours.
+	for _, i := range rng.Perm(600) {
+		b.Put(key(i), []byte("other"))
+	}
+
+	for i := 500; i < 600; i++ {
+		b.Delete(key(i))
+	}
+
+	for _, i := range rng.Perm(500) {
+		b.Put(key(i), key(i))
+	}
+
+	assert.Equal(t, a.RootHash(), b.RootHash())
+
+	b.Put(key(0), []byte("changed"))
+	assert.NotEqual(t, a.RootHash(), b.RootHash())
+
+	b.Put(key(0), key(0))
+	assert.Equal(t, a.RootHash(), b.RootHash())
+
+	// Keys and values are length-prefixed in the hash.
+	c, d := NewMerkleTree(), NewMerkleTree()
+	c.Put([]byte("ab"), []byte("c"))
+	d.Put([]byte("a"), []byte("bc"))
+	assert.NotEqual(t, c.RootHash(), d.RootHash())
+}
+
+func TestTree_Prove(t *testing.T) {
+	tree := NewMerkleTree()
+
+	for i := 0; i < 1000; i++ {
+		tree.Put(key(i), []byte(fmt.Sprint(i)))
+	}
+
+	root := tree.RootHash()
+
+	for i := 0; i < 1000; i += 37 {
+		p, ok := tree.Prove(key(i))
+		require.True(t, ok)
+		assert.Equal(t, key(i), p.Key)
+		assert.Equal(t, fmt.Sprint(i), string(p.Value))
+		assert.True(t, p.Verify(root))
+	}
+
+	_, ok := tree.Prove([]byte("missing"))
+	assert.False(t, ok)
+
+	p, _ := tree.Prove(key(500))
+	require.NotEmpty(t, p.Path)
+
+	tt := []struct {
+		name   string
+		tamper func(p *Proof)
+	}{
+		{name: "value", tamper: func(p *Proof) { p.Value = []byte("forged") }},
+		{name: "key", tamper: func(p *Proof) { p.Key = key(501) }},
+		{name: "child", tamper: func(p *Proof) { p.Left[0] ^= 1 }},
+		{name: "sibling", tamper: func(p *Proof) { p.Path[0].Sibling[0] ^= 1 }},
+		{name: "direction", tamper: func(p *Proof) { p.Path[0].Right = !p.Path[0].Right }},
+		{name: "truncated path", tamper: func(p *Proof) { p.Path = p.Path[:len(p.Path)-1] }},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			forged, _ := tree.Prove(key(500))
+			tc.tamper(&forged)
+
+			assert.False(t, forged.Verify(root))
+		})
+	}
+
+	t.Run("proof is invalidated by updates", func(t *testing.T) {
+		tree.Put(key(1), []byte("changed"))
+
+		assert.True(t, p.Verify(root))
+		assert.False(t, p.Verify(tree.RootHash()))
+	})
+}
+
+func TestTree_Diff(t *testing.T) {
+	var (
+		rng  = rand.New(rand.NewSource(3))
+		a, b = NewMerkleTree(), NewMerkleTree()
+	)
+
+	for i := 0; i < 2000; i++ {
+		a.Put(key(i), key(i))
+		b.Put(key(i), key(i))
+	}
+
+	assert.Empty(t, a.Diff(b))
+	assert.Empty(t, a.Diff(a))
+
+	t.Run("single difference visits a path", func(t *testing.T) {
+		b.Put(key(1000), []byte("changed"))
+		defer b.Put(key(1000), key(1000))
+
+		d := &differ{}
+		d.diff(a.root, b.root)
+
+		assert.Equal(t, []Difference{{Key: key(1000), Value: key(1000), Other: []byte("changed")}}, d.res)
+		assert.Less(t, d.visited, 100)
+	})
+
+	var want []Difference
+
+	for _, i := range rng.Perm(2100)[:50] {
+		switch {
+		case i >= 2000:
+			b.Put(key(i), []byte("new"))
+			want = append(want, Difference{Key: key(i), Other: []byte("new")})
+		case i%2 == 0:
+			a.Delete(key(i))
+			want = append(want, Difference{Key: key(i), Other: key(i)})
+		default:
+			b.Put(key(i), []byte("changed"))
+			want = append(want, Difference{Key: key(i), Value: key(i), Other: []byte("changed")})
+		}
+	}
+
+	sort.Slice(want, func(i, j int) bool {
+		return bytes.Compare(want[i].Key, want[j].Key) < 0
+	})
+
+	assert.Equal(t, want, a.Diff(b))
+
+	reversed := b.Diff(a)
+	require.Len(t, reversed, len(want))
+
+	for i, d := range reversed {
+		assert.Equal(t, want[i], Difference{Key: d.Key, Value: d.Other, Other: d.Value})
+	}
+
+	t.Run("only differing subtrees are visited", func(t *testing.T) {
+		d := &differ{}
+		d.diff(a.root, b.root)
+
+		// Comparing every pair of subtrees would take more than one visit per
+		// node.
+		assert.Len(t, d.res, len(want))
+		assert.Less(t, d.visited, a.size/2)
+	})
+
+	t.Run("empty trees", func(t *testing.T) {
+		empty := NewMerkleTree()
+
+		assert.Len(t, empty.Diff(a), a.Len())
+		assert.Len(t, a.Diff(empty), a.Len())
+		assert.Empty(t, empty.Diff(NewMerkleTree()))
+	})
+}
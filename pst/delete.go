@@ -0,0 +1,58 @@
+package pst
+
+// Delete deletes the given point. Once the tree has shrunk below alpha times
+// its size at the last complete rebuild, it is rebuilt again, which takes
+// amortized O(lg n) time.
+func (t *Tree) Delete(p Point) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if search(t.root, p) == nil {
+		return
+	}
+
+	link := &t.root
+
+	for n := t.root; ; n = *link {
+		n.size--
+
+		if n.point == p {
+			*link = promote(n)
+			break
+		}
+
+		if p.less(n.split) {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+	}
+
+	if float64(size(t.root)) < alpha*float64(t.maxSize) {
+		t.root = rebuild(t.root, size(t.root))
+		t.maxSize = size(t.root)
+	}
+}
+
+// promote replaces the point of n by the higher point of its children, which
+// repeats down to a leaf, and returns the new root of the subtree. The size of
+// n has to be updated already.
+func promote(n *node) *node {
+	var child **node
+
+	switch {
+	case n.left == nil && n.right == nil:
+		return nil
+	case n.right == nil || n.left != nil && n.left.point.Y >= n.right.point.Y:
+		child = &n.left
+	default:
+		child = &n.right
+	}
+
+	c := *child
+	n.point, n.payload = c.point, c.payload
+	c.size--
+	*child = promote(c)
+
+	return n
+}
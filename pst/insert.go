@@ -0,0 +1,75 @@
+package pst
+
+// Upsert updates the payload of an existing point, or inserts a new one. If the
+// new node ends up too deep, the subtree of one of its ancestors is rebuilt,
+// which takes amortized O(lg n) time.
+func (t *Tree) Upsert(p Point, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if n := search(t.root, p); n != nil {
+		n.payload = payload
+		return
+	}
+
+	var (
+		path []*node
+		link = &t.root
+	)
+
+	for n := t.root; n != nil; n = *link {
+		n.size++
+
+		// The higher point stays, the lower one sinks further down.
+		if p.Y > n.point.Y {
+			p, n.point = n.point, p
+			payload, n.payload = n.payload, payload
+		}
+
+		if p.less(n.split) {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+
+		path = append(path, n)
+	}
+
+	*link = &node{point: p, payload: payload, split: p, size: 1}
+
+	if size(t.root) > t.maxSize {
+		t.maxSize = size(t.root)
+	}
+
+	if len(path) > maxDepth(size(t.root)) {
+		t.rebuildScapegoat(path, *link)
+	}
+}
+
+// rebuildScapegoat walks up from the too deep node z along path, the list of
+// its ancestors, and rebuilds the subtree of the first ancestor which isn't
+// alpha-weight-balanced.
+func (t *Tree) rebuildScapegoat(path []*node, z *node) {
+	child := z
+
+	for i := len(path) - 1; i >= 0; i-- {
+		p := path[i]
+
+		if float64(child.size) > alpha*float64(p.size) {
+			n := rebuild(p, p.size)
+
+			switch {
+			case i == 0:
+				t.root = n
+			case path[i-1].left == p:
+				path[i-1].left = n
+			default:
+				path[i-1].right = n
+			}
+
+			return
+		}
+
+		child = p
+	}
+}
@@ -0,0 +1,70 @@
+package pst
+
+// Query returns all points with X in [xmin, xmax] and Y of at least ymin, in no
+// particular order. Runs in O(lg n + k) time for k results.
+func (t *Tree) Query(xmin, xmax, ymin int64) []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var res []Result
+
+	query(t.root, xmin, xmax, ymin, &res)
+
+	return res
+}
+
+func query(n *node, xmin, xmax, ymin int64, res *[]Result) {
+	// All points below n are lower than n itself.
+	if n == nil || n.point.Y < ymin {
+		return
+	}
+
+	if xmin <= n.point.X && n.point.X <= xmax {
+		*res = append(*res, Result{Point: n.point, Payload: n.payload})
+	}
+
+	if xmin <= n.split.X {
+		query(n.left, xmin, xmax, ymin, res)
+	}
+
+	if n.split.X <= xmax {
+		query(n.right, xmin, xmax, ymin, res)
+	}
+}
+
+// Highest returns the point with the highest Y among all points with X in
+// [xmin, xmax]. Returns false if there is no such point.
+func (t *Tree) Highest(xmin, xmax int64) (Result, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var best *node
+
+	highest(t.root, xmin, xmax, &best)
+
+	if best == nil {
+		return Result{}, false
+	}
+
+	return Result{Point: best.point, Payload: best.payload}, true
+}
+
+func highest(n *node, xmin, xmax int64, best **node) {
+	if n == nil || *best != nil && n.point.Y <= (*best).point.Y {
+		return
+	}
+
+	if xmin <= n.point.X && n.point.X <= xmax {
+		// Nothing below n can beat it.
+		*best = n
+		return
+	}
+
+	if xmin <= n.split.X {
+		highest(n.left, xmin, xmax, best)
+	}
+
+	if n.split.X <= xmax {
+		highest(n.right, xmin, xmax, best)
+	}
+}
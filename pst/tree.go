@@ -0,0 +1,164 @@
+// Package pst implements a priority search tree, which stores points in the
+// plane as a search tree on X and a max-heap on Y at the same time. This
+// answers three-sided queries, all points with X in [a, b] and Y of at least
+// c, in O(lg n + k) time for k results.
+//
+// The tree is kept balanced like a scapegoat tree by rebuilding subtrees which
+// became too unbalanced, so updates run in amortized O(lg n) time.
+package pst
+
+import (
+	"math"
+	"sort"
+)
+
+// alpha is the weight balance of the tree: no subtree may hold more than alpha
+// times the points of its parent after an insertion.
+const alpha = 0.7
+
+// NewPrioritySearchTree returns a new priority search tree. Unless
+// WithoutLocking is passed, all operations on the tree are safe to be accessed
+// concurrently.
+func NewPrioritySearchTree(opts ...Option) *Tree {
+	t := &Tree{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of points in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return size(t.root)
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a single node has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return height(t.root)
+}
+
+// Search returns the payload for a given point, or nil.
+func (t *Tree) Search(p Point) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if n := search(t.root, p); n != nil {
+		return n.payload
+	}
+
+	return nil
+}
+
+// maxDepth returns the depth no node may exceed in a tree of n points after an
+// insertion.
+func maxDepth(n int) int {
+	return int(math.Log(float64(n)) / math.Log(1/alpha))
+}
+
+func search(n *node, p Point) *node {
+	// No point of a subtree is higher than its root.
+	for n != nil && p.Y <= n.point.Y {
+		if n.point == p {
+			return n
+		}
+
+		if p.less(n.split) {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+
+	return nil
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+
+	l, r := height(n.left), height(n.right)
+
+	if l > r {
+		return l + 1
+	}
+
+	return r + 1
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// rebuild returns a balanced subtree holding the points of the subtree rooted
+// at n, which has the given size.
+func rebuild(n *node, size int) *node {
+	nodes := make([]*node, 0, size)
+	flatten(n, &nodes)
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].point.less(nodes[j].point)
+	})
+
+	return build(nodes)
+}
+
+func flatten(n *node, nodes *[]*node) {
+	if n == nil {
+		return
+	}
+
+	*nodes = append(*nodes, n)
+
+	flatten(n.left, nodes)
+	flatten(n.right, nodes)
+}
+
+// build links the sorted nodes into a balanced tree and returns its root: the
+// highest point becomes the root, the others are split at their median.
+func build(nodes []*node) *node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	top := 0
+
+	for i, n := range nodes {
+		if n.point.Y > nodes[top].point.Y {
+			top = i
+		}
+	}
+
+	root := nodes[top]
+	root.size = len(nodes)
+
+	copy(nodes[top:], nodes[top+1:])
+	nodes = nodes[:len(nodes)-1]
+
+	if len(nodes) == 0 {
+		root.split = root.point
+		root.left, root.right = nil, nil
+
+		return root
+	}
+
+	mid := len(nodes) / 2
+	root.split = nodes[mid].point
+	root.left = build(nodes[:mid])
+	root.right = build(nodes[mid:])
+
+	return root
+}
@@ -0,0 +1,361 @@
+package pst
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verify checks the heap order on Y, the split keys and the sizes of all
+// subtrees.
+func verify(t *testing.T, tree *Tree) {
+	var walk func(n *node, lo, hi *Point) int
+
+	// walk checks that all points of the subtree rooted at n are in [lo, hi)
+	// and returns their number.
+	walk = func(n *node, lo, hi *Point) int {
+		if n == nil {
+			return 0
+		}
+
+		if lo != nil {
+			require.False(t, n.point.less(*lo), "%v less than %v", n.point, *lo)
+		}
+
+		if hi != nil {
+			require.True(t, n.point.less(*hi), "%v not less than %v", n.point, *hi)
+		}
+
+		for _, c := range []*node{n.left, n.right} {
+			if c != nil {
+				require.LessOrEqual(t, c.point.Y, n.point.Y, "%v is higher than its parent", c.point)
+			}
+		}
+
+		split := n.split
+		size := 1 + walk(n.left, lo, &split) + walk(n.right, &split, hi)
+
+		require.Equal(t, size, n.size, "size of %v", n.point)
+
+		return size
+	}
+
+	walk(tree.root, nil, nil)
+	require.LessOrEqual(t, size(tree.root), tree.maxSize)
+}
+
+// brute returns the points of want within the query, sorted.
+func brute(want map[Point]int, xmin, xmax, ymin int64) []Point {
+	var res []Point
+
+	for p := range want {
+		if xmin <= p.X && p.X <= xmax && p.Y >= ymin {
+			res = append(res, p)
+		}
+	}
+
+	sortPoints(res)
+
+	return res
+}
+
+func points(res []Result) []Point {
+	var ps []Point
+
+	for _, r := range res {
+		ps = append(ps, r.Point)
+	}
+
+	sortPoints(ps)
+
+	return ps
+}
+
+func sortPoints(ps []Point) {
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].less(ps[j])
+	})
+}
+
+// shape renders the subtree rooted at n as its points with their children in
+// parentheses and "." for a missing child, e.g. "6,9(2,1 5,5(. 8,3))".
+func shape(n *node) string {
+	if n == nil {
+		return "."
+	}
+
+	s := fmt.Sprintf("%d,%d", n.point.X, n.point.Y)
+	if n.left == nil && n.right == nil {
+		return s
+	}
+
+	return s + "(" + shape(n.left) + " " + shape(n.right) + ")"
+}
+
+func newTree(ps ...Point) *Tree {
+	tree := NewPrioritySearchTree()
+
+	for _, p := range ps {
+		tree.Upsert(p, p)
+	}
+
+	return tree
+}
+
+// chain returns the points (0, 0) to (n-1, n-1), each of which is higher than
+// all before it.
+func chain(n int64) []Point {
+	ps := make([]Point, n)
+	for i := range ps {
+		ps[i] = Point{X: int64(i), Y: int64(i)}
+	}
+
+	return ps
+}
+
+func TestTree_Upsert(t *testing.T) {
+	tt := []struct {
+		name   string
+		points []Point
+		want   string
+	}{
+		{
+			name:   "higher point takes the root",
+			points: []Point{{5, 1}, {3, 9}},
+			want:   "3,9(. 5,1)",
+		},
+		{
+			name:   "lower point sinks by the split of each node",
+			points: []Point{{5, 5}, {2, 1}, {8, 3}},
+			want:   "5,5(2,1 8,3)",
+		},
+		{
+			name:   "displaced points keep sinking",
+			points: []Point{{5, 5}, {2, 1}, {8, 3}, {6, 9}},
+			want:   "6,9(2,1 5,5(. 8,3))",
+		},
+		{
+			name:   "equal X is ordered by Y",
+			points: []Point{{4, 1}, {4, 3}, {4, 2}},
+			want:   "4,3(. 4,2(. 4,1))",
+		},
+		{
+			name:   "chain up to the depth limit",
+			points: chain(6),
+			want:   "5,5(. 4,4(. 3,3(. 2,2(. 1,1(. 0,0)))))",
+		},
+		{
+			name:   "too deep node rebuilds the lowest unbalanced ancestor",
+			points: chain(7),
+			want:   "6,6(. 5,5(. 4,4(. 3,3(0,0 2,2(. 1,1)))))",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTree(tc.points...)
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			assert.Equal(t, len(tc.points), tree.Len())
+			verify(t, tree)
+
+			for _, p := range tc.points {
+				assert.Equal(t, p, tree.Search(p))
+			}
+		})
+	}
+
+	t.Run("existing point only updates the payload", func(t *testing.T) {
+		tree := newTree(Point{5, 5}, Point{2, 1}, Point{8, 3})
+
+		tree.Upsert(Point{2, 1}, "two")
+
+		assert.Equal(t, "5,5(2,1 8,3)", shape(tree.root))
+		assert.Equal(t, "two", tree.Search(Point{2, 1}))
+		assert.Equal(t, 3, tree.Len())
+	})
+
+	t.Run("same X with other Y is another point", func(t *testing.T) {
+		tree := newTree(Point{12, 5})
+
+		assert.Nil(t, tree.Search(Point{12, 6}))
+		assert.Nil(t, tree.Search(Point{12, 4}))
+	})
+}
+
+func TestTree_Delete(t *testing.T) {
+	tt := []struct {
+		name    string
+		points  []Point
+		delete  []Point
+		want    string
+		maxSize int
+	}{
+		{
+			name:    "root is replaced by its higher child",
+			points:  []Point{{5, 5}, {2, 1}, {8, 3}, {6, 9}},
+			delete:  []Point{{6, 9}},
+			want:    "5,5(2,1 8,3)",
+			maxSize: 4,
+		},
+		{
+			name:    "leaf is removed",
+			points:  []Point{{5, 5}, {2, 1}, {8, 3}, {6, 9}},
+			delete:  []Point{{8, 3}},
+			want:    "6,9(2,1 5,5)",
+			maxSize: 4,
+		},
+		{
+			name:    "missing point",
+			points:  []Point{{5, 5}, {2, 1}},
+			delete:  []Point{{5, 1}, {2, 5}},
+			want:    "5,5(2,1 .)",
+			maxSize: 2,
+		},
+		{
+			name:    "shrinking to alpha of the max size doesn't rebuild",
+			points:  chain(6),
+			delete:  []Point{{5, 5}},
+			want:    "4,4(. 3,3(. 2,2(. 1,1(. 0,0))))",
+			maxSize: 6,
+		},
+		{
+			name:    "shrinking below alpha of the max size rebuilds",
+			points:  chain(6),
+			delete:  []Point{{5, 5}, {4, 4}},
+			want:    "3,3(0,0 2,2(. 1,1))",
+			maxSize: 4,
+		},
+		{
+			name:    "last point",
+			points:  []Point{{1, 1}},
+			delete:  []Point{{1, 1}},
+			want:    ".",
+			maxSize: 0,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTree(tc.points...)
+
+			for _, p := range tc.delete {
+				tree.Delete(p)
+			}
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			assert.Equal(t, tc.maxSize, tree.maxSize)
+			verify(t, tree)
+
+			for _, p := range tc.delete {
+				assert.Nil(t, tree.Search(p))
+			}
+		})
+	}
+}
+
+func TestTree_Query(t *testing.T) {
+	tree := NewPrioritySearchTree()
+
+	// Intervals as (start, end).
+	intervals := []Point{
+		{X: 1, Y: 5},
+		{X: 2, Y: 3},
+		{X: 2, Y: 9},
+		{X: 4, Y: 8},
+		{X: 6, Y: 7},
+		{X: 7, Y: 12},
+		{X: 9, Y: 10},
+	}
+
+	for _, p := range intervals {
+		tree.Upsert(p, nil)
+	}
+
+	tt := []struct {
+		name             string
+		xmin, xmax, ymin int64
+		want             []Point
+	}{
+		{name: "all points", xmin: 0, xmax: 10, ymin: 0, want: intervals},
+		{name: "starting in range and ending late", xmin: 2, xmax: 7, ymin: 8, want: []Point{{2, 9}, {4, 8}, {7, 12}}},
+		{name: "bounds are inclusive", xmin: 4, xmax: 6, ymin: 7, want: []Point{{4, 8}, {6, 7}}},
+		{name: "single X", xmin: 2, xmax: 2, ymin: 0, want: []Point{{2, 3}, {2, 9}}},
+		{name: "single X above ymin", xmin: 2, xmax: 2, ymin: 4, want: []Point{{2, 9}}},
+		{name: "ymin equal to the highest point", xmin: 0, xmax: 10, ymin: 12, want: []Point{{7, 12}}},
+		{name: "no point high enough", xmin: 0, xmax: 10, ymin: 13, want: nil},
+		{name: "no point in range", xmin: 10, xmax: 20, ymin: 0, want: nil},
+		{name: "empty range", xmin: 7, xmax: 1, ymin: 0, want: nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, points(tree.Query(tc.xmin, tc.xmax, tc.ymin)))
+		})
+	}
+
+	r, ok := tree.Highest(1, 6)
+	require.True(t, ok)
+	assert.Equal(t, Point{X: 2, Y: 9}, r.Point)
+
+	r, ok = tree.Highest(2, 2)
+	require.True(t, ok)
+	assert.Equal(t, Point{X: 2, Y: 9}, r.Point)
+
+	_, ok = tree.Highest(10, 20)
+	assert.False(t, ok)
+}
+
+func TestTree_random(t *testing.T) {
+	var (
+		rng  = rand.New(rand.NewSource(1))
+		tree = NewPrioritySearchTree(WithoutLocking())
+		want = make(map[Point]int)
+	)
+
+	for i := 0; i < 5000; i++ {
+		p := Point{X: rng.Int63n(100), Y: rng.Int63n(100)}
+
+		if rng.Intn(3) == 0 {
+			tree.Delete(p)
+			delete(want, p)
+		} else {
+			tree.Upsert(p, i)
+			want[p] = i
+		}
+
+		if i%100 != 0 {
+			continue
+		}
+
+		verify(t, tree)
+
+		xmin, xmax, ymin := rng.Int63n(100), rng.Int63n(100), rng.Int63n(100)
+		require.Equal(t, brute(want, xmin, xmax, ymin), points(tree.Query(xmin, xmax, ymin)))
+
+		r, ok := tree.Highest(xmin, xmax)
+		if all := brute(want, xmin, xmax, 0); len(all) == 0 {
+			require.False(t, ok)
+		} else {
+			require.True(t, ok)
+			require.Empty(t, brute(want, xmin, xmax, r.Point.Y+1), "%v is not the highest", r.Point)
+			require.Equal(t, want[r.Point], r.Payload)
+		}
+	}
+
+	require.Equal(t, len(want), tree.Len())
+
+	for p, v := range want {
+		require.Equal(t, v, tree.Search(p))
+	}
+}
+
+func TestNewPrioritySearchTree_WithoutLocking(t *testing.T) {
+	tree := NewPrioritySearchTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+}
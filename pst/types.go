@@ -0,0 +1,43 @@
+package pst
+
+import "github.com/obitech/go-trees/internal/lock"
+
+// Point is a point in the plane. X is the search key of the tree, Y the
+// priority. An interval can be stored as the point (start, end), so intervals
+// starting in [a, b] and ending at or after c are the result of
+// Query(a, b, c).
+type Point struct {
+	X int64
+	Y int64
+}
+
+// less orders points by X, then by Y.
+func (p Point) less(q Point) bool {
+	return p.X < q.X || p.X == q.X && p.Y < q.Y
+}
+
+// Result is a point found in the tree along with its payload.
+type Result struct {
+	Point   Point
+	Payload interface{}
+}
+
+// Tree represents a priority search tree with a root node and a lock to
+// protect concurrent access.
+type Tree struct {
+	lock    lock.RWMutex
+	root    *node
+	maxSize int
+}
+
+// node holds the point with the highest Y of its subtree. The other points of
+// the subtree are stored in the left subtree if they are less than split, in
+// the right one otherwise.
+type node struct {
+	point   Point
+	payload interface{}
+	split   Point
+	size    int
+	left    *node
+	right   *node
+}
@@ -0,0 +1,77 @@
+package radix
+
+import "bytes"
+
+// Delete deletes key and returns whether it existed.
+func (t *Tree) Delete(key []byte) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var (
+		parent, n = (*node)(nil), t.root
+		// The index of n among the children of parent, and the offsets in
+		// key at which the labels of parent and n start.
+		idx, parentStart, start int
+		off                     int
+	)
+
+	for off < len(key) {
+		i, child := n.child(key[off])
+
+		if child == nil || !bytes.HasPrefix(key[off:], child.prefix) {
+			return false
+		}
+
+		parent, n, idx = n, child, i
+		parentStart, start = start, off
+		off += len(child.prefix)
+	}
+
+	if !n.hasValue {
+		return false
+	}
+
+	n.key, n.value, n.hasValue = nil, nil, false
+	t.size--
+
+	if n == t.root {
+		return true
+	}
+
+	switch len(n.children) {
+	case 0:
+		copy(parent.children[idx:], parent.children[idx+1:])
+		parent.children[len(parent.children)-1] = nil
+		parent.children = parent.children[:len(parent.children)-1]
+
+		// The parent may be left as a valueless node with a single child.
+		if parent != t.root && !parent.hasValue && len(parent.children) == 1 {
+			parent.mergeChild(parentStart)
+		}
+	case 1:
+		n.mergeChild(start)
+	}
+
+	return true
+}
+
+// mergeChild merges the only child of the valueless node n into n. start is
+// the offset in the keys below n at which the label of n starts.
+func (n *node) mergeChild(start int) {
+	child := n.children[0]
+
+	// The combined label is part of every key below child, so it can be
+	// sliced from one of them instead of being allocated.
+	k := child
+	for !k.hasValue {
+		k = k.children[0]
+	}
+
+	*n = node{
+		prefix:   k.key[start : start+len(n.prefix)+len(child.prefix)],
+		children: child.children,
+		key:      child.key,
+		value:    child.value,
+		hasValue: child.hasValue,
+	}
+}
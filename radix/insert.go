@@ -0,0 +1,54 @@
+package radix
+
+// Insert stores value for key, replacing the value of an existing key. The key
+// is copied, so the caller is free to modify it afterwards.
+func (t *Tree) Insert(key []byte, value interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var (
+		stored = append([]byte(nil), key...)
+		search = stored
+		n      = t.root
+	)
+
+	for len(search) > 0 {
+		i, child := n.child(search[0])
+
+		if child == nil {
+			leaf := &node{prefix: search}
+			leaf.set(stored, value)
+
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = leaf
+			t.size++
+
+			return
+		}
+
+		common := commonPrefix(search, child.prefix)
+
+		// The label of the child diverges from the key, so split it into a
+		// new node holding the common part and the remainder.
+		if common < len(child.prefix) {
+			mid := &node{
+				prefix:   child.prefix[:common],
+				children: []*node{child},
+			}
+
+			child.prefix = child.prefix[common:]
+			n.children[i] = mid
+			child = mid
+		}
+
+		search = search[common:]
+		n = child
+	}
+
+	if !n.hasValue {
+		t.size++
+	}
+
+	n.set(stored, value)
+}
@@ -0,0 +1,63 @@
+package radix
+
+import "bytes"
+
+// Walk calls fn for every key in lexicographic order. Iteration stops early if
+// fn returns false. Neither the keys nor the tree must be modified from within
+// fn.
+func (t *Tree) Walk(fn func(key []byte, value interface{}) bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	t.root.walk(fn)
+}
+
+// WalkPrefix calls fn in lexicographic order for every key starting with
+// prefix. Iteration stops early if fn returns false. Neither the keys nor the
+// tree must be modified from within fn.
+func (t *Tree) WalkPrefix(prefix []byte, fn func(key []byte, value interface{}) bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	n := t.root
+
+	for len(prefix) > 0 {
+		_, child := n.child(prefix[0])
+		if child == nil {
+			return
+		}
+
+		// The prefix may end within the label of child, in which case all
+		// keys below child start with it.
+		if bytes.HasPrefix(child.prefix, prefix) {
+			child.walk(fn)
+			return
+		}
+
+		if !bytes.HasPrefix(prefix, child.prefix) {
+			return
+		}
+
+		prefix = prefix[len(child.prefix):]
+		n = child
+	}
+
+	n.walk(fn)
+}
+
+// walk calls fn for all keys of the subtree rooted at n in order. A key is
+// visited before the keys below it, as it is a prefix of those. Returns false
+// if iteration was stopped.
+func (n *node) walk(fn func(key []byte, value interface{}) bool) bool {
+	if n.hasValue && !fn(n.key, n.value) {
+		return false
+	}
+
+	for _, c := range n.children {
+		if !c.walk(fn) {
+			return false
+		}
+	}
+
+	return true
+}
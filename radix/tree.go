@@ -0,0 +1,143 @@
+// Package radix implements a radix tree, a compressed trie mapping byte-slice
+// keys to arbitrary values. Keys sharing a prefix share the nodes of that
+// prefix, and chains of nodes with a single child are merged into one, so
+// operations run in O(k) time for keys of length k, independent of the
+// number of keys. Lookups don't allocate.
+package radix
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// Tree represents a radix tree with a root node and a lock to protect
+// concurrent access.
+type Tree struct {
+	lock lock.RWMutex
+	root *node
+	size int
+}
+
+// node is reached from its parent by the edge label prefix. Its children are
+// sorted by the first byte of their prefix, which is unique among them.
+// Labels are slices of the stored keys, which are copied on insertion and
+// never modified.
+type node struct {
+	prefix   []byte
+	children []*node
+	key      []byte
+	value    interface{}
+	hasValue bool
+}
+
+// NewRadixTree returns a new radix tree. Unless WithoutLocking is passed, all
+// operations on the tree are safe to be accessed concurrently.
+func NewRadixTree(opts ...Option) *Tree {
+	t := &Tree{
+		root: &node{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Get returns the value stored for key and whether it exists.
+func (t *Tree) Get(key []byte) (interface{}, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	n := t.root
+
+	for len(key) > 0 {
+		_, child := n.child(key[0])
+
+		if child == nil || !bytes.HasPrefix(key, child.prefix) {
+			return nil, false
+		}
+
+		key = key[len(child.prefix):]
+		n = child
+	}
+
+	return n.value, n.hasValue
+}
+
+// LongestPrefix returns the longest key in the tree which is a prefix of key,
+// along with its value. Returns false if there is none. The returned key must
+// not be modified.
+func (t *Tree) LongestPrefix(key []byte) ([]byte, interface{}, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var (
+		n    = t.root
+		last *node
+	)
+
+	for {
+		if n.hasValue {
+			last = n
+		}
+
+		if len(key) == 0 {
+			break
+		}
+
+		_, child := n.child(key[0])
+
+		if child == nil || !bytes.HasPrefix(key, child.prefix) {
+			break
+		}
+
+		key = key[len(child.prefix):]
+		n = child
+	}
+
+	if last == nil {
+		return nil, nil, false
+	}
+
+	return last.key, last.value, true
+}
+
+// child returns the index of the child of n whose prefix starts with b and
+// the child itself, or the index it would have to be inserted at and nil.
+func (n *node) child(b byte) (int, *node) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].prefix[0] >= b
+	})
+
+	if i < len(n.children) && n.children[i].prefix[0] == b {
+		return i, n.children[i]
+	}
+
+	return i, nil
+}
+
+func (n *node) set(key []byte, value interface{}) {
+	n.key, n.value, n.hasValue = key, value, true
+}
+
+// commonPrefix returns the length of the common prefix of a and b.
+func commonPrefix(a, b []byte) int {
+	i := 0
+
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
@@ -0,0 +1,355 @@
+package radix
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verify checks that the tree is fully compressed, that children are sorted
+// and that every key matches the labels on its path.
+func verify(t *testing.T, tree *Tree) {
+	var (
+		count int
+		walk  func(n *node, path []byte)
+	)
+
+	walk = func(n *node, path []byte) {
+		path = append(path, n.prefix...)
+
+		if n != tree.root {
+			require.NotEmpty(t, n.prefix)
+			require.True(t, n.hasValue || len(n.children) > 1, "node %q isn't compressed", path)
+		}
+
+		if n.hasValue {
+			require.Equal(t, path, n.key)
+			count++
+		}
+
+		for i, c := range n.children {
+			if i > 0 {
+				require.Less(t, n.children[i-1].prefix[0], c.prefix[0], "children not sorted")
+			}
+
+			walk(c, path)
+		}
+	}
+
+	walk(tree.root, nil)
+
+	require.Equal(t, tree.size, count)
+}
+
+func newTree(keys ...string) *Tree {
+	tree := NewRadixTree()
+
+	for _, k := range keys {
+		tree.Insert([]byte(k), k)
+	}
+
+	return tree
+}
+
+// shape renders the subtree rooted at n as its edge labels, marking nodes
+// holding a key with a star, e.g. "(fo(o*(bar*) x*))" for "foo", "foobar" and
+// "fox".
+func shape(n *node) string {
+	s := string(n.prefix)
+	if n.hasValue {
+		s += "*"
+	}
+
+	if len(n.children) == 0 {
+		return s
+	}
+
+	parts := make([]string, len(n.children))
+	for i, c := range n.children {
+		parts[i] = shape(c)
+	}
+
+	return s + "(" + strings.Join(parts, " ") + ")"
+}
+
+func walked(tree *Tree, prefix string) []string {
+	var res []string
+
+	fn := func(key []byte, value interface{}) bool {
+		res = append(res, string(key))
+		return true
+	}
+
+	if prefix == "" {
+		tree.Walk(fn)
+	} else {
+		tree.WalkPrefix([]byte(prefix), fn)
+	}
+
+	return res
+}
+
+func TestTree_Insert(t *testing.T) {
+	t.Run("shared prefixes split nodes", func(t *testing.T) {
+		tree := newTree("romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus")
+
+		assert.Equal(t, 7, tree.Len())
+		assert.Equal(t, "(r(om(an(e* us*) ulus*) ub(e(ns* r*) ic(on* undus*))))", shape(tree.root))
+		verify(t, tree)
+
+		for _, k := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus"} {
+			v, ok := tree.Get([]byte(k))
+
+			assert.True(t, ok)
+			assert.Equal(t, k, v)
+		}
+
+		for _, k := range []string{"", "r", "rom", "roman", "rubicons", "x"} {
+			_, ok := tree.Get([]byte(k))
+			assert.False(t, ok, k)
+		}
+	})
+
+	t.Run("key ending inside an edge splits it", func(t *testing.T) {
+		tree := newTree("test", "team")
+
+		assert.Equal(t, "(te(am* st*))", shape(tree.root))
+
+		tree.Insert([]byte("t"), "t")
+
+		assert.Equal(t, "(t*(e(am* st*)))", shape(tree.root))
+		verify(t, tree)
+	})
+
+	t.Run("insert of existing key replaces value", func(t *testing.T) {
+		tree := newTree("foo", "foobar")
+
+		tree.Insert([]byte("foo"), 1)
+
+		v, _ := tree.Get([]byte("foo"))
+		assert.Equal(t, 1, v)
+		assert.Equal(t, 2, tree.Len())
+	})
+
+	t.Run("prefix of existing key", func(t *testing.T) {
+		tree := newTree("foobar", "foo", "")
+
+		assert.Equal(t, 3, tree.Len())
+		assert.Equal(t, "*(foo*(bar*))", shape(tree.root))
+		verify(t, tree)
+	})
+
+	t.Run("keys are copied", func(t *testing.T) {
+		tree := NewRadixTree()
+		key := []byte("abc")
+
+		tree.Insert(key, 1)
+		key[0] = 'x'
+
+		_, ok := tree.Get([]byte("abc"))
+		assert.True(t, ok)
+	})
+
+	t.Run("nil values are stored", func(t *testing.T) {
+		tree := NewRadixTree()
+		tree.Insert([]byte("a"), nil)
+
+		v, ok := tree.Get([]byte("a"))
+		assert.Nil(t, v)
+		assert.True(t, ok)
+	})
+}
+
+func TestTree_Delete(t *testing.T) {
+	t.Run("delete of non-existing key", func(t *testing.T) {
+		tree := newTree("foo", "foobar")
+
+		assert.False(t, tree.Delete([]byte("fo")))
+		assert.False(t, tree.Delete([]byte("foob")))
+		assert.False(t, tree.Delete([]byte("bar")))
+		assert.False(t, tree.Delete([]byte("")))
+		assert.Equal(t, 2, tree.Len())
+	})
+
+	t.Run("deleting a leaf merges its parent", func(t *testing.T) {
+		tree := newTree("test", "team", "toast")
+
+		assert.True(t, tree.Delete([]byte("team")))
+
+		// "e" has a single child left, which gets merged into it.
+		assert.Equal(t, "(t(est* oast*))", shape(tree.root))
+		verify(t, tree)
+	})
+
+	t.Run("deleting an inner key merges its child", func(t *testing.T) {
+		tree := newTree("foo", "foobar", "fox")
+
+		assert.True(t, tree.Delete([]byte("foo")))
+
+		assert.Equal(t, "(fo(obar* x*))", shape(tree.root))
+		verify(t, tree)
+	})
+
+	t.Run("deleting the empty key", func(t *testing.T) {
+		tree := newTree("", "a")
+
+		assert.True(t, tree.Delete(nil))
+		assert.Equal(t, "(a*)", shape(tree.root))
+		verify(t, tree)
+	})
+}
+
+func TestTree_LongestPrefix(t *testing.T) {
+	tree := newTree("/", "/api", "/api/v1/", "/api/v1/users", "/static/")
+
+	tt := []struct {
+		key  string
+		want string
+		ok   bool
+	}{
+		{key: "/api/v1/users/42", want: "/api/v1/users", ok: true},
+		{key: "/api/v1/groups", want: "/api/v1/", ok: true},
+		{key: "/api/v2", want: "/api", ok: true},
+		{key: "/apis", want: "/api", ok: true},
+		{key: "/static", want: "/", ok: true},
+		{key: "/", want: "/", ok: true},
+		{key: "api"},
+		{key: ""},
+	}
+
+	for _, tc := range tt {
+		key, value, ok := tree.LongestPrefix([]byte(tc.key))
+
+		assert.Equal(t, tc.ok, ok, tc.key)
+
+		if ok {
+			assert.Equal(t, tc.want, string(key), tc.key)
+			assert.Equal(t, tc.want, value, tc.key)
+		}
+	}
+}
+
+func TestTree_WalkPrefix(t *testing.T) {
+	tree := newTree("romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rub")
+
+	tt := []struct {
+		prefix string
+		want   []string
+	}{
+		{prefix: "rom", want: []string{"romane", "romanus", "romulus"}},
+		{prefix: "ro", want: []string{"romane", "romanus", "romulus"}},
+		{prefix: "rub", want: []string{"rub", "rubens", "ruber", "rubicon", "rubicundus"}},
+		{prefix: "rubic", want: []string{"rubicon", "rubicundus"}},
+		{prefix: "romulus", want: []string{"romulus"}},
+		{prefix: "romuluss"},
+		{prefix: "rx"},
+		{prefix: "x"},
+	}
+
+	for _, tc := range tt {
+		assert.Equal(t, tc.want, walked(tree, tc.prefix), tc.prefix)
+	}
+
+	t.Run("stops early", func(t *testing.T) {
+		var n int
+
+		tree.WalkPrefix([]byte("r"), func(key []byte, value interface{}) bool {
+			n++
+			return n < 2
+		})
+
+		assert.Equal(t, 2, n)
+	})
+}
+
+func TestTree_random(t *testing.T) {
+	var (
+		tree = NewRadixTree()
+		want = make(map[string]int)
+		rng  = rand.New(rand.NewSource(1))
+	)
+
+	// A small alphabet produces lots of shared prefixes.
+	randomKey := func() []byte {
+		k := make([]byte, rng.Intn(8))
+		for i := range k {
+			k[i] = "abc"[rng.Intn(3)]
+		}
+
+		return k
+	}
+
+	for i := 0; i < 5000; i++ {
+		k := randomKey()
+
+		if rng.Intn(3) == 0 {
+			_, existed := want[string(k)]
+
+			assert.Equal(t, existed, tree.Delete(k))
+			delete(want, string(k))
+		} else {
+			tree.Insert(k, i)
+			want[string(k)] = i
+		}
+
+		if i%500 == 0 {
+			verify(t, tree)
+		}
+	}
+
+	verify(t, tree)
+
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	assert.Equal(t, keys, walked(tree, ""))
+	assert.Equal(t, len(want), tree.Len())
+
+	for k, v := range want {
+		got, ok := tree.Get([]byte(k))
+
+		assert.True(t, ok)
+		assert.Equal(t, v, got)
+	}
+
+	for i := 0; i < 100; i++ {
+		k := randomKey()
+
+		// The longest key in want which is a prefix of k.
+		var longest string
+
+		found := false
+
+		for p := range want {
+			if bytes.HasPrefix(k, []byte(p)) && (!found || len(p) > len(longest)) {
+				longest, found = p, true
+			}
+		}
+
+		got, _, ok := tree.LongestPrefix(k)
+
+		assert.Equal(t, found, ok)
+		assert.Equal(t, longest, string(got))
+	}
+}
+
+func TestTree_allocations(t *testing.T) {
+	tree := newTree("/api", "/api/v1/users", "/static/")
+	key := []byte("/api/v1/users")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		tree.Get(key)
+		tree.LongestPrefix(key)
+	})
+
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,77 @@
+package redblack
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func createTree(keys int64) *Tree {
+	rand.Seed(time.Now().UnixNano())
+	tree := NewRedBlackTree()
+
+	for i := int64(1); i <= keys; i++ {
+		tree.Upsert(myInt(rand.Int63n(keys)), i)
+	}
+
+	return tree
+}
+
+var result interface{}
+
+func benchmarkSearch(i int64, b *testing.B) {
+	tree := createTree(i)
+
+	var r interface{}
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		r = tree.Search(myInt(rand.Int63n(i)))
+	}
+
+	result = r
+}
+
+func benchmarkDelete(i int64, b *testing.B) {
+	tree := createTree(i)
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		tree.Delete(myInt(rand.Int63n(i)))
+	}
+}
+
+func BenchmarkRBTree_Upsert(b *testing.B) {
+	rand.Seed(time.Now().UnixNano())
+	tree := NewRedBlackTree()
+
+	for n := 1; n <= b.N; n++ {
+		tree.Upsert(myInt(rand.Int63n(int64(n))), nil)
+	}
+}
+
+func BenchmarkRBTree_Search10_000(b *testing.B) {
+	benchmarkSearch(10_000, b)
+}
+
+func BenchmarkRBTree_Search100_000(b *testing.B) {
+	benchmarkSearch(100_000, b)
+}
+
+func BenchmarkRBTree_Search1_000_000(b *testing.B) {
+	benchmarkSearch(1_000_000, b)
+}
+
+func BenchmarkRBTree_Delete10_000(b *testing.B) {
+	benchmarkDelete(10_000, b)
+}
+
+func BenchmarkRBTree_Delete100_000(b *testing.B) {
+	benchmarkDelete(100_000, b)
+}
+
+func BenchmarkRBTree_Delete1_000_000(b *testing.B) {
+	benchmarkDelete(1_000_000, b)
+}
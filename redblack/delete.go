@@ -7,6 +7,8 @@ func (t *Tree) Delete(key Key) {
 
 	if n := t.search(t.root, key); n != t.sentinel {
 		t.delete(n)
+
+		t.watchers.Notify(Event{Type: EventDelete, Key: n.key, Old: n.payload})
 	}
 }
 
@@ -0,0 +1,158 @@
+package redblack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+// snapshotVersion is the version of the binary format written by WriteTo:
+//
+//	magic | version | count (uvarint) | count × (key | payload)
+//
+// Entries are written in ascending key order, keys and payloads are
+// length-prefixed and encoded with the tree's codec.
+const snapshotVersion = 1
+
+var snapshotMagic = [4]byte{'R', 'B', 'T', 'S'}
+
+// maxPrealloc limits how many nodes are allocated up front when reading a
+// snapshot, so corrupted counts can't exhaust memory.
+const maxPrealloc = 1 << 16
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (t *Tree) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. All existing entries
+// of the tree are replaced.
+func (t *Tree) UnmarshalBinary(data []byte) error {
+	_, err := t.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// WriteTo implements io.WriterTo by writing a snapshot of the tree to w.
+func (t *Tree) WriteTo(w io.Writer) (int64, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	e := codec.NewEncoder(w)
+
+	e.WriteHeader(snapshotMagic, snapshotVersion)
+	e.WriteUvarint(uint64(t.size(t.root)))
+
+	t.inorder(t.root, func(z *node) {
+		e.WriteValue(t.codec, z.key)
+		e.WriteValue(t.codec, z.payload)
+	})
+
+	return e.Flush()
+}
+
+// ReadFrom implements io.ReaderFrom by reading a snapshot written by WriteTo.
+// All existing entries of the tree are replaced, without notifying watchers.
+// Since snapshots are sorted, the tree is built in O(n) time.
+func (t *Tree) ReadFrom(r io.Reader) (int64, error) {
+	d := codec.NewDecoder(r)
+
+	if v := d.ReadHeader(snapshotMagic); d.Err() == nil && v != snapshotVersion {
+		d.Fail(fmt.Errorf("redblack: unsupported snapshot version %d", v))
+	}
+
+	count := d.ReadUvarint()
+
+	nodes := make([]*node, 0, minUint64(count, maxPrealloc))
+
+	for i := uint64(0); i < count && d.Err() == nil; i++ {
+		k, p := d.ReadValue(t.codec), d.ReadValue(t.codec)
+		if d.Err() != nil {
+			break
+		}
+
+		key, ok := k.(Key)
+		if !ok {
+			d.Fail(fmt.Errorf("redblack: decoded key of type %T doesn't implement Key", k))
+			break
+		}
+
+		if len(nodes) > 0 && !nodes[len(nodes)-1].key.Less(key) {
+			d.Fail(errors.New("redblack: snapshot keys are not in ascending order"))
+			break
+		}
+
+		nodes = append(nodes, t.newLeaf(key, p))
+	}
+
+	if err := d.Err(); err != nil {
+		return d.Count(), err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = t.build(nodes, t.sentinel, 0, bits.Len(uint(len(nodes)))-1)
+	t.root.color = black
+
+	return d.Count(), nil
+}
+
+// build links sorted nodes into a balanced tree and returns its root. All
+// levels but the deepest one are complete, so coloring the nodes of the
+// deepest level red and all others black satisfies the red-black properties.
+func (t *Tree) build(nodes []*node, parent *node, depth, maxDepth int) *node {
+	if len(nodes) == 0 {
+		return t.sentinel
+	}
+
+	mid := len(nodes) / 2
+
+	z := nodes[mid]
+	z.parent = parent
+	z.left = t.build(nodes[:mid], z, depth+1, maxDepth)
+	z.right = t.build(nodes[mid+1:], z, depth+1, maxDepth)
+
+	if depth == maxDepth && depth > 0 {
+		z.color = red
+	} else {
+		z.color = black
+	}
+
+	return z
+}
+
+func (t *Tree) inorder(z *node, fn func(*node)) {
+	if z == t.sentinel {
+		return
+	}
+
+	t.inorder(z.left, fn)
+	fn(z)
+	t.inorder(z.right, fn)
+}
+
+func (t *Tree) size(z *node) int {
+	if z == t.sentinel {
+		return 0
+	}
+
+	return 1 + t.size(z.left) + t.size(z.right)
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
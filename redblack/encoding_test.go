@@ -0,0 +1,149 @@
+package redblack
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+func init() {
+	gob.Register(myInt(0))
+}
+
+// blackHeight verifies the red-black properties of the subtree rooted at z
+// and returns its black height.
+func blackHeight(t *testing.T, tree *Tree, z *node) int {
+	if z == tree.sentinel {
+		return 0
+	}
+
+	if z.color == red {
+		require.Equal(t, black, z.left.color, "red node %v has red left child", z.key)
+		require.Equal(t, black, z.right.color, "red node %v has red right child", z.key)
+	}
+
+	l, r := blackHeight(t, tree, z.left), blackHeight(t, tree, z.right)
+	require.Equal(t, l, r, "black heights of %v differ", z.key)
+
+	if z.color == black {
+		return l + 1
+	}
+
+	return l
+}
+
+type failingCodec struct{}
+
+func (failingCodec) Marshal(interface{}) ([]byte, error) {
+	return nil, errors.New("nope")
+}
+
+func (failingCodec) Unmarshal([]byte) (interface{}, error) {
+	return nil, errors.New("nope")
+}
+
+func TestTree_MarshalBinary(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 100, 1000} {
+		tree := NewRedBlackTree()
+
+		for i := 0; i < n; i++ {
+			tree.Upsert(myInt(i), i)
+		}
+
+		b, err := tree.MarshalBinary()
+		require.NoError(t, err)
+
+		got := NewRedBlackTree()
+		require.NoError(t, got.UnmarshalBinary(b))
+
+		assert.Equal(t, tree.InOrder(), got.InOrder(), "n=%d", n)
+		assert.Equal(t, black, got.root.color)
+
+		if n > 0 {
+			assert.Equal(t, got.sentinel, got.root.parent)
+		}
+
+		blackHeight(t, got, got.root)
+
+		// The loaded tree stays fully functional.
+		got.Upsert(myInt(n+1), n)
+		got.Delete(myInt(0))
+		blackHeight(t, got, got.root)
+		assert.Equal(t, n, got.Search(myInt(n+1)))
+	}
+}
+
+func TestTree_ReadFrom(t *testing.T) {
+	t.Run("replaces existing entries", func(t *testing.T) {
+		src := NewRedBlackTree()
+		src.Upsert(myInt(1), "1")
+
+		var buf bytes.Buffer
+
+		n, err := src.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		dst := NewRedBlackTree()
+		dst.Upsert(myInt(2), "2")
+
+		read, err := dst.ReadFrom(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, n, read)
+
+		assert.Equal(t, []Result{{Key: myInt(1), Payload: "1"}}, dst.InOrder())
+	})
+
+	t.Run("truncated snapshot returns error and leaves tree untouched", func(t *testing.T) {
+		src := NewRedBlackTree()
+		src.Upsert(myInt(1), "1")
+		src.Upsert(myInt(2), "2")
+
+		b, err := src.MarshalBinary()
+		require.NoError(t, err)
+
+		dst := NewRedBlackTree()
+		dst.Upsert(myInt(3), "3")
+
+		assert.Error(t, dst.UnmarshalBinary(b[:len(b)-1]))
+		assert.Equal(t, []Result{{Key: myInt(3), Payload: "3"}}, dst.InOrder())
+	})
+
+	t.Run("invalid magic number returns error", func(t *testing.T) {
+		assert.Equal(t, codec.ErrInvalidMagic, NewRedBlackTree().UnmarshalBinary([]byte("BSTS\x01\x00")))
+	})
+
+	t.Run("unknown version returns error", func(t *testing.T) {
+		assert.Error(t, NewRedBlackTree().UnmarshalBinary([]byte("RBTS\x02\x00")))
+	})
+
+	t.Run("unsorted keys return error", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		e := codec.NewEncoder(&buf)
+		e.WriteHeader(snapshotMagic, snapshotVersion)
+		e.WriteUvarint(2)
+		e.WriteValue(codec.Gob{}, myInt(2))
+		e.WriteValue(codec.Gob{}, nil)
+		e.WriteValue(codec.Gob{}, myInt(1))
+		e.WriteValue(codec.Gob{}, nil)
+		_, err := e.Flush()
+		require.NoError(t, err)
+
+		assert.Error(t, NewRedBlackTree().UnmarshalBinary(buf.Bytes()))
+	})
+
+	t.Run("custom codec is used", func(t *testing.T) {
+		tree := NewRedBlackTree(WithCodec(failingCodec{}))
+		tree.Upsert(myInt(1), "1")
+
+		_, err := tree.MarshalBinary()
+		assert.Error(t, err)
+	})
+}
@@ -6,9 +6,14 @@ func (t *Tree) Upsert(key Key, payload interface{}) {
 	defer t.lock.Unlock()
 
 	if existing := t.search(t.root, key); existing != t.sentinel {
+		old := existing.payload
 		existing.payload = payload
+
+		t.watchers.Notify(Event{Type: EventUpdate, Key: key, Old: old, New: payload})
 	} else {
 		t.insert(t.newLeaf(key, payload))
+
+		t.watchers.Notify(Event{Type: EventInsert, Key: key, New: payload})
 	}
 }
 
@@ -188,72 +188,3 @@ func TestRBTree_Upsert(t *testing.T) {
 		assert.Equal(t, myInt(50), tree.root.right.right.right.key)
 	})
 }
-
-//
-// func createTree(keys myInt) *Tree {
-// 	rand.Seed(time.Now().UnixNano())
-// 	tree := NewRedBlackTree()
-//
-// 	for i := int(1); i <= keys; i++ {
-// 		tree.Upsert(rand.Int63n(keys), i)
-// 	}
-//
-// 	return tree
-// }
-//
-// var result interface{}
-//
-// func benchmarkSearch(i myInt, b *testing.B) {
-// 	rand.Seed(time.Now().UnixNano())
-// 	tree := createTree(i)
-//
-// 	var r interface{}
-//
-// 	for n := 0; n < b.N; n++ {
-// 		r = tree.Search(rand.Int63n(i))
-// 	}
-//
-// 	result = r
-// }
-//
-// func benchmarkDelete(i myInt, b *testing.B) {
-// 	rand.Seed(time.Now().UnixNano())
-// 	tree := createTree(i)
-//
-// 	for n := 0; n < b.N; n++ {
-// 		tree.Delete(rand.Int63n(i))
-// 	}
-// }
-//
-// func BenchmarkRBTree_Upsert(b *testing.B) {
-// 	rand.Seed(time.Now().UnixNano())
-// 	tree := NewRedBlackTree()
-//
-// 	for n := 1; n <= b.N; n++ {
-// 		tree.Upsert(rand.Int63n(myInt(n)), nil)
-// 	}
-// }
-//
-// func BenchmarkRBTree_Search10_000(b *testing.B) {
-// 	benchmarkSearch(10_000, b)
-// }
-//
-// func BenchmarkRBTree_Search100_000(b *testing.B) {
-// 	benchmarkSearch(100_000, b)
-// }
-//
-// func BenchmarkRBTree_Search1_000_000(b *testing.B) {
-// 	benchmarkSearch(1_000_000, b)
-// }
-//
-// func BenchmarkRBTree_Delete10_000(b *testing.B) {
-// 	benchmarkDelete(10_000, b)
-// }
-//
-// func BenchmarkRBTree_Delete100_000(b *testing.B) {
-// 	benchmarkDelete(100_000, b)
-// }
-//
-// func BenchmarkRBTree_Delete1_000_000(b *testing.B) {
-// 	benchmarkDelete(1_000_000, b)
-// }
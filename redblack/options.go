@@ -0,0 +1,21 @@
+package redblack
+
+import "github.com/obitech/go-trees/codec"
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
+
+// WithCodec sets the codec used to encode keys and payloads when the tree is
+// serialized. Defaults to codec.Gob.
+func WithCodec(c codec.Codec) Option {
+	return func(t *Tree) {
+		t.codec = c
+	}
+}
@@ -4,19 +4,26 @@ package redblack
 
 import (
 	"math"
-	"sync"
+
+	"github.com/obitech/go-trees/codec"
 )
 
-// NewRedBlackTree returns a new red-back tree. All operations on the tree are
-// safe to be accessed concurrently.
-func NewRedBlackTree() *Tree {
+// NewRedBlackTree returns a new red-back tree. Unless WithoutLocking is
+// passed, all operations on the tree are safe to be accessed concurrently.
+func NewRedBlackTree(opts ...Option) *Tree {
 	sentinel := &node{color: black, payload: "sentinel"}
 
-	return &Tree{
-		lock:     sync.RWMutex{},
+	t := &Tree{
 		root:     sentinel,
 		sentinel: sentinel,
+		codec:    codec.Gob{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
 	}
+
+	return t
 }
 
 // Root returns the payload of the root node of the tree.
@@ -94,7 +101,7 @@ func (t *Tree) Successor(key Key) interface{} {
 
 	n := t.successor(t.search(t.root, key))
 
-	if n == nil {
+	if n == nil || n == t.sentinel {
 		return nil
 	}
 
@@ -352,4 +352,24 @@ func TestTree_Successor(t *testing.T) {
 			assert.Equal(t, tc.want, tree.Successor(myInt(tc.check)))
 		})
 	}
+
+	t.Run("highest key has no successor", func(t *testing.T) {
+		assert.Nil(t, tree.Successor(myInt(50)))
+	})
+}
+
+func TestNewRedBlackTree_WithoutLocking(t *testing.T) {
+	tree := NewRedBlackTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+
+	tree.Upsert(myInt(15), "15")
+	tree.Upsert(myInt(10), "10")
+	tree.Upsert(myInt(20), "20")
+	tree.Delete(myInt(10))
+
+	assert.Equal(t, "15", tree.Root())
+	assert.Equal(t, "15", tree.Min())
+	assert.Equal(t, "20", tree.Max())
+	assert.Nil(t, tree.Search(myInt(10)))
 }
@@ -1,7 +1,9 @@
 package redblack
 
 import (
-	"sync"
+	"github.com/obitech/go-trees/codec"
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/internal/watch"
 )
 
 type color int
@@ -15,12 +17,14 @@ type Key interface {
 	Less(k Key) bool
 }
 
-// Tree represents a red-black tree with a root node and Mutex to protect
+// Tree represents a red-black tree with a root node and a lock to protect
 // concurrent access.
 type Tree struct {
-	lock     sync.RWMutex
+	lock     lock.RWMutex
 	root     *node
 	sentinel *node
+	watchers watch.Hub
+	codec    codec.Codec
 }
 
 type node struct {
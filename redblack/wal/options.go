@@ -0,0 +1,76 @@
+package wal
+
+import (
+	"time"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+// SyncPolicy determines when the log is flushed to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes the log after every record. No acknowledged write
+	// can get lost, at the cost of an fsync per modification.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval flushes the log periodically in the background. Writes of
+	// the last interval can get lost on a crash.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system. The log is only
+	// flushed on Sync, Checkpoint and Close.
+	SyncNever
+)
+
+// Option configures a Log on Open.
+type Option func(*options)
+
+type options struct {
+	codec           codec.Codec
+	sync            SyncPolicy
+	interval        time.Duration
+	checkpointEvery int
+	maxRecordSize   int
+}
+
+func defaultOptions() options {
+	return options{
+		codec:           codec.Gob{},
+		sync:            SyncAlways,
+		interval:        time.Second,
+		checkpointEvery: 10_000,
+		maxRecordSize:   64 << 20,
+	}
+}
+
+// WithCodec sets the codec used to encode keys and payloads. Defaults to
+// codec.Gob, which requires custom key types to be registered with
+// gob.Register.
+func WithCodec(c codec.Codec) Option {
+	return func(o *options) {
+		o.codec = c
+	}
+}
+
+// WithSyncPolicy sets the SyncPolicy of the log. Defaults to SyncAlways.
+func WithSyncPolicy(p SyncPolicy) Option {
+	return func(o *options) {
+		o.sync = p
+	}
+}
+
+// WithSyncInterval sets the interval for SyncInterval. Defaults to one
+// second.
+func WithSyncInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.sync = SyncInterval
+		o.interval = d
+	}
+}
+
+// WithCheckpointEvery writes a checkpoint after n logged modifications.
+// Defaults to 10,000, a value of zero disables automatic checkpoints.
+func WithCheckpointEvery(n int) Option {
+	return func(o *options) {
+		o.checkpointEvery = n
+	}
+}
@@ -0,0 +1,598 @@
+// Package wal adds crash-safe persistence to a redblack.Tree. Every Upsert and
+// Delete is appended to a write-ahead log before it is applied to the tree,
+// and a snapshot of the full tree is periodically written to a checkpoint
+// file so the log can be truncated. Opening a directory restores the tree by
+// loading the latest checkpoint and replaying the log on top of it.
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/obitech/go-trees/redblack"
+)
+
+const (
+	logFile        = "wal"
+	checkpointFile = "checkpoint"
+	version        = 1
+
+	// recordHeaderSize is the size of the checksum and length prefix of each
+	// record.
+	recordHeaderSize = 8
+)
+
+var (
+	logMagic = [4]byte{'R', 'B', 'W', 'L'}
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// ErrClosed is returned for operations on a closed Log.
+var ErrClosed = errors.New("wal: log is closed")
+
+type op byte
+
+const (
+	opUpsert op = 1
+	opDelete op = 2
+)
+
+// file is the part of *os.File used for the log.
+type file interface {
+	io.Reader
+	io.Writer
+	io.WriterAt
+	io.Seeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// Log is a redblack.Tree backed by a write-ahead log. All modifications have
+// to go through the Log, reads can be done on the tree returned by Tree.
+type Log struct {
+	mu     sync.Mutex
+	tree   *redblack.Tree
+	dir    string
+	file   file
+	opts   options
+	err    error
+	dirty  bool
+	count  int
+	closed bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// Open restores the tree persisted in dir, creating the directory if it
+// doesn't exist. A torn or corrupted tail of the log, as left behind by a
+// crash during a write, is discarded.
+func Open(dir string, opts ...Option) (*Log, error) {
+	o := defaultOptions()
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &Log{
+		tree: redblack.NewRedBlackTree(redblack.WithCodec(o.codec)),
+		dir:  dir,
+		opts: o,
+	}
+
+	if err := l.loadCheckpoint(); err != nil {
+		return nil, err
+	}
+
+	if err := l.openLog(); err != nil {
+		return nil, err
+	}
+
+	if o.sync == SyncInterval {
+		l.stop = make(chan struct{})
+		l.done = make(chan struct{})
+
+		go l.syncLoop()
+	}
+
+	return l, nil
+}
+
+// Tree returns the restored tree. It must not be modified directly, as those
+// changes would not be persisted.
+func (l *Log) Tree() *redblack.Tree {
+	return l.tree
+}
+
+// Upsert logs and applies an update or insert of the given key.
+func (l *Log) Upsert(key redblack.Key, payload interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.append(opUpsert, key, payload); err != nil {
+		return err
+	}
+
+	l.tree.Upsert(key, payload)
+
+	return l.maybeCheckpoint()
+}
+
+// Delete logs and applies the deletion of the given key.
+func (l *Log) Delete(key redblack.Key) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.append(opDelete, key, nil); err != nil {
+		return err
+	}
+
+	l.tree.Delete(key)
+
+	return l.maybeCheckpoint()
+}
+
+// Sync flushes the log to stable storage.
+func (l *Log) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	return l.sync()
+}
+
+// Checkpoint writes the full tree to the checkpoint file and truncates the
+// log.
+func (l *Log) Checkpoint() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	return l.checkpoint()
+}
+
+// Close syncs and closes the log. The tree stays readable.
+func (l *Log) Close() error {
+	l.mu.Lock()
+
+	if l.closed {
+		l.mu.Unlock()
+		return ErrClosed
+	}
+
+	l.closed = true
+	l.mu.Unlock()
+
+	if l.stop != nil {
+		close(l.stop)
+		<-l.done
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.file.Sync(); err != nil {
+		l.file.Close()
+		return err
+	}
+
+	return l.file.Close()
+}
+
+func (l *Log) syncLoop() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			// Errors resurface on the next explicit Sync or Close.
+			_ = l.sync()
+			l.mu.Unlock()
+		}
+	}
+}
+
+func (l *Log) sync() error {
+	if !l.dirty {
+		return nil
+	}
+
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+
+	l.dirty = false
+
+	return nil
+}
+
+func (l *Log) append(o op, key redblack.Key, payload interface{}) error {
+	if l.closed {
+		return ErrClosed
+	}
+
+	if l.err != nil {
+		return l.err
+	}
+
+	rec, err := l.encodeRecord(o, key, payload)
+	if err != nil {
+		return err
+	}
+
+	offset, err := l.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	if _, err := l.file.Write(rec); err != nil {
+		return l.rollback(offset, err)
+	}
+
+	l.dirty = true
+
+	if l.opts.sync == SyncAlways {
+		if err := l.sync(); err != nil {
+			return l.rollback(offset, err)
+		}
+	}
+
+	l.count++
+
+	return nil
+}
+
+// rollback removes a record which failed to be written or synced from the end
+// of the log, so it is neither replayed although the caller got an error, nor
+// hides the records appended after it behind a torn tail. If the log can't be
+// restored, all further modifications fail.
+func (l *Log) rollback(offset int64, err error) error {
+	if terr := l.file.Truncate(offset); terr != nil {
+		l.err = fmt.Errorf("wal: log is corrupted after failed write: %w", terr)
+		return err
+	}
+
+	if _, serr := l.file.Seek(offset, io.SeekStart); serr != nil {
+		l.err = fmt.Errorf("wal: log is corrupted after failed write: %w", serr)
+	}
+
+	return err
+}
+
+func (l *Log) maybeCheckpoint() error {
+	if l.opts.checkpointEvery > 0 && l.count >= l.opts.checkpointEvery {
+		return l.checkpoint()
+	}
+
+	return nil
+}
+
+func (l *Log) checkpoint() error {
+	var (
+		path = filepath.Join(l.dir, checkpointFile)
+		tmp  = path + ".tmp"
+	)
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if err := l.writeCheckpoint(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	if err := syncDir(l.dir); err != nil {
+		return err
+	}
+
+	// Everything in the log is part of the checkpoint now. Should we crash
+	// before the truncation is durable, replaying the log on top of the
+	// checkpoint yields the same tree.
+	if err := l.file.Truncate(int64(len(logMagic) + 1)); err != nil {
+		return err
+	}
+
+	if _, err := l.file.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+
+	l.count = 0
+	l.dirty = true
+
+	return l.sync()
+}
+
+func (l *Log) writeCheckpoint(f *os.File) error {
+	if _, err := l.tree.WriteTo(f); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+func (l *Log) loadCheckpoint() error {
+	f, err := os.Open(filepath.Join(l.dir, checkpointFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Checkpoints are written atomically, so unlike the log they can't have
+	// a torn tail.
+	if _, err := l.tree.ReadFrom(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("wal: reading checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (l *Log) openLog() error {
+	f, err := os.OpenFile(filepath.Join(l.dir, logFile), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+
+	l.file = f
+
+	good, err := l.replayLog()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	// Drop everything after the last intact record.
+	if err := f.Truncate(good); err != nil {
+		f.Close()
+		return err
+	}
+
+	if good == 0 {
+		var buf bytes.Buffer
+
+		if err := writeHeader(&buf, logMagic); err != nil {
+			f.Close()
+			return err
+		}
+
+		if _, err := f.WriteAt(buf.Bytes(), 0); err != nil {
+			f.Close()
+			return err
+		}
+
+		l.dirty = true
+	}
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := l.sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return nil
+}
+
+// replayLog applies all intact records of the log to the tree and returns the
+// offset right after the last one.
+func (l *Log) replayLog() (int64, error) {
+	r := bufio.NewReader(l.file)
+
+	if err := readHeader(r, logMagic); err != nil {
+		// A log without a complete header is rewritten from scratch.
+		if errors.Is(err, io.ErrUnexpectedEOF) || err == io.EOF {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("wal: reading log: %w", err)
+	}
+
+	offset := int64(len(logMagic) + 1)
+
+	for {
+		body, n, err := l.readRecord(r)
+		if err != nil {
+			// io.EOF marks the clean end of the log, anything else a torn or
+			// corrupted tail.
+			return offset, nil
+		}
+
+		if err := l.apply(body); err != nil {
+			return 0, fmt.Errorf("wal: replaying log at offset %d: %w", offset, err)
+		}
+
+		offset += int64(n)
+		l.count++
+	}
+}
+
+// readRecord reads and verifies the next record, returning its body and total
+// size.
+func (l *Log) readRecord(r *bufio.Reader) ([]byte, int, error) {
+	var header [recordHeaderSize]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		sum    = binary.LittleEndian.Uint32(header[0:4])
+		length = binary.LittleEndian.Uint32(header[4:8])
+	)
+
+	if length > uint32(l.opts.maxRecordSize) {
+		return nil, 0, fmt.Errorf("record of %d bytes exceeds limit", length)
+	}
+
+	body := make([]byte, length)
+
+	if _, err := io.ReadFull(r, body); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, 0, err
+	}
+
+	if crc32.Checksum(body, crcTable) != sum {
+		return nil, 0, errors.New("checksum mismatch")
+	}
+
+	return body, recordHeaderSize + len(body), nil
+}
+
+// encodeRecord returns a record of the following format:
+//
+//	checksum (uint32) | length (uint32) | op (byte) | key length (uvarint) | key | payload
+func (l *Log) encodeRecord(o op, key redblack.Key, payload interface{}) ([]byte, error) {
+	k, err := l.opts.codec.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("wal: encoding key: %w", err)
+	}
+
+	var p []byte
+
+	if o == opUpsert {
+		if p, err = l.opts.codec.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("wal: encoding payload: %w", err)
+		}
+	}
+
+	var (
+		rec  = make([]byte, recordHeaderSize, recordHeaderSize+1+binary.MaxVarintLen64+len(k)+len(p))
+		klen [binary.MaxVarintLen64]byte
+	)
+
+	rec = append(rec, byte(o))
+	rec = append(rec, klen[:binary.PutUvarint(klen[:], uint64(len(k)))]...)
+	rec = append(rec, k...)
+	rec = append(rec, p...)
+
+	body := rec[recordHeaderSize:]
+
+	binary.LittleEndian.PutUint32(rec[0:4], crc32.Checksum(body, crcTable))
+	binary.LittleEndian.PutUint32(rec[4:8], uint32(len(body)))
+
+	return rec, nil
+}
+
+func (l *Log) apply(body []byte) error {
+	if len(body) == 0 {
+		return errors.New("empty record")
+	}
+
+	o := op(body[0])
+
+	klen, n := binary.Uvarint(body[1:])
+	if n <= 0 || uint64(len(body)-1-n) < klen {
+		return errors.New("malformed record")
+	}
+
+	var (
+		start = 1 + n
+		end   = start + int(klen)
+	)
+
+	key, err := l.opts.codec.Unmarshal(body[start:end])
+	if err != nil {
+		return fmt.Errorf("decoding key: %w", err)
+	}
+
+	k, ok := key.(redblack.Key)
+	if !ok {
+		return fmt.Errorf("decoded key of type %T doesn't implement redblack.Key", key)
+	}
+
+	switch o {
+	case opUpsert:
+		payload, err := l.opts.codec.Unmarshal(body[end:])
+		if err != nil {
+			return fmt.Errorf("decoding payload: %w", err)
+		}
+
+		l.tree.Upsert(k, payload)
+	case opDelete:
+		l.tree.Delete(k)
+	default:
+		return fmt.Errorf("unknown operation %d", o)
+	}
+
+	return nil
+}
+
+func writeHeader(w io.Writer, magic [4]byte) error {
+	_, err := w.Write(append(magic[:], version))
+	return err
+}
+
+func readHeader(r io.Reader, magic [4]byte) error {
+	var header [len(magic) + 1]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(header[:len(magic)], magic[:]) {
+		return errors.New("invalid magic number")
+	}
+
+	if header[len(magic)] != version {
+		return fmt.Errorf("unsupported version %d", header[len(magic)])
+	}
+
+	return nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -0,0 +1,334 @@
+package wal
+
+import (
+	"encoding/gob"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obitech/go-trees/redblack"
+)
+
+type myInt int
+
+func (i myInt) Less(v redblack.Key) bool {
+	return i < v.(myInt)
+}
+
+func init() {
+	gob.Register(myInt(0))
+}
+
+func open(t *testing.T, dir string, opts ...Option) *Log {
+	l, err := Open(dir, opts...)
+	require.NoError(t, err)
+
+	return l
+}
+
+func contents(l *Log) map[myInt]interface{} {
+	res := make(map[myInt]interface{})
+
+	for _, r := range l.Tree().InOrder() {
+		res[r.Key.(myInt)] = r.Payload
+	}
+
+	return res
+}
+
+func TestLog(t *testing.T) {
+	t.Run("opening an empty directory yields an empty tree", func(t *testing.T) {
+		l := open(t, filepath.Join(t.TempDir(), "new"))
+		defer l.Close()
+
+		assert.Nil(t, l.Tree().Root())
+	})
+
+	t.Run("modifications survive a reopen", func(t *testing.T) {
+		dir := t.TempDir()
+		l := open(t, dir)
+
+		require.NoError(t, l.Upsert(myInt(15), "15"))
+		require.NoError(t, l.Upsert(myInt(10), "10"))
+		require.NoError(t, l.Upsert(myInt(20), 20))
+		require.NoError(t, l.Upsert(myInt(10), "ten"))
+		require.NoError(t, l.Delete(myInt(15)))
+		require.NoError(t, l.Close())
+
+		l = open(t, dir)
+		defer l.Close()
+
+		assert.Equal(t, map[myInt]interface{}{10: "ten", 20: 20}, contents(l))
+	})
+
+	t.Run("checkpoint truncates the log and is restored on open", func(t *testing.T) {
+		dir := t.TempDir()
+		l := open(t, dir, WithCheckpointEvery(0))
+
+		for i := 0; i < 100; i++ {
+			require.NoError(t, l.Upsert(myInt(i), i))
+		}
+
+		require.NoError(t, l.Checkpoint())
+
+		info, err := os.Stat(filepath.Join(dir, logFile))
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(logMagic)+1), info.Size())
+
+		require.NoError(t, l.Delete(myInt(0)))
+		require.NoError(t, l.Upsert(myInt(1), "one"))
+		require.NoError(t, l.Close())
+
+		l = open(t, dir)
+		defer l.Close()
+
+		got := contents(l)
+
+		assert.Len(t, got, 99)
+		assert.Equal(t, "one", got[1])
+		assert.Equal(t, 99, got[99])
+	})
+
+	t.Run("checkpoints are written automatically", func(t *testing.T) {
+		dir := t.TempDir()
+		l := open(t, dir, WithCheckpointEvery(10))
+
+		for i := 0; i < 25; i++ {
+			require.NoError(t, l.Upsert(myInt(i), i))
+		}
+
+		assert.Equal(t, 5, l.count)
+		assert.FileExists(t, filepath.Join(dir, checkpointFile))
+		require.NoError(t, l.Close())
+
+		l = open(t, dir)
+		defer l.Close()
+
+		assert.Len(t, contents(l), 25)
+	})
+
+	t.Run("log replayed on top of checkpoint after crash during truncation", func(t *testing.T) {
+		dir := t.TempDir()
+		l := open(t, dir, WithCheckpointEvery(0))
+
+		require.NoError(t, l.Upsert(myInt(1), "1"))
+		require.NoError(t, l.Upsert(myInt(2), "2"))
+		require.NoError(t, l.Delete(myInt(1)))
+		require.NoError(t, l.Close())
+
+		log, err := ioutil.ReadFile(filepath.Join(dir, logFile))
+		require.NoError(t, err)
+
+		l = open(t, dir, WithCheckpointEvery(0))
+		require.NoError(t, l.Checkpoint())
+		require.NoError(t, l.Close())
+
+		// Restore the untruncated log.
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, logFile), log, 0o644))
+
+		l = open(t, dir)
+		defer l.Close()
+
+		assert.Equal(t, map[myInt]interface{}{2: "2"}, contents(l))
+	})
+
+	t.Run("operations on a closed log return ErrClosed", func(t *testing.T) {
+		l := open(t, t.TempDir())
+		require.NoError(t, l.Close())
+
+		assert.Equal(t, ErrClosed, l.Upsert(myInt(1), nil))
+		assert.Equal(t, ErrClosed, l.Delete(myInt(1)))
+		assert.Equal(t, ErrClosed, l.Sync())
+		assert.Equal(t, ErrClosed, l.Checkpoint())
+		assert.Equal(t, ErrClosed, l.Close())
+	})
+
+	t.Run("payloads that can't be encoded are rejected", func(t *testing.T) {
+		l := open(t, t.TempDir())
+		defer l.Close()
+
+		assert.Error(t, l.Upsert(myInt(1), func() {}))
+		assert.Nil(t, l.Tree().Search(myInt(1)))
+	})
+}
+
+func TestLog_tornWrites(t *testing.T) {
+	dir := t.TempDir()
+	l := open(t, dir, WithCheckpointEvery(0))
+
+	var (
+		sizes []int64
+		path  = filepath.Join(dir, logFile)
+	)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, l.Upsert(myInt(i), i))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+
+		sizes = append(sizes, info.Size())
+	}
+
+	require.NoError(t, l.Close())
+
+	log, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	for size := int64(0); size <= int64(len(log)); size++ {
+		// The number of records fully contained in the truncated log.
+		var want int
+		for want < len(sizes) && sizes[want] <= size {
+			want++
+		}
+
+		crashed := t.TempDir()
+		require.NoError(t, ioutil.WriteFile(filepath.Join(crashed, logFile), log[:size], 0o644))
+
+		l := open(t, crashed)
+		assert.Len(t, contents(l), want, "log truncated to %d bytes", size)
+
+		// The torn tail is discarded, so new writes are readable again.
+		require.NoError(t, l.Upsert(myInt(99), 99))
+		require.NoError(t, l.Close())
+
+		l = open(t, crashed)
+		assert.Len(t, contents(l), want+1, "log truncated to %d bytes", size)
+		require.NoError(t, l.Close())
+	}
+
+	t.Run("corrupted record discards the tail", func(t *testing.T) {
+		crashed := t.TempDir()
+		corrupted := append([]byte(nil), log...)
+		corrupted[sizes[2]+recordHeaderSize] ^= 0xff
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(crashed, logFile), corrupted, 0o644))
+
+		l := open(t, crashed)
+		defer l.Close()
+
+		assert.Len(t, contents(l), 3)
+	})
+
+	t.Run("invalid magic number returns error", func(t *testing.T) {
+		crashed := t.TempDir()
+		require.NoError(t, ioutil.WriteFile(filepath.Join(crashed, logFile), []byte("nope!"), 0o644))
+
+		_, err := Open(crashed)
+		assert.Error(t, err)
+	})
+}
+
+func TestLog_syncPolicies(t *testing.T) {
+	tt := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "always", opts: []Option{WithSyncPolicy(SyncAlways)}},
+		{name: "interval", opts: []Option{WithSyncInterval(time.Millisecond)}},
+		{name: "never", opts: []Option{WithSyncPolicy(SyncNever)}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := open(t, dir, tc.opts...)
+
+			require.NoError(t, l.Upsert(myInt(1), "1"))
+
+			if tc.name == "interval" {
+				assert.Eventually(t, func() bool {
+					l.mu.Lock()
+					defer l.mu.Unlock()
+
+					return !l.dirty
+				}, time.Second, time.Millisecond)
+			}
+
+			require.NoError(t, l.Sync())
+			require.NoError(t, l.Close())
+
+			l = open(t, dir)
+			defer l.Close()
+
+			assert.Equal(t, "1", l.Tree().Search(myInt(1)))
+		})
+	}
+}
+
+// faultyFile fails the next write after writing half of it, or the next sync.
+type faultyFile struct {
+	file
+	failWrite bool
+	failSync  bool
+}
+
+var errInjected = errors.New("injected failure")
+
+func (f *faultyFile) Write(b []byte) (int, error) {
+	if !f.failWrite {
+		return f.file.Write(b)
+	}
+
+	f.failWrite = false
+
+	n, _ := f.file.Write(b[:len(b)/2])
+
+	return n, errInjected
+}
+
+func (f *faultyFile) Sync() error {
+	if !f.failSync {
+		return f.file.Sync()
+	}
+
+	f.failSync = false
+
+	return errInjected
+}
+
+func TestLog_failedWrites(t *testing.T) {
+	tt := []struct {
+		name   string
+		inject func(f *faultyFile)
+	}{
+		{name: "short write", inject: func(f *faultyFile) { f.failWrite = true }},
+		{name: "failed sync", inject: func(f *faultyFile) { f.failSync = true }},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := open(t, dir, WithSyncPolicy(SyncAlways), WithCheckpointEvery(0))
+
+			f := &faultyFile{file: l.file}
+			l.file = f
+
+			require.NoError(t, l.Upsert(myInt(1), "1"))
+
+			tc.inject(f)
+			assert.Equal(t, errInjected, l.Upsert(myInt(2), "2"))
+
+			tc.inject(f)
+			assert.Equal(t, errInjected, l.Delete(myInt(1)))
+
+			assert.Equal(t, map[myInt]interface{}{1: "1"}, contents(l))
+
+			// Writes after the failure are acknowledged and must survive.
+			require.NoError(t, l.Upsert(myInt(3), "3"))
+			require.NoError(t, l.Delete(myInt(1)))
+			require.NoError(t, l.Close())
+
+			l = open(t, dir)
+			defer l.Close()
+
+			assert.Equal(t, map[myInt]interface{}{3: "3"}, contents(l))
+		})
+	}
+}
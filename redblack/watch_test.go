@@ -0,0 +1,119 @@
+package redblack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	select {
+	case e, ok := <-ch:
+		require.True(t, ok, "channel closed unexpectedly")
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for event")
+	}
+
+	return Event{}
+}
+
+func TestTree_Watch(t *testing.T) {
+	t.Run("watcher receives insert, update and delete events", func(t *testing.T) {
+		tree := NewRedBlackTree()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := tree.Watch(ctx, nil)
+
+		tree.Upsert(myInt(15), "foo")
+		tree.Upsert(myInt(15), "bar")
+		tree.Delete(myInt(15))
+		tree.Delete(myInt(99))
+
+		assert.Equal(t, Event{Type: EventInsert, Key: myInt(15), New: "foo"}, receive(t, events))
+		assert.Equal(t, Event{Type: EventUpdate, Key: myInt(15), Old: "foo", New: "bar"}, receive(t, events))
+		assert.Equal(t, Event{Type: EventDelete, Key: myInt(15), Old: "bar"}, receive(t, events))
+	})
+
+	t.Run("key range filter drops events outside of range", func(t *testing.T) {
+		tree := NewRedBlackTree()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := tree.Watch(ctx, KeyRange(myInt(10), myInt(20)))
+
+		for _, k := range []myInt{5, 10, 15, 20, 25} {
+			tree.Upsert(k, int(k))
+		}
+
+		assert.Equal(t, myInt(10), receive(t, events).Key)
+		assert.Equal(t, myInt(15), receive(t, events).Key)
+
+		select {
+		case e := <-events:
+			assert.Failf(t, "unexpected event", "%+v", e)
+		case <-time.After(10 * time.Millisecond):
+		}
+	})
+
+	t.Run("writers don't block on slow watchers", func(t *testing.T) {
+		tree := NewRedBlackTree()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := tree.Watch(ctx, nil)
+
+		for i := 0; i < 1000; i++ {
+			tree.Upsert(myInt(i), i)
+		}
+
+		for i := 0; i < 1000; i++ {
+			assert.Equal(t, myInt(i), receive(t, events).Key)
+		}
+	})
+
+	t.Run("cancelling the context closes the channel", func(t *testing.T) {
+		tree := NewRedBlackTree()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events := tree.Watch(ctx, nil)
+
+		cancel()
+
+		for range events {
+		}
+
+		assert.Zero(t, tree.watchers.Len())
+
+		tree.Upsert(myInt(1), nil)
+	})
+}
+
+func TestKeyRange(t *testing.T) {
+	tt := []struct {
+		name string
+		from Key
+		to   Key
+		key  Key
+		want bool
+	}{
+		{name: "open range matches", key: myInt(5), want: true},
+		{name: "lower bound is inclusive", from: myInt(5), key: myInt(5), want: true},
+		{name: "upper bound is exclusive", to: myInt(5), key: myInt(5)},
+		{name: "key below range", from: myInt(5), to: myInt(10), key: myInt(4)},
+		{name: "key within range", from: myInt(5), to: myInt(10), key: myInt(7), want: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, KeyRange(tc.from, tc.to)(tc.key))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package rope
+
+// Delete removes n bytes starting at position pos. Panics if the range is out
+// of bounds.
+func (r *Rope) Delete(pos, n int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.checkRange(pos, pos+n)
+
+	for n > 0 {
+		z, off := r.locate(pos, false)
+
+		cut := len(z.chunk) - off
+		if cut > n {
+			cut = n
+		}
+
+		n -= cut
+
+		if cut == len(z.chunk) {
+			r.delete(z)
+			continue
+		}
+
+		z.chunk = append(z.chunk[:off], z.chunk[off+cut:]...)
+		r.recalc(z)
+	}
+
+	// Merge the chunks around the gap if they fit into one, so repeated
+	// edits don't fragment the text.
+	if pos == 0 || pos == r.root.size {
+		return
+	}
+
+	z, _ := r.locate(pos-1, false)
+
+	if next := r.successor(z); next != r.sentinel && len(z.chunk)+len(next.chunk) <= r.chunkSize {
+		z.chunk = append(z.chunk, next.chunk...)
+		r.recalc(z)
+		r.delete(next)
+	}
+}
+
+func (r *Rope) delete(z *node) {
+	var (
+		y              = z
+		yOriginalColor = y.color
+		x              *node
+	)
+
+	switch {
+	case z.left == r.sentinel:
+		x = z.right
+		r.transplant(z, z.right)
+	case z.right == r.sentinel:
+		x = z.left
+		r.transplant(z, z.left)
+	default:
+		y = r.min(z.right)
+		yOriginalColor = y.color
+
+		x = y.right
+
+		if y.parent == z {
+			x.parent = y
+		} else {
+			r.transplant(y, y.right)
+			y.right = z.right
+			y.right.parent = y
+		}
+
+		r.transplant(z, y)
+
+		y.left = z.left
+		y.left.parent = y
+		y.color = z.color
+	}
+
+	// The subtree of x is unchanged, but x may be the sentinel, whose parent
+	// is where the tree changed.
+	r.recalc(x.parent)
+
+	if yOriginalColor == black {
+		r.fixupDelete(x)
+	}
+}
+
+func (r *Rope) transplant(u, v *node) {
+	switch {
+	case u.parent == r.sentinel:
+		r.root = v
+	case u == u.parent.left:
+		u.parent.left = v
+	default:
+		u.parent.right = v
+	}
+
+	v.parent = u.parent
+}
+
+func (r *Rope) fixupDelete(x *node) {
+	for x != r.root && x.color == black {
+		if x == x.parent.left {
+			w := x.parent.right
+
+			if w.color == red {
+				w.color = black
+				x.parent.color = red
+
+				r.rotateLeft(x.parent)
+
+				w = x.parent.right
+			}
+
+			switch {
+			case w.left.color == black && w.right.color == black:
+				w.color = red
+				x = x.parent
+			case w.right.color == black:
+				w.left.color = black
+				w.color = red
+
+				r.rotateRight(w)
+
+				w = x.parent.right
+			default:
+				w.color = x.parent.color
+				x.parent.color = black
+				w.right.color = black
+
+				r.rotateLeft(x.parent)
+
+				x = r.root
+			}
+		} else {
+			w := x.parent.left
+
+			if w.color == red {
+				w.color = black
+				x.parent.color = red
+
+				r.rotateRight(x.parent)
+
+				w = x.parent.left
+			}
+
+			switch {
+			case w.right.color == black && w.left.color == black:
+				w.color = red
+				x = x.parent
+			case w.left.color == black:
+				w.right.color = black
+				w.color = red
+
+				r.rotateLeft(w)
+
+				w = x.parent.left
+			default:
+				w.color = x.parent.color
+				x.parent.color = black
+				w.left.color = black
+
+				r.rotateRight(x.parent)
+
+				x = r.root
+			}
+		}
+	}
+	x.color = black
+}
@@ -0,0 +1,129 @@
+package rope
+
+import "fmt"
+
+// Insert inserts s at position pos, moving the text from pos onwards behind
+// it. Panics if pos is out of bounds.
+func (r *Rope) Insert(pos int, s string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if pos < 0 || pos > r.root.size {
+		panic(fmt.Sprintf("rope: position %d out of bounds for length %d", pos, r.root.size))
+	}
+
+	if s == "" {
+		return
+	}
+
+	if r.root == r.sentinel {
+		prev := r.sentinel
+
+		for _, chunk := range r.cut([]byte(s)) {
+			prev = r.insertAfter(prev, chunk)
+		}
+
+		return
+	}
+
+	n, off := r.locate(pos, true)
+
+	text := make([]byte, 0, len(n.chunk)+len(s))
+	text = append(text, n.chunk[:off]...)
+	text = append(text, s...)
+	text = append(text, n.chunk[off:]...)
+
+	// The node keeps the first chunk, the rest follows it in new nodes.
+	chunks := r.cut(text)
+
+	n.chunk = chunks[0]
+	r.recalc(n)
+
+	for _, chunk := range chunks[1:] {
+		n = r.insertAfter(n, chunk)
+	}
+}
+
+// cut splits text into chunks of at most chunkSize bytes.
+func (r *Rope) cut(text []byte) [][]byte {
+	chunks := make([][]byte, 0, (len(text)+r.chunkSize-1)/r.chunkSize)
+
+	for len(text) > r.chunkSize {
+		chunks = append(chunks, text[:r.chunkSize:r.chunkSize])
+		text = text[r.chunkSize:]
+	}
+
+	return append(chunks, text)
+}
+
+// insertAfter inserts a node holding chunk directly behind n in the order of
+// the text, or as the first node if n is the sentinel, and returns it.
+func (r *Rope) insertAfter(n *node, chunk []byte) *node {
+	z := &node{
+		chunk:  chunk,
+		left:   r.sentinel,
+		right:  r.sentinel,
+		color:  red,
+		parent: r.sentinel,
+	}
+
+	switch {
+	case r.root == r.sentinel:
+		r.root = z
+	case n == r.sentinel:
+		z.parent = r.min(r.root)
+		z.parent.left = z
+	case n.right == r.sentinel:
+		z.parent = n
+		n.right = z
+	default:
+		z.parent = r.min(n.right)
+		z.parent.left = z
+	}
+
+	r.recalc(z)
+	r.fixupInsert(z)
+
+	return z
+}
+
+func (r *Rope) fixupInsert(z *node) {
+	for z.parent.color == red {
+		if z.parent == z.parent.parent.left {
+			y := z.parent.parent.right
+
+			switch {
+			case y.color == red:
+				z.parent.color = black
+				y.color = black
+				z.parent.parent.color = red
+				z = z.parent.parent
+			case z == z.parent.right:
+				z = z.parent
+				r.rotateLeft(z)
+			default:
+				z.parent.color = black
+				z.parent.parent.color = red
+				r.rotateRight(z.parent.parent)
+			}
+		} else {
+			y := z.parent.parent.left
+
+			switch {
+			case y.color == red:
+				z.parent.color = black
+				y.color = black
+				z.parent.parent.color = red
+				z = z.parent.parent
+			case z == z.parent.left:
+				z = z.parent
+				r.rotateRight(z)
+			default:
+				z.parent.color = black
+				z.parent.parent.color = red
+				r.rotateLeft(z.parent.parent)
+			}
+		}
+	}
+	r.root.color = black
+}
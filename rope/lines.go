@@ -0,0 +1,114 @@
+package rope
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Lines returns the number of lines, which is one more than the number of
+// newlines in the text.
+func (r *Rope) Lines() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.root.lines + 1
+}
+
+// LineCol returns the line and column of position pos. A position at the end
+// of the text is at the end of the last line. Panics if pos is out of bounds.
+func (r *Rope) LineCol(pos int) (line, col int) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if pos < 0 || pos > r.root.size {
+		panic(fmt.Sprintf("rope: position %d out of bounds for length %d", pos, r.root.size))
+	}
+
+	// Count the newlines before pos.
+	for n, rest := r.root, pos; n != r.sentinel; {
+		if rest < n.left.size {
+			n = n.left
+			continue
+		}
+
+		line += n.left.lines
+		rest -= n.left.size
+
+		if rest <= len(n.chunk) {
+			line += bytes.Count(n.chunk[:rest], newline)
+			break
+		}
+
+		line += bytes.Count(n.chunk, newline)
+		rest -= len(n.chunk)
+		n = n.right
+	}
+
+	return line, pos - r.lineStart(line)
+}
+
+// Offset returns the position of the given line and column. Panics if the
+// line doesn't exist or the column is beyond its end.
+func (r *Rope) Offset(line, col int) int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if line < 0 || line > r.root.lines {
+		panic(fmt.Sprintf("rope: line %d out of bounds for %d lines", line, r.root.lines+1))
+	}
+
+	var (
+		start = r.lineStart(line)
+		end   = r.root.size
+	)
+
+	if line < r.root.lines {
+		end = r.lineStart(line+1) - 1
+	}
+
+	if col < 0 || col > end-start {
+		panic(fmt.Sprintf("rope: column %d out of bounds for line %d of length %d", col, line, end-start))
+	}
+
+	return start + col
+}
+
+// lineStart returns the position of the first byte of the given line, which
+// directly follows the line-th newline.
+func (r *Rope) lineStart(line int) int {
+	if line == 0 {
+		return 0
+	}
+
+	var (
+		n   = r.root
+		pos int
+	)
+
+	for {
+		if line <= n.left.lines {
+			n = n.left
+			continue
+		}
+
+		line -= n.left.lines
+		pos += n.left.size
+
+		if lines := bytes.Count(n.chunk, newline); line > lines {
+			line -= lines
+			pos += len(n.chunk)
+			n = n.right
+
+			continue
+		}
+
+		// The line starts within this chunk, behind its line-th newline.
+		for i, c := range n.chunk {
+			if c == '\n' {
+				if line--; line == 0 {
+					return pos + i + 1
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package rope
+
+// Option configures a Rope on construction.
+type Option func(*Rope)
+
+// WithChunkSize sets the maximum number of bytes stored per node. Larger
+// chunks use less memory, smaller chunks make edits cheaper. Panics if n is
+// less than 1.
+func WithChunkSize(n int) Option {
+	if n < 1 {
+		panic("rope: chunk size must be at least 1")
+	}
+
+	return func(r *Rope) {
+		r.chunkSize = n
+	}
+}
+
+// WithoutLocking disables the internal lock of the rope.
+func WithoutLocking() Option {
+	return func(r *Rope) {
+		r.lock.Disable()
+	}
+}
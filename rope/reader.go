@@ -0,0 +1,68 @@
+package rope
+
+import "io"
+
+// Reader returns an io.Reader streaming the text from position 0. Edits made
+// between calls to Read are visible to the reader, which continues at the
+// same position.
+func (r *Rope) Reader() io.Reader {
+	return &reader{rope: r}
+}
+
+type reader struct {
+	rope *Rope
+	pos  int
+}
+
+// Read implements io.Reader.
+func (rd *reader) Read(p []byte) (int, error) {
+	r := rd.rope
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if rd.pos >= r.root.size {
+		return 0, io.EOF
+	}
+
+	var (
+		to = rd.pos + len(p)
+		n  int
+	)
+
+	if to > r.root.size {
+		to = r.root.size
+	}
+
+	r.each(rd.pos, to, func(chunk []byte) {
+		n += copy(p[n:], chunk)
+	})
+
+	rd.pos += n
+
+	return n, nil
+}
+
+// WriteTo implements io.WriterTo, writing the whole text to w.
+func (r *Rope) WriteTo(w io.Writer) (int64, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var (
+		written int64
+		err     error
+	)
+
+	r.each(0, r.root.size, func(chunk []byte) {
+		if err != nil {
+			return
+		}
+
+		var n int
+
+		n, err = w.Write(chunk)
+		written += int64(n)
+	})
+
+	return written, err
+}
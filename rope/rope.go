@@ -0,0 +1,269 @@
+// Package rope implements a rope, which stores a large text as a sequence of
+// chunks in the nodes of a red-black tree. Every node counts the bytes and
+// newlines in its subtree, so inserting and deleting text at arbitrary
+// positions, as well as translating between positions and lines, runs in
+// O(lg n) instead of copying the whole text.
+//
+// Positions and columns are byte offsets, starting at zero.
+package rope
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// DefaultChunkSize is the maximum number of bytes stored per node unless
+// WithChunkSize is passed.
+const DefaultChunkSize = 1024
+
+type color int
+
+const (
+	red   color = 0
+	black color = 1
+)
+
+// Rope represents a rope with a root node and a lock to protect concurrent
+// access.
+type Rope struct {
+	lock      lock.RWMutex
+	root      *node
+	sentinel  *node
+	chunkSize int
+}
+
+// node holds a chunk of the text, which owns its backing array up to its
+// capacity. size and lines are the number of bytes and newlines in the
+// subtree rooted at the node, and are zero for the sentinel.
+type node struct {
+	chunk  []byte
+	size   int
+	lines  int
+	color  color
+	left   *node
+	right  *node
+	parent *node
+}
+
+// NewRope returns a new rope holding s. Unless WithoutLocking is passed, all
+// operations on the rope are safe to be accessed concurrently.
+func NewRope(s string, opts ...Option) *Rope {
+	sentinel := &node{color: black}
+
+	r := &Rope{
+		root:      sentinel,
+		sentinel:  sentinel,
+		chunkSize: DefaultChunkSize,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.Insert(0, s)
+
+	return r
+}
+
+// Len returns the length of the text in bytes.
+func (r *Rope) Len() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.root.size
+}
+
+// Index returns the byte at position i. Panics if i is out of bounds.
+func (r *Rope) Index(i int) byte {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if i < 0 || i >= r.root.size {
+		panic(fmt.Sprintf("rope: index %d out of bounds for length %d", i, r.root.size))
+	}
+
+	n, off := r.locate(i, false)
+
+	return n.chunk[off]
+}
+
+// Slice returns the text in [from, to). Panics if the range is out of bounds.
+func (r *Rope) Slice(from, to int) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	r.checkRange(from, to)
+
+	var b strings.Builder
+
+	b.Grow(to - from)
+	r.each(from, to, func(chunk []byte) {
+		b.Write(chunk)
+	})
+
+	return b.String()
+}
+
+// String returns the whole text.
+func (r *Rope) String() string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var b strings.Builder
+
+	b.Grow(r.root.size)
+	r.each(0, r.root.size, func(chunk []byte) {
+		b.Write(chunk)
+	})
+
+	return b.String()
+}
+
+func (r *Rope) checkRange(from, to int) {
+	if from < 0 || to < from || to > r.root.size {
+		panic(fmt.Sprintf("rope: range [%d, %d) out of bounds for length %d", from, to, r.root.size))
+	}
+}
+
+// each calls fn with the parts of all chunks within [from, to), in order.
+func (r *Rope) each(from, to int, fn func(chunk []byte)) {
+	if from == to {
+		return
+	}
+
+	n, off := r.locate(from, false)
+
+	for to > from {
+		chunk := n.chunk[off:]
+		if len(chunk) > to-from {
+			chunk = chunk[:to-from]
+		}
+
+		fn(chunk)
+
+		from += len(chunk)
+		n, off = r.successor(n), 0
+	}
+}
+
+// locate returns the node holding position pos along with the offset of pos
+// in its chunk. If end is true, a position at the end of a chunk is located
+// in that chunk rather than at the start of the next one, which is where
+// text gets inserted.
+func (r *Rope) locate(pos int, end bool) (*node, int) {
+	n := r.root
+
+	for {
+		if pos < n.left.size {
+			n = n.left
+			continue
+		}
+
+		pos -= n.left.size
+
+		if pos < len(n.chunk) || end && pos == len(n.chunk) || n.right == r.sentinel {
+			return n, pos
+		}
+
+		pos -= len(n.chunk)
+		n = n.right
+	}
+}
+
+// update recalculates the counts of z from its chunk and its children.
+func (r *Rope) update(z *node) {
+	z.size = z.left.size + len(z.chunk) + z.right.size
+	z.lines = z.left.lines + bytes.Count(z.chunk, newline) + z.right.lines
+}
+
+// recalc updates the counts of z and all of its ancestors.
+func (r *Rope) recalc(z *node) {
+	for z != r.sentinel {
+		r.update(z)
+		z = z.parent
+	}
+}
+
+var newline = []byte{'\n'}
+
+func (r *Rope) min(z *node) *node {
+	for z.left != r.sentinel {
+		z = z.left
+	}
+
+	return z
+}
+
+func (r *Rope) successor(z *node) *node {
+	if z.right != r.sentinel {
+		return r.min(z.right)
+	}
+
+	parent := z.parent
+
+	for parent != r.sentinel && z == parent.right {
+		z = parent
+		parent = z.parent
+	}
+
+	return parent
+}
+
+func (r *Rope) rotateLeft(x *node) {
+	// y's left subtree will be x's right subtree.
+	y := x.right
+	x.right = y.left
+
+	if y.left != r.sentinel {
+		y.left.parent = x
+	}
+
+	// Restore parent relationships.
+	y.parent = x.parent
+
+	switch {
+	case x.parent == r.sentinel:
+		r.root = y
+	case x.parent.left == x:
+		x.parent.left = y
+	default:
+		x.parent.right = y
+	}
+
+	// x will be y's new left-child.
+	y.left = x
+	x.parent = y
+
+	// x is now below y, so it has to be updated first.
+	r.update(x)
+	r.update(y)
+}
+
+func (r *Rope) rotateRight(x *node) {
+	y := x.left
+	x.left = y.right
+
+	if y.right != r.sentinel {
+		y.right.parent = x
+	}
+
+	y.parent = x.parent
+
+	switch {
+	case x.parent == r.sentinel:
+		r.root = y
+	case x.parent.left == x:
+		x.parent.left = y
+	default:
+		x.parent.right = y
+	}
+
+	y.right = x
+	x.parent = y
+
+	r.update(x)
+	r.update(y)
+}
@@ -0,0 +1,273 @@
+package rope
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verify checks the red-black properties and the counts of all nodes, and
+// that no chunk is empty or exceeds the chunk size.
+func verify(t *testing.T, r *Rope) {
+	var walk func(n *node) int
+
+	walk = func(n *node) int {
+		if n == r.sentinel {
+			return 1
+		}
+
+		require.NotEmpty(t, n.chunk)
+		require.LessOrEqual(t, len(n.chunk), r.chunkSize)
+
+		if n.color == red {
+			require.Equal(t, black, n.left.color, "red node with red child")
+			require.Equal(t, black, n.right.color, "red node with red child")
+		}
+
+		for _, c := range []*node{n.left, n.right} {
+			if c != r.sentinel {
+				require.Equal(t, n, c.parent)
+			}
+		}
+
+		left, right := walk(n.left), walk(n.right)
+		require.Equal(t, left, right, "black heights differ")
+
+		require.Equal(t, n.left.size+len(n.chunk)+n.right.size, n.size)
+		require.Equal(t, n.left.lines+bytes.Count(n.chunk, newline)+n.right.lines, n.lines)
+
+		if n.color == black {
+			return left + 1
+		}
+
+		return left
+	}
+
+	require.Equal(t, black, r.root.color)
+	require.Zero(t, r.sentinel.size)
+	require.Zero(t, r.sentinel.lines)
+	walk(r.root)
+}
+
+func randomText(rng *rand.Rand, n int) string {
+	const alphabet = "abc\n"
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rng.Intn(len(alphabet))]
+	}
+
+	return string(b)
+}
+
+func TestRope(t *testing.T) {
+	r := NewRope("hello world", WithChunkSize(4))
+
+	verify(t, r)
+	assert.Equal(t, 11, r.Len())
+	assert.Equal(t, "hello world", r.String())
+	assert.Equal(t, byte('w'), r.Index(6))
+	assert.Equal(t, "lo wo", r.Slice(3, 8))
+	assert.Equal(t, "", r.Slice(5, 5))
+
+	r.Insert(5, ",")
+	r.Insert(12, "!")
+	r.Insert(0, ">> ")
+	assert.Equal(t, ">> hello, world!", r.String())
+
+	r.Delete(0, 3)
+	r.Delete(5, 7)
+	assert.Equal(t, "hello!", r.String())
+
+	r.Delete(0, r.Len())
+	verify(t, r)
+	assert.Equal(t, 0, r.Len())
+	assert.Equal(t, "", r.String())
+
+	r.Insert(0, "again")
+	assert.Equal(t, "again", r.String())
+
+	assert.Equal(t, "", NewRope("").String())
+}
+
+// chunks returns the chunks of r in the order of the text.
+func chunks(r *Rope) []string {
+	var res []string
+
+	r.each(0, r.Len(), func(chunk []byte) {
+		res = append(res, string(chunk))
+	})
+
+	return res
+}
+
+func TestRope_chunks(t *testing.T) {
+	tt := []struct {
+		name string
+		edit func(r *Rope)
+		want []string
+	}{
+		{name: "text is cut into full chunks", edit: func(r *Rope) {}, want: []string{"hell", "o wo", "rld"}},
+		{name: "overflowing chunk is cut", edit: func(r *Rope) { r.Insert(2, "XY") }, want: []string{"heXY", "ll", "o wo", "rld"}},
+		{name: "insert at chunk boundary appends to the left chunk", edit: func(r *Rope) { r.Insert(8, "!") }, want: []string{"hell", "o wo", "!", "rld"}},
+		{name: "deleting a whole chunk removes it", edit: func(r *Rope) { r.Delete(4, 4) }, want: []string{"hell", "rld"}},
+		{name: "chunks around the gap are merged if they fit", edit: func(r *Rope) { r.Delete(2, 4) }, want: []string{"hewo", "rld"}},
+		{name: "chunks around the gap are kept if they don't fit", edit: func(r *Rope) { r.Delete(1, 1) }, want: []string{"hll", "o wo", "rld"}},
+		{name: "deleting the end doesn't merge", edit: func(r *Rope) { r.Delete(9, 2) }, want: []string{"hell", "o wo", "r"}},
+		{name: "deleting everything leaves no chunks", edit: func(r *Rope) { r.Delete(0, 11) }},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRope("hello world", WithChunkSize(4))
+
+			tc.edit(r)
+
+			assert.Equal(t, tc.want, chunks(r))
+			verify(t, r)
+		})
+	}
+
+	t.Run("edits don't write into neighbouring chunks", func(t *testing.T) {
+		r := NewRope("abcdefgh", WithChunkSize(4))
+
+		r.Delete(2, 1)
+		r.Insert(3, "X")
+
+		assert.Equal(t, []string{"abdX", "efgh"}, chunks(r))
+	})
+}
+
+func TestRope_random(t *testing.T) {
+	for _, chunkSize := range []int{1, 3, 16, DefaultChunkSize} {
+		var (
+			rng  = rand.New(rand.NewSource(int64(chunkSize)))
+			r    = NewRope("", WithChunkSize(chunkSize), WithoutLocking())
+			want string
+		)
+
+		for i := 0; i < 2000; i++ {
+			pos := rng.Intn(len(want) + 1)
+
+			if len(want) > 0 && rng.Intn(3) == 0 {
+				n := rng.Intn(len(want) - pos + 1)
+				if n > 50 {
+					n = 50
+				}
+
+				r.Delete(pos, n)
+				want = want[:pos] + want[pos+n:]
+			} else {
+				s := randomText(rng, rng.Intn(40))
+
+				r.Insert(pos, s)
+				want = want[:pos] + s + want[pos:]
+			}
+
+			if i%100 != 0 {
+				continue
+			}
+
+			verify(t, r)
+			require.Equal(t, want, r.String(), "chunk size %d", chunkSize)
+			require.Equal(t, len(want), r.Len())
+
+			if len(want) > 0 {
+				from := rng.Intn(len(want))
+				to := from + rng.Intn(len(want)-from+1)
+
+				require.Equal(t, want[from:to], r.Slice(from, to))
+				require.Equal(t, want[from], r.Index(from))
+			}
+		}
+	}
+}
+
+func TestRope_lines(t *testing.T) {
+	const text = "first\nsecond\n\nfourth"
+
+	r := NewRope(text, WithChunkSize(3))
+
+	assert.Equal(t, 4, r.Lines())
+	assert.Equal(t, 1, NewRope("").Lines())
+	assert.Equal(t, 2, NewRope("\n").Lines())
+
+	for pos := 0; pos <= len(text); pos++ {
+		var (
+			line            = strings.Count(text[:pos], "\n")
+			col             = pos - strings.LastIndex(text[:pos], "\n") - 1
+			gotLine, gotCol = r.LineCol(pos)
+		)
+
+		assert.Equal(t, line, gotLine, "line of %d", pos)
+		assert.Equal(t, col, gotCol, "column of %d", pos)
+		assert.Equal(t, pos, r.Offset(line, col))
+	}
+
+	assert.Equal(t, 13, r.Offset(2, 0))
+	assert.Equal(t, 20, r.Offset(3, 6))
+
+	assert.Panics(t, func() { r.Offset(4, 0) })
+	assert.Panics(t, func() { r.Offset(2, 1) })
+	assert.Panics(t, func() { r.Offset(0, 6) })
+	assert.Panics(t, func() { r.LineCol(21) })
+
+	t.Run("random", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		text := randomText(rng, 5000)
+		r := NewRope(text, WithChunkSize(7))
+
+		require.Equal(t, strings.Count(text, "\n")+1, r.Lines())
+
+		for i := 0; i < 500; i++ {
+			pos := rng.Intn(len(text) + 1)
+			line, col := r.LineCol(pos)
+
+			require.Equal(t, strings.Count(text[:pos], "\n"), line)
+			require.Equal(t, pos, r.Offset(line, col))
+		}
+	})
+}
+
+func TestRope_Reader(t *testing.T) {
+	text := randomText(rand.New(rand.NewSource(1)), 10000)
+	r := NewRope(text, WithChunkSize(100))
+
+	got, err := ioutil.ReadAll(r.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, text, string(got))
+
+	got, err = ioutil.ReadAll(iotest.OneByteReader(r.Reader()))
+	require.NoError(t, err)
+	assert.Equal(t, text, string(got))
+
+	var b bytes.Buffer
+
+	n, err := r.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(text)), n)
+	assert.Equal(t, text, b.String())
+
+	_, err = NewRope("").Reader().Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRope_bounds(t *testing.T) {
+	r := NewRope("abc")
+
+	assert.Panics(t, func() { r.Insert(4, "x") })
+	assert.Panics(t, func() { r.Insert(-1, "x") })
+	assert.Panics(t, func() { r.Delete(2, 2) })
+	assert.Panics(t, func() { r.Delete(-1, 1) })
+	assert.Panics(t, func() { r.Index(3) })
+	assert.Panics(t, func() { r.Slice(2, 1) })
+	assert.Panics(t, func() { WithChunkSize(0) })
+	assert.Equal(t, "abc", r.String())
+}
@@ -0,0 +1,81 @@
+package rtree
+
+import "reflect"
+
+// Delete removes a rectangle stored with the given payload, which is compared
+// using reflect.DeepEqual, so payloads don't need to be comparable. If the rectangle has been stored multiple times with that payload,
+// only one of them is removed. Returns false if there was no such rectangle.
+func (t *Tree) Delete(r Rect, payload interface{}) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var orphans []orphan
+
+	if !t.delete(t.root, t.height, r, payload, &orphans) {
+		return false
+	}
+
+	t.size--
+
+	// Entries of nodes which underflowed are inserted again on their
+	// original level, which keeps all leaves on the same level.
+	for _, o := range orphans {
+		for _, e := range o.node.entries {
+			t.insert(e, o.level)
+		}
+	}
+
+	for !t.root.leaf && len(t.root.entries) == 1 {
+		t.root = t.root.entries[0].child
+		t.height--
+	}
+
+	return true
+}
+
+// orphan is a node which has been removed from the tree because it
+// underflowed.
+type orphan struct {
+	node  *node
+	level int
+}
+
+// delete removes the rectangle from the subtree rooted at n on level nLevel.
+// Children which underflow as a consequence are removed from n and appended
+// to orphans.
+func (t *Tree) delete(n *node, nLevel int, r Rect, payload interface{}, orphans *[]orphan) bool {
+	if n.leaf {
+		for i, e := range n.entries {
+			if e.rect == r && reflect.DeepEqual(e.payload, payload) {
+				n.entries = removeEntry(n.entries, i)
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i, e := range n.entries {
+		if !e.rect.Intersects(r) || !t.delete(e.child, nLevel-1, r, payload, orphans) {
+			continue
+		}
+
+		if len(e.child.entries) < t.minEntries() {
+			*orphans = append(*orphans, orphan{node: e.child, level: nLevel - 1})
+			n.entries = removeEntry(n.entries, i)
+		} else {
+			n.entries[i].rect = e.child.bounds()
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func removeEntry(entries []entry, i int) []entry {
+	copy(entries[i:], entries[i+1:])
+	entries[len(entries)-1] = entry{}
+
+	return entries[:len(entries)-1]
+}
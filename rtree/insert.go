@@ -0,0 +1,181 @@
+package rtree
+
+import "math"
+
+// Insert stores a rectangle with a payload. The same rectangle may be stored
+// multiple times. Returns ErrInvalidRect if the minimum of the rectangle
+// exceeds its maximum.
+func (t *Tree) Insert(r Rect, payload interface{}) error {
+	if !r.valid() {
+		return ErrInvalidRect
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.insert(entry{rect: r, payload: payload}, 0)
+	t.size++
+
+	return nil
+}
+
+// insert adds e to a node on the given level, leaves being on level zero.
+func (t *Tree) insert(e entry, level int) {
+	sibling := t.insertAt(t.root, t.height, e, level)
+
+	// Splitting the root is the only way the tree grows in height.
+	if sibling != nil {
+		t.root = &node{
+			entries: []entry{
+				{rect: t.root.bounds(), child: t.root},
+				{rect: sibling.bounds(), child: sibling},
+			},
+		}
+		t.height++
+	}
+}
+
+// insertAt adds e to the subtree rooted at n on level nLevel. If n overflows,
+// it's split and the new sibling is returned.
+func (t *Tree) insertAt(n *node, nLevel int, e entry, level int) *node {
+	if nLevel == level {
+		n.entries = append(n.entries, e)
+	} else {
+		i := chooseSubtree(n, e.rect)
+		child := n.entries[i].child
+
+		sibling := t.insertAt(child, nLevel-1, e, level)
+
+		n.entries[i].rect = child.bounds()
+
+		if sibling != nil {
+			n.entries = append(n.entries, entry{rect: sibling.bounds(), child: sibling})
+		}
+	}
+
+	if len(n.entries) > t.maxEntries {
+		return t.split(n)
+	}
+
+	return nil
+}
+
+// chooseSubtree returns the index of the entry of n which needs the least
+// enlargement to include r, resolving ties by the smallest area.
+func chooseSubtree(n *node, r Rect) int {
+	var (
+		best                  int
+		bestEnlarge, bestArea = math.Inf(1), math.Inf(1)
+	)
+
+	for i, e := range n.entries {
+		enlarge, area := e.rect.enlargement(r), e.rect.Area()
+
+		if enlarge < bestEnlarge || (enlarge == bestEnlarge && area < bestArea) {
+			best, bestEnlarge, bestArea = i, enlarge, area
+		}
+	}
+
+	return best
+}
+
+// split distributes the entries of the overflowing node n between n and a new
+// sibling using Guttman's quadratic split, and returns the sibling.
+func (t *Tree) split(n *node) *node {
+	var (
+		entries = n.entries
+		min     = t.minEntries()
+		a, b    = pickSeeds(entries)
+		groups  = [2][]entry{{entries[a]}, {entries[b]}}
+		bounds  = [2]Rect{entries[a].rect, entries[b].rect}
+		rest    = make([]entry, 0, len(entries)-2)
+	)
+
+	for i, e := range entries {
+		if i != a && i != b {
+			rest = append(rest, e)
+		}
+	}
+
+	for len(rest) > 0 {
+		// If a group needs all remaining entries to reach the minimum, it
+		// gets them.
+		for g := range groups {
+			if len(groups[g])+len(rest) == min {
+				for _, e := range rest {
+					bounds[g] = bounds[g].union(e.rect)
+				}
+
+				groups[g] = append(groups[g], rest...)
+				rest = nil
+			}
+		}
+
+		if len(rest) == 0 {
+			break
+		}
+
+		// Pick the entry with the greatest preference for one group.
+		var (
+			next       int
+			d0, d1     float64
+			preference = -1.0
+		)
+
+		for i, e := range rest {
+			e0, e1 := bounds[0].enlargement(e.rect), bounds[1].enlargement(e.rect)
+
+			if diff := math.Abs(e0 - e1); diff > preference {
+				next, d0, d1, preference = i, e0, e1, diff
+			}
+		}
+
+		g := 0
+
+		switch {
+		case d0 > d1:
+			g = 1
+		case d0 < d1:
+		case bounds[0].Area() > bounds[1].Area():
+			g = 1
+		case bounds[0].Area() < bounds[1].Area():
+		case len(groups[0]) > len(groups[1]):
+			g = 1
+		}
+
+		e := rest[next]
+		rest[next] = rest[len(rest)-1]
+		rest = rest[:len(rest)-1]
+
+		groups[g] = append(groups[g], e)
+		bounds[g] = bounds[g].union(e.rect)
+	}
+
+	n.entries = groups[0]
+
+	return &node{
+		leaf:    n.leaf,
+		entries: groups[1],
+	}
+}
+
+// pickSeeds returns the indices of the two entries which would waste the most
+// area if they were put into the same group.
+func pickSeeds(entries []entry) (int, int) {
+	var (
+		a, b  = 0, 1
+		worst = math.Inf(-1)
+	)
+
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			waste := entries[i].rect.union(entries[j].rect).Area() - entries[i].rect.Area() - entries[j].rect.Area()
+
+			if waste > worst {
+				a, b, worst = i, j, waste
+			}
+		}
+	}
+
+	return a, b
+}
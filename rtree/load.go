@@ -0,0 +1,107 @@
+package rtree
+
+import (
+	"math"
+	"sort"
+)
+
+// Load replaces the contents of the tree with the given rectangles using
+// Sort-Tile-Recursive bulk loading, which is considerably faster than
+// inserting them one by one and yields nodes with less overlap. The Distance
+// of the results is ignored. Returns ErrInvalidRect, leaving the tree
+// unchanged, if the minimum of a rectangle exceeds its maximum.
+func (t *Tree) Load(items []Result) error {
+	entries := make([]entry, len(items))
+
+	for i, item := range items {
+		if !item.Rect.valid() {
+			return ErrInvalidRect
+		}
+
+		entries[i] = entry{rect: item.Rect, payload: item.Payload}
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.size, t.height = len(entries), 0
+
+	level := t.pack(entries, true)
+
+	for len(level) > 1 {
+		entries = make([]entry, len(level))
+
+		for i, n := range level {
+			entries[i] = entry{rect: n.bounds(), child: n}
+		}
+
+		level = t.pack(entries, false)
+		t.height++
+	}
+
+	if len(level) == 0 {
+		level = []*node{{leaf: true}}
+	}
+
+	t.root = level[0]
+
+	return nil
+}
+
+// pack tiles the entries into nodes. Entries are sorted by the x coordinate
+// of their center and cut into vertical slices of about sqrt(n/M) nodes each,
+// which are then sorted by the y coordinate and cut into nodes.
+func (t *Tree) pack(entries []entry, leaf bool) []*node {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var (
+		leaves = (len(entries) + t.maxEntries - 1) / t.maxEntries
+		slices = int(math.Ceil(math.Sqrt(float64(leaves))))
+		nodes  = make([]*node, 0, leaves)
+	)
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].rect.center().X < entries[j].rect.center().X
+	})
+
+	for _, size := range spread(len(entries), slices*t.maxEntries) {
+		slice := entries[:size]
+		entries = entries[size:]
+
+		sort.Slice(slice, func(i, j int) bool {
+			return slice[i].rect.center().Y < slice[j].rect.center().Y
+		})
+
+		for _, size := range spread(len(slice), t.maxEntries) {
+			nodes = append(nodes, &node{
+				leaf:    leaf,
+				entries: append([]entry(nil), slice[:size]...),
+			})
+			slice = slice[size:]
+		}
+	}
+
+	return nodes
+}
+
+// spread splits n elements into as few groups of at most max elements as
+// possible, with sizes differing by at most one. This keeps the last group
+// from underflowing.
+func spread(n, max int) []int {
+	var (
+		groups = (n + max - 1) / max
+		sizes  = make([]int, groups)
+	)
+
+	for i := range sizes {
+		sizes[i] = n / groups
+
+		if i < n%groups {
+			sizes[i]++
+		}
+	}
+
+	return sizes
+}
@@ -0,0 +1,24 @@
+package rtree
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithMaxEntries sets the maximum number of entries per node. Every node but
+// the root holds at least 40% of that, and no less than two entries. Panics
+// if m is less than 4.
+func WithMaxEntries(m int) Option {
+	if m < 4 {
+		panic("rtree: max entries must be at least 4")
+	}
+
+	return func(t *Tree) {
+		t.maxEntries = m
+	}
+}
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
@@ -0,0 +1,64 @@
+package rtree
+
+import "math"
+
+// Point is a point in the plane.
+type Point struct {
+	X, Y float64
+}
+
+// Rect is an axis-aligned rectangle, including its bounds. Min has to be less
+// than or equal to Max on both axes. Points can be stored as rectangles with
+// Min equal to Max.
+type Rect struct {
+	Min, Max Point
+}
+
+// Intersects returns true if r and o share at least one point.
+func (r Rect) Intersects(o Rect) bool {
+	return r.Min.X <= o.Max.X && o.Min.X <= r.Max.X &&
+		r.Min.Y <= o.Max.Y && o.Min.Y <= r.Max.Y
+}
+
+// Contains returns true if p lies within r.
+func (r Rect) Contains(p Point) bool {
+	return r.Min.X <= p.X && p.X <= r.Max.X &&
+		r.Min.Y <= p.Y && p.Y <= r.Max.Y
+}
+
+// Area returns the area of r.
+func (r Rect) Area() float64 {
+	return (r.Max.X - r.Min.X) * (r.Max.Y - r.Min.Y)
+}
+
+// Distance returns the Euclidean distance between p and the closest point of
+// r, which is zero if r contains p.
+func (r Rect) Distance(p Point) float64 {
+	var (
+		dx = math.Max(0, math.Max(r.Min.X-p.X, p.X-r.Max.X))
+		dy = math.Max(0, math.Max(r.Min.Y-p.Y, p.Y-r.Max.Y))
+	)
+
+	return math.Hypot(dx, dy)
+}
+
+func (r Rect) valid() bool {
+	return r.Min.X <= r.Max.X && r.Min.Y <= r.Max.Y
+}
+
+// union returns the smallest rectangle containing r and o.
+func (r Rect) union(o Rect) Rect {
+	return Rect{
+		Min: Point{math.Min(r.Min.X, o.Min.X), math.Min(r.Min.Y, o.Min.Y)},
+		Max: Point{math.Max(r.Max.X, o.Max.X), math.Max(r.Max.Y, o.Max.Y)},
+	}
+}
+
+// enlargement returns the area r has to grow by to contain o.
+func (r Rect) enlargement(o Rect) float64 {
+	return r.union(o).Area() - r.Area()
+}
+
+func (r Rect) center() Point {
+	return Point{(r.Min.X + r.Max.X) / 2, (r.Min.Y + r.Max.Y) / 2}
+}
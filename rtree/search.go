@@ -0,0 +1,129 @@
+package rtree
+
+import "container/heap"
+
+// SearchIntersecting returns all rectangles which share at least one point
+// with r, in no particular order.
+func (t *Tree) SearchIntersecting(r Rect) []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var res []Result
+
+	search(t.root, r.Intersects, r.Intersects, &res)
+
+	return res
+}
+
+// SearchContaining returns all rectangles which contain p, in no particular
+// order.
+func (t *Tree) SearchContaining(p Point) []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var (
+		res      []Result
+		contains = func(r Rect) bool { return r.Contains(p) }
+	)
+
+	search(t.root, contains, contains, &res)
+
+	return res
+}
+
+// search descends into all entries of inner nodes for which descend returns
+// true, and collects all entries of leaves for which match returns true.
+func search(n *node, descend, match func(Rect) bool, res *[]Result) {
+	for _, e := range n.entries {
+		switch {
+		case n.leaf && match(e.rect):
+			*res = append(*res, Result{Rect: e.rect, Payload: e.payload})
+		case !n.leaf && descend(e.rect):
+			search(e.child, descend, match, res)
+		}
+	}
+}
+
+// Nearest returns the rectangle closest to p, measured from p to the closest
+// point of the rectangle. Returns false if the tree is empty.
+func (t *Tree) Nearest(p Point) (Result, bool) {
+	res := t.KNearest(p, 1)
+
+	if len(res) == 0 {
+		return Result{}, false
+	}
+
+	return res[0], true
+}
+
+// KNearest returns the k rectangles closest to p, ordered by ascending
+// distance. Returns fewer rectangles if the tree holds less than k.
+func (t *Tree) KNearest(p Point, k int) []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if k <= 0 {
+		return nil
+	}
+
+	var (
+		res   []Result
+		queue = candidates{{node: t.root}}
+	)
+
+	// Best-first search: nodes and rectangles are visited by ascending
+	// distance, so a rectangle reaching the front of the queue is closer than
+	// everything not yet visited.
+	for len(queue) > 0 && len(res) < k {
+		c := heap.Pop(&queue).(candidate)
+
+		if c.node == nil {
+			res = append(res, Result{Rect: c.rect, Payload: c.payload, Distance: c.distance})
+			continue
+		}
+
+		for _, e := range c.node.entries {
+			next := candidate{
+				distance: e.rect.Distance(p),
+				rect:     e.rect,
+				payload:  e.payload,
+			}
+
+			if !c.node.leaf {
+				next.node = e.child
+			}
+
+			heap.Push(&queue, next)
+		}
+	}
+
+	return res
+}
+
+// candidate is either a node or a rectangle in the queue of a nearest
+// neighbour search.
+type candidate struct {
+	distance float64
+	node     *node
+	rect     Rect
+	payload  interface{}
+}
+
+// candidates is a min-heap of candidates by distance.
+type candidates []candidate
+
+func (c candidates) Len() int { return len(c) }
+
+func (c candidates) Less(i, j int) bool { return c[i].distance < c[j].distance }
+
+func (c candidates) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+
+func (c *candidates) Push(x interface{}) { *c = append(*c, x.(candidate)) }
+
+func (c *candidates) Pop() interface{} {
+	old := *c
+	x := old[len(old)-1]
+	*c = old[:len(old)-1]
+
+	return x
+}
@@ -0,0 +1,118 @@
+// Package rtree implements an R-tree, which indexes rectangles in the plane by
+// grouping nearby rectangles under their bounding boxes. It answers
+// intersection, containment and nearest neighbour queries without looking at
+// most of the rectangles. Nodes are split with Guttman's quadratic split, and
+// trees can be bulk loaded with the Sort-Tile-Recursive algorithm.
+package rtree
+
+import (
+	"errors"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// DefaultMaxEntries is the maximum number of entries per node unless
+// WithMaxEntries is passed.
+const DefaultMaxEntries = 16
+
+// ErrInvalidRect is returned if the minimum of a rectangle exceeds its
+// maximum.
+var ErrInvalidRect = errors.New("rtree: invalid rectangle")
+
+// Result is a rectangle stored in the tree along with its payload. Distance
+// is the distance to the query point of a nearest neighbour search.
+type Result struct {
+	Rect     Rect
+	Payload  interface{}
+	Distance float64
+}
+
+// Tree represents an R-tree with a root node and a lock to protect concurrent
+// access.
+type Tree struct {
+	lock       lock.RWMutex
+	root       *node
+	height     int
+	maxEntries int
+	size       int
+}
+
+// node holds between minEntries and maxEntries entries, the root may hold
+// less. All leaves are on the same level.
+type node struct {
+	leaf    bool
+	entries []entry
+}
+
+// entry is either a rectangle with a payload in a leaf, or the bounding box
+// of a child in an inner node.
+type entry struct {
+	rect    Rect
+	child   *node
+	payload interface{}
+}
+
+// NewRTree returns a new R-tree. Unless WithoutLocking is passed, all
+// operations on the tree are safe to be accessed concurrently.
+func NewRTree(opts ...Option) *Tree {
+	t := &Tree{
+		maxEntries: DefaultMaxEntries,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	t.root = &node{leaf: true}
+
+	return t
+}
+
+// Len returns the number of rectangles in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Height returns the height of the tree, counted in nodes. A tree with only a
+// root leaf has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.height
+}
+
+// Bounds returns the bounding box of all rectangles in the tree, and false if
+// the tree is empty.
+func (t *Tree) Bounds() (Rect, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if len(t.root.entries) == 0 {
+		return Rect{}, false
+	}
+
+	return t.root.bounds(), true
+}
+
+func (t *Tree) minEntries() int {
+	if m := t.maxEntries * 40 / 100; m > 2 {
+		return m
+	}
+
+	return 2
+}
+
+// bounds returns the bounding box of all entries of n.
+func (n *node) bounds() Rect {
+	r := n.entries[0].rect
+
+	for _, e := range n.entries[1:] {
+		r = r.union(e.rect)
+	}
+
+	return r
+}
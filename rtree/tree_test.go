@@ -0,0 +1,345 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verify checks that all leaves are on the same level, that every node but
+// the root holds between the minimum and maximum number of entries, and that
+// the rectangles of inner entries are the bounding boxes of their children.
+func verify(t *testing.T, tree *Tree) {
+	var (
+		size int
+		walk func(n *node, level int)
+	)
+
+	walk = func(n *node, level int) {
+		require.Equal(t, level == 0, n.leaf, "leaf on level %d", level)
+		require.LessOrEqual(t, len(n.entries), tree.maxEntries)
+
+		if n != tree.root {
+			require.GreaterOrEqual(t, len(n.entries), tree.minEntries())
+		}
+
+		if n.leaf {
+			size += len(n.entries)
+			return
+		}
+
+		for _, e := range n.entries {
+			require.Equal(t, e.child.bounds(), e.rect)
+			walk(e.child, level-1)
+		}
+	}
+
+	walk(tree.root, tree.height)
+	require.Equal(t, tree.size, size)
+}
+
+func randomRect(rng *rand.Rand) Rect {
+	var (
+		x, y = float64(rng.Intn(1000)), float64(rng.Intn(1000))
+		w, h = float64(rng.Intn(50)), float64(rng.Intn(50))
+	)
+
+	return Rect{Min: Point{x, y}, Max: Point{x + w, y + h}}
+}
+
+// sorted orders results by payload, which are ints in all tests.
+func sorted(res []Result) []int {
+	ids := make([]int, len(res))
+
+	for i, r := range res {
+		ids[i] = r.Payload.(int)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// bruteForce returns the payloads of all rectangles matching fn.
+func bruteForce(rects map[int]Rect, fn func(Rect) bool) []int {
+	ids := []int{}
+
+	for id, r := range rects {
+		if fn(r) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+func TestRect(t *testing.T) {
+	r := Rect{Min: Point{0, 0}, Max: Point{2, 1}}
+
+	assert.True(t, r.Intersects(Rect{Min: Point{2, 1}, Max: Point{3, 3}}))
+	assert.False(t, r.Intersects(Rect{Min: Point{2.5, 0}, Max: Point{3, 3}}))
+	assert.True(t, r.Contains(Point{2, 0.5}))
+	assert.False(t, r.Contains(Point{-1, 0.5}))
+	assert.Equal(t, 2.0, r.Area())
+	assert.Equal(t, 0.0, r.Distance(Point{1, 1}))
+	assert.Equal(t, 5.0, r.Distance(Point{5, 5}))
+	assert.Equal(t, 1.0, r.Distance(Point{1, -1}))
+}
+
+func TestTree_Insert(t *testing.T) {
+	var (
+		rng   = rand.New(rand.NewSource(1))
+		tree  = NewRTree(WithMaxEntries(4))
+		rects = make(map[int]Rect)
+	)
+
+	_, ok := tree.Bounds()
+	assert.False(t, ok)
+
+	for i := 0; i < 1000; i++ {
+		rects[i] = randomRect(rng)
+		require.NoError(t, tree.Insert(rects[i], i))
+	}
+
+	verify(t, tree)
+	assert.Equal(t, 1000, tree.Len())
+	assert.Greater(t, tree.Height(), 3)
+
+	bounds, ok := tree.Bounds()
+	require.True(t, ok)
+
+	for _, r := range rects {
+		assert.Equal(t, bounds, bounds.union(r))
+	}
+
+	assert.Equal(t, ErrInvalidRect, tree.Insert(Rect{Min: Point{1, 0}, Max: Point{0, 1}}, nil))
+	assert.Equal(t, 1000, tree.Len())
+}
+
+func TestTree_Delete(t *testing.T) {
+	var (
+		rng   = rand.New(rand.NewSource(2))
+		tree  = NewRTree(WithMaxEntries(5))
+		rects = make(map[int]Rect)
+	)
+
+	for i := 0; i < 500; i++ {
+		rects[i] = randomRect(rng)
+		require.NoError(t, tree.Insert(rects[i], i))
+	}
+
+	assert.False(t, tree.Delete(rects[0], 1), "payload doesn't match")
+	assert.False(t, tree.Delete(Rect{Max: Point{-1, -1}}, 0))
+
+	for _, id := range rng.Perm(500) {
+		require.True(t, tree.Delete(rects[id], id))
+		require.False(t, tree.Delete(rects[id], id))
+		delete(rects, id)
+
+		if id%10 == 0 {
+			verify(t, tree)
+
+			q := randomRect(rng)
+			require.Equal(t, bruteForce(rects, q.Intersects), sorted(tree.SearchIntersecting(q)))
+		}
+	}
+
+	verify(t, tree)
+	assert.Equal(t, 0, tree.Len())
+	assert.Equal(t, 0, tree.Height())
+
+	t.Run("duplicates are removed one at a time", func(t *testing.T) {
+		r := Rect{Max: Point{1, 1}}
+
+		require.NoError(t, tree.Insert(r, 1))
+		require.NoError(t, tree.Insert(r, 1))
+
+		assert.True(t, tree.Delete(r, 1))
+		assert.Len(t, tree.SearchContaining(Point{}), 1)
+		assert.True(t, tree.Delete(r, 1))
+		assert.Empty(t, tree.SearchContaining(Point{}))
+	})
+
+	t.Run("uncomparable payloads", func(t *testing.T) {
+		r := Rect{Max: Point{1, 1}}
+
+		require.NoError(t, tree.Insert(r, []byte("a")))
+		require.NoError(t, tree.Insert(r, []byte("b")))
+
+		assert.False(t, tree.Delete(r, []byte("c")))
+		assert.True(t, tree.Delete(r, []byte("a")))
+		res := tree.SearchContaining(Point{})
+		require.Len(t, res, 1)
+		assert.Equal(t, []byte("b"), res[0].Payload)
+	})
+}
+
+func TestTree_random(t *testing.T) {
+	var (
+		rng   = rand.New(rand.NewSource(3))
+		tree  = NewRTree(WithMaxEntries(6), WithoutLocking())
+		rects = make(map[int]Rect)
+	)
+
+	for i := 0; i < 5000; i++ {
+		if len(rects) > 0 && rng.Intn(3) == 0 {
+			for id, r := range rects {
+				require.True(t, tree.Delete(r, id))
+				delete(rects, id)
+
+				break
+			}
+		} else {
+			rects[i] = randomRect(rng)
+			require.NoError(t, tree.Insert(rects[i], i))
+		}
+
+		if i%250 == 0 {
+			verify(t, tree)
+
+			var (
+				q = randomRect(rng)
+				p = Point{float64(rng.Intn(1000)), float64(rng.Intn(1000))}
+			)
+
+			require.Equal(t, bruteForce(rects, q.Intersects), sorted(tree.SearchIntersecting(q)))
+			require.Equal(t, bruteForce(rects, func(r Rect) bool { return r.Contains(p) }), sorted(tree.SearchContaining(p)))
+		}
+	}
+
+	verify(t, tree)
+	assert.Equal(t, len(rects), tree.Len())
+}
+
+func TestTree_Load(t *testing.T) {
+	tt := []struct {
+		name       string
+		n          int
+		maxEntries int
+	}{
+		{name: "empty", n: 0, maxEntries: 4},
+		{name: "single leaf", n: 3, maxEntries: 4},
+		{name: "exactly full", n: 16, maxEntries: 4},
+		{name: "uneven", n: 1001, maxEntries: 4},
+		{name: "default", n: 10000, maxEntries: DefaultMaxEntries},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rng   = rand.New(rand.NewSource(int64(tc.n)))
+				tree  = NewRTree(WithMaxEntries(tc.maxEntries))
+				rects = make(map[int]Rect)
+				items []Result
+			)
+
+			require.NoError(t, tree.Insert(Rect{}, -1))
+
+			for i := 0; i < tc.n; i++ {
+				rects[i] = randomRect(rng)
+				items = append(items, Result{Rect: rects[i], Payload: i})
+			}
+
+			require.NoError(t, tree.Load(items))
+			verify(t, tree)
+			assert.Equal(t, tc.n, tree.Len())
+
+			all := Rect{Min: Point{-1, -1}, Max: Point{2000, 2000}}
+			assert.Equal(t, bruteForce(rects, all.Intersects), sorted(tree.SearchIntersecting(all)))
+
+			// The tree stays valid when modified after loading.
+			for i := 0; i < tc.n/2; i++ {
+				require.True(t, tree.Delete(rects[i], i))
+				delete(rects, i)
+			}
+
+			require.NoError(t, tree.Insert(Rect{}, -1))
+			rects[-1] = Rect{}
+
+			verify(t, tree)
+
+			q := randomRect(rng)
+			assert.Equal(t, bruteForce(rects, q.Intersects), sorted(tree.SearchIntersecting(q)))
+		})
+	}
+
+	t.Run("invalid rectangle leaves tree unchanged", func(t *testing.T) {
+		tree := NewRTree()
+		require.NoError(t, tree.Insert(Rect{}, 0))
+
+		err := tree.Load([]Result{{Rect: Rect{Max: Point{1, 1}}}, {Rect: Rect{Min: Point{0, 2}, Max: Point{1, 1}}}})
+		assert.Equal(t, ErrInvalidRect, err)
+		assert.Equal(t, 1, tree.Len())
+	})
+}
+
+func TestTree_Nearest(t *testing.T) {
+	tree := NewRTree()
+
+	_, ok := tree.Nearest(Point{})
+	assert.False(t, ok)
+
+	require.NoError(t, tree.Insert(Rect{Min: Point{0, 0}, Max: Point{1, 1}}, "a"))
+	require.NoError(t, tree.Insert(Rect{Min: Point{3, 0}, Max: Point{4, 4}}, "b"))
+	require.NoError(t, tree.Insert(Rect{Min: Point{10, 10}, Max: Point{10, 10}}, "c"))
+
+	res, ok := tree.Nearest(Point{3.5, 2})
+	require.True(t, ok)
+	assert.Equal(t, Result{Rect: Rect{Min: Point{3, 0}, Max: Point{4, 4}}, Payload: "b"}, res)
+
+	res, ok = tree.Nearest(Point{1.5, 1})
+	require.True(t, ok)
+	assert.Equal(t, "a", res.Payload)
+	assert.Equal(t, 0.5, res.Distance)
+
+	assert.Len(t, tree.KNearest(Point{}, 5), 3)
+	assert.Nil(t, tree.KNearest(Point{}, 0))
+}
+
+func TestTree_KNearest_random(t *testing.T) {
+	var (
+		rng   = rand.New(rand.NewSource(4))
+		tree  = NewRTree(WithMaxEntries(8))
+		rects = make(map[int]Rect)
+		items []Result
+	)
+
+	for i := 0; i < 2000; i++ {
+		rects[i] = randomRect(rng)
+		items = append(items, Result{Rect: rects[i], Payload: i})
+	}
+
+	require.NoError(t, tree.Load(items))
+
+	for i := 0; i < 100; i++ {
+		var (
+			p    = Point{float64(rng.Intn(1200) - 100), float64(rng.Intn(1200) - 100)}
+			k    = rng.Intn(20) + 1
+			want []float64
+			got  []float64
+		)
+
+		for _, r := range rects {
+			want = append(want, r.Distance(p))
+		}
+
+		sort.Float64s(want)
+
+		for _, r := range tree.KNearest(p, k) {
+			require.Equal(t, rects[r.Payload.(int)].Distance(p), r.Distance)
+			got = append(got, r.Distance)
+		}
+
+		require.Equal(t, want[:k], got)
+	}
+}
+
+func TestWithMaxEntries(t *testing.T) {
+	assert.Panics(t, func() { WithMaxEntries(3) })
+	assert.NotPanics(t, func() { WithMaxEntries(4) })
+}
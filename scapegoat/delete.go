@@ -0,0 +1,51 @@
+package scapegoat
+
+// Delete deletes the node with the given key. Once the tree has shrunk below
+// alpha times its size at the last complete rebuild, it is rebuilt again,
+// which takes amortized O(lg n) time.
+func (t *Tree) Delete(key Key) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	link := &t.root
+
+	for n := t.root; n != nil; n = *link {
+		switch {
+		case key.Less(n.key):
+			link = &n.left
+			continue
+		case n.key.Less(key):
+			link = &n.right
+			continue
+		}
+
+		switch {
+		case n.left == nil:
+			*link = n.right
+		case n.right == nil:
+			*link = n.left
+		default:
+			// Unlink the successor of n, which has no left child, and put
+			// it in the place of n.
+			succ := &n.right
+			for (*succ).left != nil {
+				succ = &(*succ).left
+			}
+
+			y := *succ
+			*succ = y.right
+
+			y.left, y.right = n.left, n.right
+			*link = y
+		}
+
+		t.size--
+
+		if float64(t.size) < t.alpha*float64(t.maxSize) {
+			t.root = rebuild(t.root, t.size)
+			t.maxSize = t.size
+		}
+
+		return
+	}
+}
@@ -0,0 +1,116 @@
+package scapegoat
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+// If the new node ends up too deep, the subtree of one of its ancestors is
+// rebuilt, which takes amortized O(lg n) time.
+func (t *Tree) Upsert(key Key, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var (
+		path []*node
+		link = &t.root
+	)
+
+	for n := t.root; n != nil; n = *link {
+		switch {
+		case key.Less(n.key):
+			link = &n.left
+		case n.key.Less(key):
+			link = &n.right
+		default:
+			n.payload = payload
+			return
+		}
+
+		path = append(path, n)
+	}
+
+	z := &node{key: key, payload: payload}
+	*link = z
+
+	t.size++
+	if t.size > t.maxSize {
+		t.maxSize = t.size
+	}
+
+	if len(path) > t.maxDepth(t.size) {
+		t.rebuildScapegoat(path, z)
+	}
+}
+
+// rebuildScapegoat walks up from the too deep node z along path, the list of
+// its ancestors, and rebuilds the subtree of the first ancestor which isn't
+// alpha-weight-balanced. Such an ancestor, the scapegoat, always exists since
+// z is deeper than a balanced tree allows.
+func (t *Tree) rebuildScapegoat(path []*node, z *node) {
+	var (
+		child     = z
+		childSize = 1
+	)
+
+	for i := len(path) - 1; i >= 0; i-- {
+		p := path[i]
+
+		sibling := p.left
+		if child == p.left {
+			sibling = p.right
+		}
+
+		total := childSize + size(sibling) + 1
+
+		if float64(childSize) > t.alpha*float64(total) {
+			t.replace(path[:i], p, rebuild(p, total))
+			return
+		}
+
+		child, childSize = p, total
+	}
+}
+
+// replace makes n take the place of the child old of the last node of path,
+// or of the root if path is empty.
+func (t *Tree) replace(path []*node, old, n *node) {
+	switch {
+	case len(path) == 0:
+		t.root = n
+	case path[len(path)-1].left == old:
+		path[len(path)-1].left = n
+	default:
+		path[len(path)-1].right = n
+	}
+}
+
+// rebuild turns the subtree rooted at n, which holds size nodes, into a
+// perfectly balanced one and returns its new root.
+func rebuild(n *node, size int) *node {
+	nodes := make([]*node, 0, size)
+	flatten(n, &nodes)
+
+	return build(nodes)
+}
+
+func flatten(n *node, nodes *[]*node) {
+	if n == nil {
+		return
+	}
+
+	flatten(n.left, nodes)
+	*nodes = append(*nodes, n)
+	flatten(n.right, nodes)
+}
+
+// build links sorted nodes into a balanced tree and returns its root.
+func build(nodes []*node) *node {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	mid := len(nodes) / 2
+
+	n := nodes[mid]
+	n.left = build(nodes[:mid])
+	n.right = build(nodes[mid+1:])
+
+	return n
+}
@@ -0,0 +1,28 @@
+package scapegoat
+
+import "fmt"
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithAlpha sets the balance factor of the tree, which has to be in
+// (0.5, 1). The size of no subtree may exceed alpha times the size of its
+// parent's subtree. Lower values yield lower trees at the cost of more
+// frequent rebuilds. Defaults to DefaultAlpha. Panics if alpha is out of
+// range.
+func WithAlpha(alpha float64) Option {
+	if alpha <= 0.5 || alpha >= 1 {
+		panic(fmt.Sprintf("scapegoat: alpha %v out of range (0.5, 1)", alpha))
+	}
+
+	return func(t *Tree) {
+		t.alpha = alpha
+	}
+}
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
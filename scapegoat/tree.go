@@ -0,0 +1,180 @@
+// Package scapegoat implements a scapegoat tree, a binary search tree which
+// stores no balancing information in its nodes. Instead, a subtree is rebuilt
+// into a perfectly balanced one whenever an insertion ends up too deep, and
+// the whole tree is rebuilt once enough keys have been deleted. Lookups run in
+// worst-case O(lg n) time, modifications in amortized O(lg n) time.
+package scapegoat
+
+import (
+	"math"
+)
+
+// DefaultAlpha is the balance factor of a tree unless WithAlpha is passed.
+const DefaultAlpha = 0.7
+
+// NewScapegoatTree returns a new scapegoat tree. Unless WithoutLocking is
+// passed, all operations on the tree are safe to be accessed concurrently.
+func NewScapegoatTree(opts ...Option) *Tree {
+	t := &Tree{
+		alpha: DefaultAlpha,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.size
+}
+
+// Root returns the payload of the root node of the tree.
+func (t *Tree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a single node has a height of zero.
+// As nodes don't store their height, this runs in O(n) time.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return height(t.root)
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *Tree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return min(t.root).payload
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *Tree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return max(t.root).payload
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *Tree) Search(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if n := search(t.root, key); n != nil {
+		return n.payload
+	}
+
+	return nil
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (t *Tree) Successor(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	// The lowest ancestor whose left subtree holds key.
+	var candidate *node
+
+	for n := t.root; n != nil; {
+		switch {
+		case key.Less(n.key):
+			candidate = n
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		case n.right != nil:
+			return min(n.right).payload
+		case candidate != nil:
+			return candidate.payload
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// maxDepth returns the depth no node may exceed in a tree of n keys after an
+// insertion.
+func (t *Tree) maxDepth(n int) int {
+	return int(math.Log(float64(n)) / math.Log(1/t.alpha))
+}
+
+func search(n *node, key Key) *node {
+	for n != nil {
+		switch {
+		case key.Less(n.key):
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+func min(n *node) *node {
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+func max(n *node) *node {
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+
+	l, r := height(n.left), height(n.right)
+
+	if l > r {
+		return l + 1
+	}
+
+	return r + 1
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	return 1 + size(n.left) + size(n.right)
+}
@@ -0,0 +1,193 @@
+package scapegoat
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the number of keys, the bounds of maxSize and the height
+// bound of the tree.
+func verify(t *testing.T, tree *Tree) {
+	require.Equal(t, tree.size, size(tree.root))
+	require.LessOrEqual(t, tree.size, tree.maxSize)
+
+	// The tree is rebuilt as soon as it shrinks below alpha times maxSize.
+	require.GreaterOrEqual(t, float64(tree.size), tree.alpha*float64(tree.maxSize))
+
+	// Deletions may leave nodes one level deeper than insertions do.
+	if tree.size > 0 {
+		require.LessOrEqual(t, height(tree.root), tree.maxDepth(tree.maxSize)+1)
+	}
+}
+
+func TestTree_insertRebuild(t *testing.T) {
+	// Ascending keys form a list until the newest key is deeper than
+	// log_{1/alpha}(n), which happens the sooner the lower alpha is.
+	tt := []struct {
+		alpha float64
+		n     int
+	}{
+		{alpha: 0.51, n: 3},
+		{alpha: 0.6, n: 4},
+		{alpha: DefaultAlpha, n: 7},
+		{alpha: 0.75, n: 9},
+		{alpha: 0.9, n: 35},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("alpha=%v", tc.alpha), func(t *testing.T) {
+			tree := NewScapegoatTree(WithAlpha(tc.alpha))
+
+			for i := 0; i < tc.n-1; i++ {
+				tree.Upsert(myInt(i), i)
+			}
+
+			require.Equal(t, tc.n-2, tree.Height(), "rebuilt before key %d", tc.n)
+
+			tree.Upsert(myInt(tc.n-1), tc.n-1)
+
+			assert.Less(t, tree.Height(), tc.n-1, "not rebuilt on key %d", tc.n)
+			assert.Equal(t, tc.n, tree.maxSize)
+			verify(t, tree)
+		})
+	}
+}
+
+func TestTree_deleteRebuild(t *testing.T) {
+	const n = 100
+
+	// The whole tree is rebuilt once it holds less than alpha times the keys
+	// it held at most.
+	tt := []struct {
+		alpha     float64
+		deletions int
+	}{
+		{alpha: 0.51, deletions: 50},
+		{alpha: DefaultAlpha, deletions: 31},
+		{alpha: 0.75, deletions: 26},
+		{alpha: 0.9, deletions: 11},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("alpha=%v", tc.alpha), func(t *testing.T) {
+			var (
+				rng  = rand.New(rand.NewSource(1))
+				tree = NewScapegoatTree(WithAlpha(tc.alpha))
+				keys = rng.Perm(n)
+			)
+
+			for _, k := range keys {
+				tree.Upsert(myInt(k), k)
+			}
+
+			for _, k := range keys[:tc.deletions-1] {
+				tree.Delete(myInt(k))
+			}
+
+			require.Equal(t, n, tree.maxSize, "rebuilt before deletion %d", tc.deletions)
+
+			tree.Delete(myInt(keys[tc.deletions-1]))
+
+			size := n - tc.deletions
+			assert.Equal(t, size, tree.maxSize, "not rebuilt on deletion %d", tc.deletions)
+			assert.Equal(t, bits.Len(uint(size))-1, tree.Height(), "not perfectly balanced")
+			verify(t, tree)
+		})
+	}
+}
+
+func TestTree_deleteMissing(t *testing.T) {
+	tree := NewScapegoatTree()
+
+	for i := 0; i < 10; i++ {
+		tree.Upsert(myInt(i), i)
+	}
+
+	// Deleting missing keys must neither shrink the tree nor trigger a
+	// rebuild.
+	for i := 10; i < 20; i++ {
+		tree.Delete(myInt(i))
+	}
+
+	assert.Equal(t, 10, tree.Len())
+	assert.Equal(t, 10, tree.maxSize)
+}
+
+func TestTree_random(t *testing.T) {
+	for _, alpha := range []float64{0.51, DefaultAlpha, 0.9} {
+		var (
+			rng  = rand.New(rand.NewSource(1))
+			tree = NewScapegoatTree(WithAlpha(alpha), WithoutLocking())
+			want = make(map[int]int)
+		)
+
+		for i := 0; i < 5000; i++ {
+			k := rng.Intn(500)
+
+			if rng.Intn(3) == 0 {
+				tree.Delete(myInt(k))
+				delete(want, k)
+			} else {
+				tree.Upsert(myInt(k), i)
+				want[k] = i
+			}
+
+			if i%100 == 0 {
+				verify(t, tree)
+			}
+		}
+
+		verify(t, tree)
+		require.Equal(t, len(want), tree.Len())
+
+		for k, v := range want {
+			require.Equal(t, v, tree.Search(myInt(k)), "alpha %v", alpha)
+		}
+	}
+}
+
+func TestTree_alpha(t *testing.T) {
+	const n = 4096
+
+	var prev int
+
+	// Lower balance factors yield lower trees for sorted insertions, which
+	// would degenerate an unbalanced tree into a list.
+	for _, alpha := range []float64{0.9, 0.75, 0.6, 0.51} {
+		tree := NewScapegoatTree(WithAlpha(alpha))
+
+		for i := 0; i < n; i++ {
+			tree.Upsert(myInt(i), i)
+		}
+
+		h := tree.Height()
+		assert.LessOrEqual(t, float64(h), math.Log(n)/math.Log(1/alpha), "alpha %v", alpha)
+
+		if prev != 0 {
+			assert.LessOrEqual(t, h, prev, "alpha %v", alpha)
+		}
+
+		prev = h
+	}
+
+	assert.Panics(t, func() { WithAlpha(0.5) })
+	assert.Panics(t, func() { WithAlpha(1) })
+}
+
+func TestNewScapegoatTree_WithoutLocking(t *testing.T) {
+	tree := NewScapegoatTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+}
@@ -0,0 +1,33 @@
+package scapegoat
+
+import (
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the tree have to implement. It is shared with
+// package redblack, so the same key types work with both trees.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the tree.
+type Result = redblack.Result
+
+// Tree represents a scapegoat tree with a root node and a lock to protect
+// concurrent access. maxSize is the highest number of keys since the tree
+// was last rebuilt completely.
+type Tree struct {
+	lock    lock.RWMutex
+	root    *node
+	alpha   float64
+	size    int
+	maxSize int
+}
+
+// node carries no balancing information, so it is as small as the node of an
+// unbalanced binary search tree without parent pointers.
+type node struct {
+	key     Key
+	payload interface{}
+	left    *node
+	right   *node
+}
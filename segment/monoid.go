@@ -0,0 +1,155 @@
+package segment
+
+import "math"
+
+// Monoid defines how the values of a Tree are aggregated. Combine has to be
+// associative, and Identity has to be its neutral element. Combine doesn't
+// need to be commutative: the aggregate of a range combines its values from
+// left to right.
+type Monoid interface {
+	Identity() interface{}
+	Combine(a, b interface{}) interface{}
+}
+
+// Action is a lazy update applied to all values of a range by RangeApply.
+type Action interface {
+	// Apply returns the aggregate of size values after applying the action
+	// to each of them, given their aggregate v before.
+	Apply(v interface{}, size int) interface{}
+
+	// Compose returns an action equivalent to applying the receiver first
+	// and next second.
+	Compose(next Action) Action
+}
+
+// Sum aggregates int64 values by adding them up. Counting the values matching
+// a condition works by storing them as 0 or 1.
+type Sum struct{}
+
+// Identity implements Monoid.
+func (Sum) Identity() interface{} { return int64(0) }
+
+// Combine implements Monoid.
+func (Sum) Combine(a, b interface{}) interface{} { return a.(int64) + b.(int64) }
+
+// Min aggregates int64 values by their minimum.
+type Min struct{}
+
+// Identity implements Monoid.
+func (Min) Identity() interface{} { return int64(math.MaxInt64) }
+
+// Combine implements Monoid.
+func (Min) Combine(a, b interface{}) interface{} {
+	if a.(int64) < b.(int64) {
+		return a
+	}
+
+	return b
+}
+
+// Max aggregates int64 values by their maximum.
+type Max struct{}
+
+// Identity implements Monoid.
+func (Max) Identity() interface{} { return int64(math.MinInt64) }
+
+// Combine implements Monoid.
+func (Max) Combine(a, b interface{}) interface{} {
+	if a.(int64) > b.(int64) {
+		return a
+	}
+
+	return b
+}
+
+// SumAdd adds a delta to every value of a range of a tree using Sum.
+type SumAdd int64
+
+// Apply implements Action.
+func (d SumAdd) Apply(v interface{}, size int) interface{} {
+	return v.(int64) + int64(d)*int64(size)
+}
+
+// Compose implements Action.
+func (d SumAdd) Compose(next Action) Action {
+	switch n := next.(type) {
+	case SumAdd:
+		return d + n
+	case SumAssign:
+		return n
+	}
+
+	return chain{d, next}
+}
+
+// SumAssign sets every value of a range of a tree using Sum.
+type SumAssign int64
+
+// Apply implements Action.
+func (x SumAssign) Apply(v interface{}, size int) interface{} {
+	return int64(x) * int64(size)
+}
+
+// Compose implements Action.
+func (x SumAssign) Compose(next Action) Action {
+	switch n := next.(type) {
+	case SumAdd:
+		return x + SumAssign(n)
+	case SumAssign:
+		return n
+	}
+
+	return chain{x, next}
+}
+
+// MinMaxAdd adds a delta to every value of a range of a tree using Min or
+// Max.
+type MinMaxAdd int64
+
+// Apply implements Action.
+func (d MinMaxAdd) Apply(v interface{}, size int) interface{} {
+	return v.(int64) + int64(d)
+}
+
+// Compose implements Action.
+func (d MinMaxAdd) Compose(next Action) Action {
+	switch n := next.(type) {
+	case MinMaxAdd:
+		return d + n
+	case MinMaxAssign:
+		return n
+	}
+
+	return chain{d, next}
+}
+
+// MinMaxAssign sets every value of a range of a tree using Min or Max.
+type MinMaxAssign int64
+
+// Apply implements Action.
+func (x MinMaxAssign) Apply(v interface{}, size int) interface{} {
+	return int64(x)
+}
+
+// Compose implements Action.
+func (x MinMaxAssign) Compose(next Action) Action {
+	switch n := next.(type) {
+	case MinMaxAdd:
+		return x + MinMaxAssign(n)
+	case MinMaxAssign:
+		return n
+	}
+
+	return chain{x, next}
+}
+
+// chain applies two actions which can't be folded into one in order.
+type chain [2]Action
+
+func (c chain) Apply(v interface{}, size int) interface{} {
+	return c[1].Apply(c[0].Apply(v, size), size)
+}
+
+func (c chain) Compose(next Action) Action {
+	return chain{c, next}
+}
@@ -0,0 +1,228 @@
+// Package segment implements a segment tree, which aggregates the values of a
+// fixed-size sequence over arbitrary index ranges. The aggregation is defined
+// by a Monoid, e.g. Sum, Min or Max. Queries, point updates and lazy range
+// updates all run in O(lg n) time.
+package segment
+
+import (
+	"fmt"
+
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// Tree represents a segment tree over a sequence of values and a lock to
+// protect concurrent access. The nodes are stored in a slice, with the
+// children of node i at 2i and 2i+1 and the root at 1.
+type Tree struct {
+	lock   lock.RWMutex
+	monoid Monoid
+	n      int
+	// aggs holds the aggregate of the range of every node, including all
+	// actions pending for that node.
+	aggs []interface{}
+	// lazy holds actions which have been applied to a node but not yet to
+	// its children.
+	lazy []Action
+}
+
+// NewSegmentTree returns a new segment tree over a copy of values, aggregated
+// by m. Unless WithoutLocking is passed, all operations on the tree are safe
+// to be accessed concurrently.
+func NewSegmentTree(m Monoid, values []interface{}, opts ...Option) *Tree {
+	t := &Tree{
+		monoid: m,
+		n:      len(values),
+		aggs:   make([]interface{}, 4*len(values)),
+		lazy:   make([]Action, 4*len(values)),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if t.n > 0 {
+		t.build(1, 0, t.n, values)
+	}
+
+	return t
+}
+
+// Len returns the number of values in the tree.
+func (t *Tree) Len() int {
+	return t.n
+}
+
+// Query returns the aggregate of the values in the half-open index range
+// [l, r), or the identity of the monoid if the range is empty. Panics if the
+// range is out of bounds.
+func (t *Tree) Query(l, r int) interface{} {
+	t.checkRange(l, r)
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if l == r {
+		return t.monoid.Identity()
+	}
+
+	return t.query(1, 0, t.n, l, r)
+}
+
+// Update sets the value at index i. Panics if i is out of bounds.
+func (t *Tree) Update(i int, v interface{}) {
+	t.checkRange(i, i+1)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.update(1, 0, t.n, i, v)
+}
+
+// RangeApply applies f to all values in the half-open index range [l, r).
+// The action is only applied to the nodes covering the range, and pushed
+// down to their children once they are visited by a later update. Panics if
+// the range is out of bounds.
+func (t *Tree) RangeApply(l, r int, f Action) {
+	t.checkRange(l, r)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if l < r {
+		t.rangeApply(1, 0, t.n, l, r, f)
+	}
+}
+
+func (t *Tree) checkRange(l, r int) {
+	if l < 0 || r > t.n || l > r {
+		panic(fmt.Sprintf("segment: range [%d, %d) out of bounds for length %d", l, r, t.n))
+	}
+}
+
+// The following methods operate on node i, which covers the range [lo, hi).
+
+func (t *Tree) build(i, lo, hi int, values []interface{}) {
+	if hi-lo == 1 {
+		t.aggs[i] = values[lo]
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+
+	t.build(2*i, lo, mid, values)
+	t.build(2*i+1, mid, hi, values)
+
+	t.aggs[i] = t.monoid.Combine(t.aggs[2*i], t.aggs[2*i+1])
+}
+
+// query returns the aggregate of [l, r) intersected with [lo, hi). Pending
+// actions are applied to the partial aggregates on the way up instead of
+// being pushed down, so queries don't modify the tree.
+func (t *Tree) query(i, lo, hi, l, r int) interface{} {
+	if l <= lo && hi <= r {
+		return t.aggs[i]
+	}
+
+	var (
+		mid = lo + (hi-lo)/2
+		res = t.monoid.Identity()
+	)
+
+	if l < mid {
+		res = t.query(2*i, lo, mid, l, r)
+	}
+
+	if mid < r {
+		res = t.monoid.Combine(res, t.query(2*i+1, mid, hi, l, r))
+	}
+
+	if t.lazy[i] != nil {
+		res = t.lazy[i].Apply(res, minInt(hi, r)-maxInt(lo, l))
+	}
+
+	return res
+}
+
+func (t *Tree) update(i, lo, hi, pos int, v interface{}) {
+	if hi-lo == 1 {
+		t.aggs[i] = v
+		return
+	}
+
+	t.push(i, lo, hi)
+
+	if mid := lo + (hi-lo)/2; pos < mid {
+		t.update(2*i, lo, mid, pos, v)
+	} else {
+		t.update(2*i+1, mid, hi, pos, v)
+	}
+
+	t.aggs[i] = t.monoid.Combine(t.aggs[2*i], t.aggs[2*i+1])
+}
+
+func (t *Tree) rangeApply(i, lo, hi, l, r int, f Action) {
+	if l <= lo && hi <= r {
+		t.apply(i, lo, hi, f)
+		return
+	}
+
+	t.push(i, lo, hi)
+
+	mid := lo + (hi-lo)/2
+
+	if l < mid {
+		t.rangeApply(2*i, lo, mid, l, r, f)
+	}
+
+	if mid < r {
+		t.rangeApply(2*i+1, mid, hi, l, r, f)
+	}
+
+	t.aggs[i] = t.monoid.Combine(t.aggs[2*i], t.aggs[2*i+1])
+}
+
+// apply applies f to the aggregate of node i and records it as pending for
+// its children.
+func (t *Tree) apply(i, lo, hi int, f Action) {
+	t.aggs[i] = f.Apply(t.aggs[i], hi-lo)
+
+	if hi-lo == 1 {
+		return
+	}
+
+	if t.lazy[i] == nil {
+		t.lazy[i] = f
+	} else {
+		t.lazy[i] = t.lazy[i].Compose(f)
+	}
+}
+
+// push applies the pending action of node i to its children.
+func (t *Tree) push(i, lo, hi int) {
+	if t.lazy[i] == nil {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+
+	t.apply(2*i, lo, mid, t.lazy[i])
+	t.apply(2*i+1, mid, hi, t.lazy[i])
+
+	t.lazy[i] = nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
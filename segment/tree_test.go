@@ -0,0 +1,244 @@
+package segment
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concat is a non-commutative monoid.
+type concat struct{}
+
+func (concat) Identity() interface{}                { return "" }
+func (concat) Combine(a, b interface{}) interface{} { return a.(string) + b.(string) }
+
+// negate negates all values of a tree using Sum. It doesn't fold with other
+// actions.
+type negate struct{}
+
+func (negate) Apply(v interface{}, size int) interface{} { return -v.(int64) }
+func (negate) Compose(next Action) Action                { return chain{negate{}, next} }
+
+func values(vs ...int64) []interface{} {
+	res := make([]interface{}, len(vs))
+	for i, v := range vs {
+		res[i] = v
+	}
+
+	return res
+}
+
+func TestTree_Query(t *testing.T) {
+	vs := values(5, 3, 8, 1, 9, 2)
+
+	tt := []struct {
+		name   string
+		monoid Monoid
+		l, r   int
+		want   int64
+	}{
+		{name: "sum of all", monoid: Sum{}, l: 0, r: 6, want: 28},
+		{name: "sum of range", monoid: Sum{}, l: 1, r: 4, want: 12},
+		{name: "sum of single value", monoid: Sum{}, l: 4, r: 5, want: 9},
+		{name: "sum of empty range", monoid: Sum{}, l: 3, r: 3, want: 0},
+		{name: "min of range", monoid: Min{}, l: 0, r: 3, want: 3},
+		{name: "min of all", monoid: Min{}, l: 0, r: 6, want: 1},
+		{name: "max of range", monoid: Max{}, l: 3, r: 6, want: 9},
+		{name: "max of single value", monoid: Max{}, l: 5, r: 6, want: 2},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := NewSegmentTree(tc.monoid, vs)
+
+			assert.Equal(t, tc.want, tree.Query(tc.l, tc.r))
+		})
+	}
+
+	t.Run("combines from left to right", func(t *testing.T) {
+		tree := NewSegmentTree(concat{}, []interface{}{"a", "b", "c", "d", "e"})
+
+		assert.Equal(t, "abcde", tree.Query(0, 5))
+		assert.Equal(t, "bcd", tree.Query(1, 4))
+
+		tree.Update(2, "X")
+
+		assert.Equal(t, "abXde", tree.Query(0, 5))
+	})
+
+	t.Run("empty tree", func(t *testing.T) {
+		tree := NewSegmentTree(Sum{}, nil)
+
+		assert.Equal(t, 0, tree.Len())
+		assert.Equal(t, int64(0), tree.Query(0, 0))
+	})
+
+	t.Run("values are copied", func(t *testing.T) {
+		vs := values(1, 2, 3)
+		tree := NewSegmentTree(Sum{}, vs)
+
+		vs[0] = int64(100)
+
+		assert.Equal(t, int64(6), tree.Query(0, 3))
+	})
+
+	t.Run("out of bounds panics", func(t *testing.T) {
+		tree := NewSegmentTree(Sum{}, vs)
+
+		assert.Panics(t, func() { tree.Query(-1, 2) })
+		assert.Panics(t, func() { tree.Query(0, 7) })
+		assert.Panics(t, func() { tree.Query(3, 2) })
+		assert.Panics(t, func() { tree.Update(6, int64(1)) })
+		assert.Panics(t, func() { tree.RangeApply(0, 7, SumAdd(1)) })
+	})
+}
+
+func TestTree_RangeApply(t *testing.T) {
+	t.Run("add to sum", func(t *testing.T) {
+		tree := NewSegmentTree(Sum{}, values(1, 2, 3, 4, 5))
+
+		tree.RangeApply(1, 4, SumAdd(10))
+
+		assert.Equal(t, int64(45), tree.Query(0, 5))
+		assert.Equal(t, int64(12), tree.Query(1, 2))
+		assert.Equal(t, int64(32), tree.Query(2, 5))
+	})
+
+	t.Run("assign to min", func(t *testing.T) {
+		tree := NewSegmentTree(Min{}, values(5, 3, 8, 1, 9))
+
+		tree.RangeApply(2, 5, MinMaxAssign(4))
+
+		assert.Equal(t, int64(3), tree.Query(0, 5))
+		assert.Equal(t, int64(4), tree.Query(2, 5))
+
+		tree.RangeApply(0, 3, MinMaxAdd(-10))
+
+		assert.Equal(t, int64(-7), tree.Query(0, 5))
+		assert.Equal(t, int64(-6), tree.Query(2, 3))
+		assert.Equal(t, int64(4), tree.Query(3, 5))
+	})
+
+	t.Run("point update after range update", func(t *testing.T) {
+		tree := NewSegmentTree(Max{}, values(1, 2, 3, 4))
+
+		tree.RangeApply(0, 4, MinMaxAdd(10))
+		tree.Update(3, int64(0))
+
+		assert.Equal(t, int64(13), tree.Query(0, 4))
+		assert.Equal(t, int64(0), tree.Query(3, 4))
+	})
+
+	t.Run("actions which don't fold are chained", func(t *testing.T) {
+		tree := NewSegmentTree(Sum{}, values(1, 2, 3, 4))
+
+		tree.RangeApply(0, 4, SumAdd(1))
+		tree.RangeApply(0, 4, negate{})
+		tree.RangeApply(0, 4, SumAdd(1))
+
+		assert.Equal(t, int64(-10), tree.Query(0, 4))
+		assert.Equal(t, int64(-1), tree.Query(0, 1))
+		assert.Equal(t, int64(-4), tree.Query(3, 4))
+	})
+}
+
+func TestTree_random(t *testing.T) {
+	type op struct {
+		monoid Monoid
+		add    func(int64) Action
+		assign func(int64) Action
+		agg    func(vs []int64) int64
+	}
+
+	ops := map[string]op{
+		"sum": {
+			monoid: Sum{},
+			add:    func(d int64) Action { return SumAdd(d) },
+			assign: func(x int64) Action { return SumAssign(x) },
+			agg: func(vs []int64) int64 {
+				var s int64
+				for _, v := range vs {
+					s += v
+				}
+
+				return s
+			},
+		},
+		"min": {
+			monoid: Min{},
+			add:    func(d int64) Action { return MinMaxAdd(d) },
+			assign: func(x int64) Action { return MinMaxAssign(x) },
+			agg: func(vs []int64) int64 {
+				m := vs[0]
+				for _, v := range vs {
+					if v < m {
+						m = v
+					}
+				}
+
+				return m
+			},
+		},
+		"max": {
+			monoid: Max{},
+			add:    func(d int64) Action { return MinMaxAdd(d) },
+			assign: func(x int64) Action { return MinMaxAssign(x) },
+			agg: func(vs []int64) int64 {
+				m := vs[0]
+				for _, v := range vs {
+					if v > m {
+						m = v
+					}
+				}
+
+				return m
+			},
+		},
+	}
+
+	for name, o := range ops {
+		t.Run(name, func(t *testing.T) {
+			var (
+				rng   = rand.New(rand.NewSource(1))
+				naive = make([]int64, 1+rng.Intn(200))
+			)
+
+			for i := range naive {
+				naive[i] = rng.Int63n(1000) - 500
+			}
+
+			vs := make([]interface{}, len(naive))
+			for i, v := range naive {
+				vs[i] = v
+			}
+
+			tree := NewSegmentTree(o.monoid, vs)
+
+			for i := 0; i < 5000; i++ {
+				l := rng.Intn(len(naive))
+				r := l + 1 + rng.Intn(len(naive)-l)
+				v := rng.Int63n(100) - 50
+
+				switch rng.Intn(4) {
+				case 0:
+					tree.Update(l, v)
+					naive[l] = v
+				case 1:
+					tree.RangeApply(l, r, o.add(v))
+					for j := l; j < r; j++ {
+						naive[j] += v
+					}
+				case 2:
+					tree.RangeApply(l, r, o.assign(v))
+					for j := l; j < r; j++ {
+						naive[j] = v
+					}
+				default:
+					require.Equal(t, o.agg(naive[l:r]), tree.Query(l, r), "query [%d, %d)", l, r)
+				}
+			}
+		})
+	}
+}
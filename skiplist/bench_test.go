@@ -0,0 +1,24 @@
+package skiplist
+
+import (
+	"testing"
+
+	"github.com/obitech/go-trees/internal/bench"
+)
+
+var impls = []bench.Impl{
+	bench.RedBlack,
+	{Name: "skiplist", New: func() bench.Map { return NewSkipList(WithSeed(1)) }},
+}
+
+func BenchmarkUpsert(b *testing.B) {
+	bench.Upsert(b, impls...)
+}
+
+func BenchmarkSearch(b *testing.B) {
+	bench.Search(b, impls...)
+}
+
+func BenchmarkDelete(b *testing.B) {
+	bench.Delete(b, impls...)
+}
@@ -0,0 +1,46 @@
+package skiplist
+
+// Delete deletes the given key.
+func (s *SkipList) Delete(key Key) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		update [maxLevels]*node
+		x      = s.head
+	)
+
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && x.levels[i].forward.key.Less(key) {
+			x = x.levels[i].forward
+		}
+
+		update[i] = x
+	}
+
+	x = x.levels[0].forward
+	if x == nil || key.Less(x.key) {
+		return
+	}
+
+	for i := 0; i < s.level; i++ {
+		if update[i].levels[i].forward == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].forward = x.levels[i].forward
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x.backward
+	} else {
+		s.tail = x.backward
+	}
+
+	for s.level > 1 && s.head.levels[s.level-1].forward == nil {
+		s.level--
+	}
+
+	s.length--
+}
@@ -0,0 +1,77 @@
+package skiplist
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+func (s *SkipList) Upsert(key Key, payload interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		// update[i] is the last node on level i with a key less than key,
+		// and rank[i] its position on the lowest level.
+		update [maxLevels]*node
+		rank   [maxLevels]int
+		x      = s.head
+	)
+
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+
+		for x.levels[i].forward != nil && x.levels[i].forward.key.Less(key) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].forward
+		}
+
+		update[i] = x
+	}
+
+	if next := x.levels[0].forward; next != nil && !key.Less(next.key) {
+		next.payload = payload
+		return
+	}
+
+	lvl := s.randomLevel()
+
+	if lvl > s.level {
+		for i := s.level; i < lvl; i++ {
+			update[i] = s.head
+			s.head.levels[i].span = s.length
+		}
+
+		s.level = lvl
+	}
+
+	n := &node{
+		key:     key,
+		payload: payload,
+		levels:  make([]level, lvl),
+	}
+
+	for i := 0; i < lvl; i++ {
+		n.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = n
+
+		// The link of update[i] gets split in two at n, which is rank[0]-rank[i]
+		// nodes behind update[i] on the lowest level.
+		n.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+
+	// Links above n now skip one more node.
+	for i := lvl; i < s.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != s.head {
+		n.backward = update[0]
+	}
+
+	if n.levels[0].forward != nil {
+		n.levels[0].forward.backward = n
+	} else {
+		s.tail = n
+	}
+
+	s.length++
+}
@@ -0,0 +1,178 @@
+// Package skiplist implements a skip list, an ordered map made of a hierarchy
+// of linked lists with each level skipping over more nodes than the one below.
+// The levels of new nodes are chosen randomly, which balances the list with
+// high probability. Search, insertion and deletion run in expected O(lg n)
+// time, and every link counts the nodes it skips so keys can be looked up by
+// rank.
+package skiplist
+
+import (
+	"math/rand"
+	"time"
+)
+
+// NewSkipList returns a new skip list. Unless WithoutLocking is passed, all
+// operations on the list are safe to be accessed concurrently.
+func NewSkipList(opts ...Option) *SkipList {
+	s := &SkipList{
+		level:    1,
+		maxLevel: DefaultMaxLevel,
+		p:        DefaultProbability,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.rng == nil {
+		s.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	s.head = &node{
+		levels: make([]level, s.maxLevel),
+	}
+
+	return s
+}
+
+// Len returns the number of keys in the list.
+func (s *SkipList) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.length
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (s *SkipList) Min() interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if first := s.head.levels[0].forward; first != nil {
+		return first.payload
+	}
+
+	return nil
+}
+
+// Max returns the payload of the highest key, or nil.
+func (s *SkipList) Max() interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.tail != nil {
+		return s.tail.payload
+	}
+
+	return nil
+}
+
+// Search returns the payload for a given key, or nil.
+func (s *SkipList) Search(key Key) interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if n := s.search(key); n != nil {
+		return n.payload
+	}
+
+	return nil
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (s *SkipList) Successor(key Key) interface{} {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	n := s.search(key)
+	if n == nil || n.levels[0].forward == nil {
+		return nil
+	}
+
+	return n.levels[0].forward.payload
+}
+
+// Rank returns the number of keys less than the passed key, which is the
+// zero-based position of the key if it exists, and whether it exists. Runs in
+// expected O(lg n) time.
+func (s *SkipList) Rank(key Key) (int, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var (
+		x    = s.head
+		rank int
+	)
+
+	// Advance to the last node with a key less than or equal to key.
+	for i := s.level - 1; i >= 0; i-- {
+		for next := x.levels[i].forward; next != nil && !key.Less(next.key); next = x.levels[i].forward {
+			rank += x.levels[i].span
+			x = next
+		}
+	}
+
+	if x != s.head && !x.key.Less(key) {
+		return rank - 1, true
+	}
+
+	return rank, false
+}
+
+// Select returns the entry with the given zero-based rank. Returns false if
+// the rank is out of range. Runs in expected O(lg n) time.
+func (s *SkipList) Select(rank int) (Result, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if rank < 0 || rank >= s.length {
+		return Result{}, false
+	}
+
+	var (
+		x         = s.head
+		traversed int
+	)
+
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && traversed+x.levels[i].span <= rank+1 {
+			traversed += x.levels[i].span
+			x = x.levels[i].forward
+		}
+	}
+
+	return Result{Key: x.key, Payload: x.payload}, true
+}
+
+// search returns the node holding key, or nil.
+func (s *SkipList) search(key Key) *node {
+	if n := s.seek(key); n != nil && !key.Less(n.key) {
+		return n
+	}
+
+	return nil
+}
+
+// seek returns the first node with a key not less than key, or nil.
+func (s *SkipList) seek(key Key) *node {
+	x := s.head
+
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && x.levels[i].forward.key.Less(key) {
+			x = x.levels[i].forward
+		}
+	}
+
+	return x.levels[0].forward
+}
+
+func (s *SkipList) randomLevel() int {
+	lvl := 1
+
+	for lvl < s.maxLevel && s.rng.Float64() < s.p {
+		lvl++
+	}
+
+	return lvl
+}
@@ -0,0 +1,297 @@
+package skiplist
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the order of all levels, the spans of all links and the
+// backward links.
+func verify(t *testing.T, s *SkipList) {
+	// Positions of all nodes on the lowest level, starting at one.
+	pos := map[*node]int{s.head: 0}
+
+	var prev *node
+
+	for x := s.head.levels[0].forward; x != nil; x = x.levels[0].forward {
+		if prev != nil {
+			require.True(t, prev.key.Less(x.key), "keys not sorted")
+		}
+
+		require.Equal(t, prev, x.backward, "wrong backward link of %v", x.key)
+
+		pos[x] = len(pos)
+		prev = x
+	}
+
+	require.Equal(t, prev, s.tail)
+	require.Equal(t, s.length, len(pos)-1)
+
+	for i := 0; i < s.maxLevel; i++ {
+		if i >= s.level {
+			require.Nil(t, s.head.levels[i].forward, "level %d above list level", i)
+			continue
+		}
+
+		for x := s.head; x.levels[i].forward != nil; x = x.levels[i].forward {
+			next := x.levels[i].forward
+
+			require.Contains(t, pos, next)
+			require.Equal(t, pos[next]-pos[x], x.levels[i].span, "wrong span on level %d", i)
+		}
+	}
+
+	if s.level > 1 {
+		require.NotNil(t, s.head.levels[s.level-1].forward, "empty top level")
+	}
+}
+
+func newList(keys ...int) *SkipList {
+	s := NewSkipList(WithSeed(1))
+
+	for _, k := range keys {
+		s.Upsert(myInt(k), k)
+	}
+
+	return s
+}
+
+func TestSkipList_Upsert(t *testing.T) {
+	t.Run("insert on empty list", func(t *testing.T) {
+		s := newList(15)
+
+		assert.Equal(t, 1, s.Len())
+		assert.Equal(t, 15, s.Search(myInt(15)))
+		assert.Equal(t, 15, s.Min())
+		assert.Equal(t, 15, s.Max())
+		verify(t, s)
+	})
+
+	t.Run("upsert on existing key changes payload", func(t *testing.T) {
+		s := newList(1, 2, 3)
+
+		s.Upsert(myInt(2), "two")
+
+		assert.Equal(t, "two", s.Search(myInt(2)))
+		assert.Equal(t, 3, s.Len())
+		verify(t, s)
+	})
+
+	t.Run("same seed yields same levels", func(t *testing.T) {
+		a, b := newList(), newList()
+
+		for i := 0; i < 100; i++ {
+			a.Upsert(myInt(i), i)
+			b.Upsert(myInt(i), i)
+		}
+
+		assert.Equal(t, a.level, b.level)
+	})
+
+	t.Run("max level is respected", func(t *testing.T) {
+		s := NewSkipList(WithMaxLevel(2), WithProbability(0.9), WithSeed(1))
+
+		for i := 0; i < 100; i++ {
+			s.Upsert(myInt(i), i)
+		}
+
+		assert.Equal(t, 2, s.level)
+		verify(t, s)
+	})
+}
+
+func TestSkipList_Delete(t *testing.T) {
+	t.Run("delete on empty list is noop", func(t *testing.T) {
+		s := newList()
+
+		s.Delete(myInt(1))
+
+		assert.Equal(t, 0, s.Len())
+		verify(t, s)
+	})
+
+	t.Run("delete of non-existing key is noop", func(t *testing.T) {
+		s := newList(1, 2, 3)
+
+		s.Delete(myInt(4))
+
+		assert.Equal(t, 3, s.Len())
+		verify(t, s)
+	})
+
+	t.Run("deleting all keys leaves empty list", func(t *testing.T) {
+		s := newList()
+
+		for i := 0; i < 100; i++ {
+			s.Upsert(myInt(i), i)
+		}
+
+		for i := 99; i >= 0; i-- {
+			s.Delete(myInt(i))
+			verify(t, s)
+		}
+
+		assert.Equal(t, 1, s.level)
+		assert.Nil(t, s.tail)
+		assert.Nil(t, s.Min())
+		assert.Nil(t, s.Max())
+	})
+}
+
+func TestSkipList_random(t *testing.T) {
+	var (
+		s    = NewSkipList(WithSeed(7), WithProbability(0.5))
+		want = make(map[myInt]int)
+		rng  = rand.New(rand.NewSource(7))
+	)
+
+	for i := 0; i < 5000; i++ {
+		k := myInt(rng.Intn(1000))
+
+		if rng.Intn(3) == 0 {
+			s.Delete(k)
+			delete(want, k)
+		} else {
+			s.Upsert(k, i)
+			want[k] = i
+		}
+
+		if i%500 == 0 {
+			verify(t, s)
+		}
+	}
+
+	verify(t, s)
+
+	res := s.InOrder()
+	require.Len(t, res, len(want))
+
+	for i, r := range res {
+		assert.Equal(t, want[r.Key.(myInt)], r.Payload)
+		assert.Equal(t, want[r.Key.(myInt)], s.Search(r.Key))
+
+		rank, ok := s.Rank(r.Key)
+		assert.True(t, ok)
+		assert.Equal(t, i, rank)
+
+		sel, ok := s.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, r, sel)
+
+		if i+1 < len(res) {
+			assert.Equal(t, res[i+1].Payload, s.Successor(r.Key))
+		} else {
+			assert.Nil(t, s.Successor(r.Key))
+		}
+	}
+}
+
+func TestSkipList_RankSelect(t *testing.T) {
+	s := newList(10, 20, 30, 40)
+
+	tt := []struct {
+		key   int
+		rank  int
+		found bool
+	}{
+		{key: 5, rank: 0},
+		{key: 10, rank: 0, found: true},
+		{key: 15, rank: 1},
+		{key: 40, rank: 3, found: true},
+		{key: 45, rank: 4},
+	}
+
+	for _, tc := range tt {
+		rank, found := s.Rank(myInt(tc.key))
+
+		assert.Equal(t, tc.rank, rank, "rank of %d", tc.key)
+		assert.Equal(t, tc.found, found, "rank of %d", tc.key)
+	}
+
+	_, ok := s.Select(-1)
+	assert.False(t, ok)
+
+	_, ok = s.Select(4)
+	assert.False(t, ok)
+
+	r, ok := s.Select(2)
+	assert.True(t, ok)
+	assert.Equal(t, Result{Key: myInt(30), Payload: 30}, r)
+}
+
+func TestSkipList_Range(t *testing.T) {
+	s := newList()
+
+	for i := 0; i < 50; i += 2 {
+		s.Upsert(myInt(i), i)
+	}
+
+	collect := func(reverse bool, from, to Key, limit int) []int {
+		var res []int
+
+		fn := func(key Key, payload interface{}) bool {
+			res = append(res, payload.(int))
+			return len(res) < limit
+		}
+
+		if reverse {
+			s.RangeReverse(from, to, fn)
+		} else {
+			s.Range(from, to, fn)
+		}
+
+		return res
+	}
+
+	tt := []struct {
+		name    string
+		from    Key
+		to      Key
+		limit   int
+		want    []int
+		reverse []int
+	}{
+		{name: "bounded range", from: myInt(10), to: myInt(20), limit: 100, want: []int{10, 12, 14, 16, 18}, reverse: []int{18, 16, 14, 12, 10}},
+		{name: "bounds between keys", from: myInt(9), to: myInt(15), limit: 100, want: []int{10, 12, 14}, reverse: []int{14, 12, 10}},
+		{name: "open lower bound", to: myInt(5), limit: 100, want: []int{0, 2, 4}, reverse: []int{4, 2, 0}},
+		{name: "open upper bound", from: myInt(44), limit: 100, want: []int{44, 46, 48}, reverse: []int{48, 46, 44}},
+		{name: "stops early", from: myInt(10), limit: 2, want: []int{10, 12}, reverse: []int{48, 46}},
+		{name: "empty range", from: myInt(11), to: myInt(12), limit: 100},
+		{name: "range above all keys", from: myInt(60), to: myInt(70), limit: 100},
+		{name: "range below all keys", from: myInt(-10), to: myInt(0), limit: 100},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, collect(false, tc.from, tc.to, tc.limit))
+			assert.Equal(t, tc.reverse, collect(true, tc.from, tc.to, tc.limit))
+		})
+	}
+
+	t.Run("full iteration", func(t *testing.T) {
+		assert.Len(t, collect(false, nil, nil, 100), s.Len())
+		assert.Len(t, collect(true, nil, nil, 100), s.Len())
+	})
+}
+
+func TestOptions(t *testing.T) {
+	assert.Panics(t, func() { WithMaxLevel(0) })
+	assert.Panics(t, func() { WithMaxLevel(65) })
+	assert.Panics(t, func() { WithProbability(0) })
+	assert.Panics(t, func() { WithProbability(1) })
+
+	s := NewSkipList(WithMaxLevel(4), WithProbability(0.5), WithoutLocking())
+
+	assert.Equal(t, 4, s.maxLevel)
+	assert.Equal(t, 0.5, s.p)
+	assert.True(t, s.lock.Disabled())
+}
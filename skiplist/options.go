@@ -0,0 +1,49 @@
+package skiplist
+
+import "math/rand"
+
+// Option configures a SkipList on construction.
+type Option func(*SkipList)
+
+// WithMaxLevel sets the maximum number of levels of the list. The list works
+// best with at most 1/p^n keys, p being the probability. Panics if n is not
+// between 1 and 64.
+func WithMaxLevel(n int) Option {
+	if n < 1 || n > maxLevels {
+		panic("skiplist: max level must be between 1 and 64")
+	}
+
+	return func(s *SkipList) {
+		s.maxLevel = n
+	}
+}
+
+// WithProbability sets the probability of a node reaching the next level.
+// Lower probabilities use less memory at the cost of longer searches. Panics
+// if p is not between 0 and 1, exclusively.
+func WithProbability(p float64) Option {
+	if p <= 0 || p >= 1 {
+		panic("skiplist: probability must be between 0 and 1")
+	}
+
+	return func(s *SkipList) {
+		s.p = p
+	}
+}
+
+// WithSeed seeds the random number generator the levels of new nodes are
+// drawn from. Lists created with the same seed and modified by the same
+// sequence of operations have the same shape, which makes tests
+// deterministic. By default the current time is used as seed.
+func WithSeed(seed int64) Option {
+	return func(s *SkipList) {
+		s.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithoutLocking disables the internal lock of the list.
+func WithoutLocking() Option {
+	return func(s *SkipList) {
+		s.lock.Disable()
+	}
+}
@@ -0,0 +1,72 @@
+package skiplist
+
+// InOrder returns an ordered list of all entries.
+func (s *SkipList) InOrder() []Result {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.length == 0 {
+		return nil
+	}
+
+	res := make([]Result, 0, s.length)
+
+	for x := s.head.levels[0].forward; x != nil; x = x.levels[0].forward {
+		res = append(res, Result{
+			Key:     x.key,
+			Payload: x.payload,
+		})
+	}
+
+	return res
+}
+
+// Range calls fn in ascending order for every key in the half-open range
+// [from, to). A nil bound leaves that side of the range open, so passing nil
+// for both iterates over the whole list. Iteration stops early if fn returns
+// false. The list must not be modified from within fn.
+func (s *SkipList) Range(from, to Key, fn func(key Key, payload interface{}) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	x := s.head.levels[0].forward
+
+	if from != nil {
+		x = s.seek(from)
+	}
+
+	for ; x != nil; x = x.levels[0].forward {
+		if to != nil && !x.key.Less(to) {
+			return
+		}
+
+		if !fn(x.key, x.payload) {
+			return
+		}
+	}
+}
+
+// RangeReverse is like Range, but calls fn in descending order.
+func (s *SkipList) RangeReverse(from, to Key, fn func(key Key, payload interface{}) bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	x := s.tail
+
+	// Start at the last node before the first one not less than to.
+	if to != nil {
+		if next := s.seek(to); next != nil {
+			x = next.backward
+		}
+	}
+
+	for ; x != nil; x = x.backward {
+		if from != nil && x.key.Less(from) {
+			return
+		}
+
+		if !fn(x.key, x.payload) {
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package skiplist
+
+import (
+	"math/rand"
+
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the list have to implement. It is shared with
+// package redblack, so the same key types work with both.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the list.
+type Result = redblack.Result
+
+const (
+	// DefaultMaxLevel is the maximum number of levels unless WithMaxLevel is
+	// passed. It's enough for 4^32 keys at the default probability.
+	DefaultMaxLevel = 32
+
+	// DefaultProbability is the probability of a node reaching the next
+	// level unless WithProbability is passed.
+	DefaultProbability = 0.25
+
+	// maxLevels is the upper limit for WithMaxLevel.
+	maxLevels = 64
+)
+
+// SkipList represents a skip list with a head node and a lock to protect
+// concurrent access.
+type SkipList struct {
+	lock     lock.RWMutex
+	head     *node
+	tail     *node
+	level    int
+	length   int
+	maxLevel int
+	p        float64
+	rng      *rand.Rand
+}
+
+// node is part of the lowest len(levels) linked lists. backward points to the
+// previous node on the lowest level, or nil for the first node.
+type node struct {
+	key      Key
+	payload  interface{}
+	backward *node
+	levels   []level
+}
+
+// level links a node to the next node on the same level. span is the number
+// of nodes on the lowest level the link skips, plus one. It's only meaningful
+// if forward isn't nil.
+type level struct {
+	forward *node
+	span    int
+}
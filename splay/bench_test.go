@@ -0,0 +1,113 @@
+package splay
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/obitech/go-trees/redblack"
+)
+
+type rbKey int64
+
+func (k rbKey) Less(v redblack.Key) bool {
+	return k < v.(rbKey)
+}
+
+const (
+	benchKeys     = 100_000
+	benchAccesses = 1 << 20
+)
+
+var result interface{}
+
+// accesses returns a sequence of keys in [0, benchKeys) with the given skew.
+// Ranks drawn from a Zipf distribution are mapped to random keys, so hot keys
+// are spread across the key space. A skew of zero yields uniform accesses.
+func accesses(skew float64) []int64 {
+	var (
+		rng  = rand.New(rand.NewSource(1))
+		perm = rng.Perm(benchKeys)
+		res  = make([]int64, benchAccesses)
+		zipf *rand.Zipf
+	)
+
+	if skew > 0 {
+		zipf = rand.NewZipf(rng, skew, 1, benchKeys-1)
+	}
+
+	for i := range res {
+		if zipf == nil {
+			res[i] = int64(rng.Intn(benchKeys))
+		} else {
+			res[i] = int64(perm[zipf.Uint64()])
+		}
+	}
+
+	return res
+}
+
+// BenchmarkSearch compares lookups with increasingly skewed access patterns
+// against a red-black tree. The cost of restructuring is amortized across all
+// accesses of a run.
+func BenchmarkSearch(b *testing.B) {
+	for _, skew := range []float64{0, 1.1, 1.5, 2} {
+		var (
+			keys    = accesses(skew)
+			splayed = NewSplayTree(WithoutLocking())
+			rbt     = redblack.NewRedBlackTree(redblack.WithoutLocking())
+		)
+
+		for _, k := range rand.New(rand.NewSource(2)).Perm(benchKeys) {
+			splayed.Upsert(int64(k), k)
+			rbt.Upsert(rbKey(k), k)
+		}
+
+		b.Run(fmt.Sprintf("splay/skew=%.1f", skew), func(b *testing.B) {
+			var r interface{}
+
+			for n := 0; n < b.N; n++ {
+				r = splayed.Search(keys[n%benchAccesses])
+			}
+
+			result = r
+		})
+
+		b.Run(fmt.Sprintf("redblack/skew=%.1f", skew), func(b *testing.B) {
+			var r interface{}
+
+			for n := 0; n < b.N; n++ {
+				r = rbt.Search(rbKey(keys[n%benchAccesses]))
+			}
+
+			result = r
+		})
+	}
+}
+
+// BenchmarkUpsert compares updates of existing keys with a skewed access
+// pattern against a red-black tree.
+func BenchmarkUpsert(b *testing.B) {
+	var (
+		keys    = accesses(1.1)
+		splayed = NewSplayTree(WithoutLocking())
+		rbt     = redblack.NewRedBlackTree(redblack.WithoutLocking())
+	)
+
+	for k := 0; k < benchKeys; k++ {
+		splayed.Upsert(int64(k), k)
+		rbt.Upsert(rbKey(k), k)
+	}
+
+	b.Run("splay", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			splayed.Upsert(keys[n%benchAccesses], n)
+		}
+	})
+
+	b.Run("redblack", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			rbt.Upsert(rbKey(keys[n%benchAccesses]), n)
+		}
+	})
+}
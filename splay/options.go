@@ -0,0 +1,11 @@
+package splay
+
+// Option configures a SplayTree on construction.
+type Option func(*SplayTree)
+
+// WithoutLocking disables the internal lock of the tree.
+func WithoutLocking() Option {
+	return func(t *SplayTree) {
+		t.lock.Disable()
+	}
+}
@@ -0,0 +1,234 @@
+// Package splay implements a splay tree with arbitrary payloads. Every access
+// moves the accessed key to the root, so frequently used keys stay close to
+// it. All operations run in amortized O(lg n) time, and sequences of accesses
+// following a skewed distribution run considerably faster.
+package splay
+
+import (
+	"math"
+)
+
+// NewSplayTree returns an empty splay tree. Unless WithoutLocking is passed,
+// all operations on the tree are safe to be accessed concurrently. As lookups
+// modify the tree as well, all operations are serialized.
+func NewSplayTree(opts ...Option) *SplayTree {
+	t := &SplayTree{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Root returns the payload of the root node of the tree, which holds the most
+// recently accessed key.
+func (t *SplayTree) Root() interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a node (the root) has a height of
+// zero.
+func (t *SplayTree) Height() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return int(height(t.root))
+}
+
+// Upsert inserts or updates an item and moves it to the root.
+func (t *SplayTree) Upsert(key int64, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = splay(t.root, key)
+
+	if t.root != nil && t.root.key == key {
+		t.root.payload = payload
+		return
+	}
+
+	n := &node{
+		key:     key,
+		payload: payload,
+	}
+
+	// The root holds the closest key, so the new node can take its place
+	// with the root on one side and the root's subtree on the other.
+	switch {
+	case t.root == nil:
+	case key < t.root.key:
+		n.left, n.right = t.root.left, t.root
+		t.root.left = nil
+	default:
+		n.left, n.right = t.root, t.root.right
+		t.root.right = nil
+	}
+
+	t.root = n
+}
+
+// Search searches for a node based on its key and returns the payload. The
+// key, or its closest neighbour if it doesn't exist, is moved to the root.
+func (t *SplayTree) Search(key int64) interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = splay(t.root, key)
+
+	if t.root == nil || t.root.key != key {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Min returns the payload of the Node with the lowest key, or nil. The lowest
+// key is moved to the root.
+func (t *SplayTree) Min() interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = splay(t.root, math.MinInt64)
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Max returns the payload of the Node with the highest key, or nil. The
+// highest key is moved to the root.
+func (t *SplayTree) Max() interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = splay(t.root, math.MaxInt64)
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Successor returns the next highest neighbour (key-wise) of the Node with the
+// passed key. The passed key is moved to the root.
+func (t *SplayTree) Successor(key int64) interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = splay(t.root, key)
+
+	if t.root == nil || t.root.key != key || t.root.right == nil {
+		return nil
+	}
+
+	n := t.root.right
+
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.payload
+}
+
+// Delete deletes a node with a given key.
+func (t *SplayTree) Delete(key int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = splay(t.root, key)
+
+	if t.root == nil || t.root.key != key {
+		return
+	}
+
+	if t.root.left == nil {
+		t.root = t.root.right
+		return
+	}
+
+	// Splaying the left subtree for key brings its highest key to the top,
+	// which leaves no right child to take the right subtree.
+	left := splay(t.root.left, key)
+	left.right = t.root.right
+
+	t.root = left
+}
+
+// splay performs a top-down splay of the subtree rooted at n and returns the
+// new root, which holds key if it exists, otherwise the last node visited
+// searching for it.
+func splay(n *node, key int64) *node {
+	if n == nil {
+		return nil
+	}
+
+	var (
+		// header.right and header.left collect the trees of nodes less and
+		// greater than key, with l and r pointing to their innermost nodes.
+		header node
+		l, r   = &header, &header
+	)
+
+	for n.key != key {
+		if key < n.key {
+			if n.left == nil {
+				break
+			}
+
+			// Zig-zig: rotate right.
+			if key < n.left.key {
+				y := n.left
+				n.left, y.right = y.right, n
+				n = y
+
+				if n.left == nil {
+					break
+				}
+			}
+
+			r.left, r, n = n, n, n.left
+		} else {
+			if n.right == nil {
+				break
+			}
+
+			// Zig-zig: rotate left.
+			if key > n.right.key {
+				y := n.right
+				n.right, y.left = y.left, n
+				n = y
+
+				if n.right == nil {
+					break
+				}
+			}
+
+			l.right, l, n = n, n, n.right
+		}
+	}
+
+	l.right, r.left = n.left, n.right
+	n.left, n.right = header.right, header.left
+
+	return n
+}
+
+func height(node *node) float64 {
+	if node == nil {
+		return -1
+	}
+
+	return 1 + math.Max(height(node.left), height(node.right))
+}
@@ -0,0 +1,216 @@
+package splay
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTree(keys ...int64) *SplayTree {
+	tree := NewSplayTree()
+
+	for _, k := range keys {
+		tree.Upsert(k, k)
+	}
+
+	return tree
+}
+
+// keys returns the keys of the tree in order, checking the search tree
+// property on the way.
+func keys(t *testing.T, tree *SplayTree) []int64 {
+	var (
+		res  []int64
+		walk func(n *node)
+	)
+
+	walk = func(n *node) {
+		if n == nil {
+			return
+		}
+
+		walk(n.left)
+
+		if len(res) > 0 {
+			require.Less(t, res[len(res)-1], n.key)
+		}
+
+		res = append(res, n.key)
+
+		walk(n.right)
+	}
+
+	walk(tree.root)
+
+	return res
+}
+
+func TestSplayTree_Upsert(t *testing.T) {
+	t.Run("insert on empty tree creates root node", func(t *testing.T) {
+		tree := newTree(15)
+
+		assert.Equal(t, 0, tree.Height())
+		assert.Equal(t, int64(15), tree.Root())
+	})
+
+	t.Run("inserted key becomes root", func(t *testing.T) {
+		tree := newTree(10, 5, 15)
+
+		assert.Equal(t, int64(15), tree.Root())
+
+		tree.Upsert(7, "seven")
+
+		assert.Equal(t, "seven", tree.Root())
+		assert.Equal(t, []int64{5, 7, 10, 15}, keys(t, tree))
+	})
+
+	t.Run("upsert on existing key changes payload", func(t *testing.T) {
+		tree := newTree(10, 5, 15)
+
+		tree.Upsert(5, "five")
+
+		assert.Equal(t, "five", tree.Root())
+		assert.Equal(t, []int64{5, 10, 15}, keys(t, tree))
+	})
+}
+
+func TestSplayTree_Search(t *testing.T) {
+	t.Run("search on empty tree returns nil", func(t *testing.T) {
+		assert.Nil(t, newTree().Search(1))
+	})
+
+	t.Run("hit moves key to root", func(t *testing.T) {
+		tree := newTree(1, 2, 3, 4, 5, 6, 7)
+
+		// Ascending inserts degenerate into a left spine.
+		assert.Equal(t, 6, tree.Height())
+
+		assert.Equal(t, int64(1), tree.Search(1))
+		assert.Equal(t, int64(1), tree.Root())
+
+		// Splaying the deepest node roughly halves the depth of the path.
+		assert.Equal(t, 4, tree.Height())
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7}, keys(t, tree))
+	})
+
+	t.Run("miss moves neighbour to root", func(t *testing.T) {
+		tree := newTree(10, 20, 30)
+
+		assert.Nil(t, tree.Search(25))
+		assert.Contains(t, []interface{}{int64(20), int64(30)}, tree.Root())
+		assert.Equal(t, []int64{10, 20, 30}, keys(t, tree))
+	})
+}
+
+func TestSplayTree_MinMax(t *testing.T) {
+	tree := newTree()
+
+	assert.Nil(t, tree.Min())
+	assert.Nil(t, tree.Max())
+
+	tree = newTree(15, 6, 18, 3, 7, 17, 20)
+
+	assert.Equal(t, int64(3), tree.Min())
+	assert.Equal(t, int64(3), tree.Root())
+	assert.Equal(t, int64(20), tree.Max())
+	assert.Equal(t, int64(20), tree.Root())
+}
+
+func TestSplayTree_Successor(t *testing.T) {
+	tree := newTree(15, 6, 18, 3, 7, 17, 20, 2, 4, 13, 9)
+
+	tt := []struct {
+		key  int64
+		want interface{}
+	}{
+		{key: 2, want: int64(3)},
+		{key: 4, want: int64(6)},
+		{key: 7, want: int64(9)},
+		{key: 13, want: int64(15)},
+		{key: 15, want: int64(17)},
+		{key: 20},
+		{key: 5},
+	}
+
+	for _, tc := range tt {
+		assert.Equal(t, tc.want, tree.Successor(tc.key), "successor of %d", tc.key)
+	}
+}
+
+func TestSplayTree_Delete(t *testing.T) {
+	t.Run("delete on empty tree is noop", func(t *testing.T) {
+		tree := newTree()
+
+		tree.Delete(1)
+
+		assert.Equal(t, -1, tree.Height())
+	})
+
+	t.Run("delete of non-existing key is noop", func(t *testing.T) {
+		tree := newTree(1, 2, 3)
+
+		tree.Delete(4)
+
+		assert.Equal(t, []int64{1, 2, 3}, keys(t, tree))
+	})
+
+	t.Run("delete keeps order", func(t *testing.T) {
+		tree := newTree(15, 6, 18, 3, 7, 17, 20)
+
+		tree.Delete(15)
+		assert.Equal(t, []int64{3, 6, 7, 17, 18, 20}, keys(t, tree))
+
+		tree.Delete(3)
+		assert.Equal(t, []int64{6, 7, 17, 18, 20}, keys(t, tree))
+
+		for _, k := range []int64{6, 7, 17, 18, 20} {
+			tree.Delete(k)
+		}
+
+		assert.Nil(t, tree.root)
+	})
+}
+
+func TestSplayTree_random(t *testing.T) {
+	var (
+		tree = NewSplayTree()
+		want = make(map[int64]int)
+		rng  = rand.New(rand.NewSource(1))
+	)
+
+	for i := 0; i < 5000; i++ {
+		k := rng.Int63n(1000)
+
+		switch rng.Intn(4) {
+		case 0:
+			tree.Delete(k)
+			delete(want, k)
+		case 1:
+			if v, ok := want[k]; ok {
+				assert.Equal(t, v, tree.Search(k))
+			} else {
+				assert.Nil(t, tree.Search(k))
+			}
+		default:
+			tree.Upsert(k, i)
+			want[k] = i
+		}
+	}
+
+	assert.Len(t, keys(t, tree), len(want))
+
+	for k, v := range want {
+		assert.Equal(t, v, tree.Search(k))
+	}
+}
+
+func TestNewSplayTree_WithoutLocking(t *testing.T) {
+	tree := NewSplayTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+
+	tree.Upsert(1, "one")
+	assert.Equal(t, "one", tree.Search(1))
+}
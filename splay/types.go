@@ -0,0 +1,17 @@
+package splay
+
+import "github.com/obitech/go-trees/internal/lock"
+
+// SplayTree represents a splay tree with a root node and a lock to protect
+// concurrent access.
+type SplayTree struct {
+	lock lock.RWMutex
+	root *node
+}
+
+type node struct {
+	key     int64
+	left    *node
+	right   *node
+	payload interface{}
+}
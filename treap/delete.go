@@ -0,0 +1,30 @@
+package treap
+
+// Delete deletes the given key.
+func (t *Tree) Delete(key Key) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.root = remove(t.root, key)
+}
+
+// remove deletes key from the subtree rooted at n by merging the children of
+// its node, and returns the new root of the subtree.
+func remove(n *node, key Key) *node {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case key.Less(n.key):
+		n.left = remove(n.left, key)
+	case n.key.Less(key):
+		n.right = remove(n.right, key)
+	default:
+		return merge(n.left, n.right)
+	}
+
+	n.update()
+
+	return n
+}
@@ -0,0 +1,22 @@
+package treap
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+func (t *Tree) Upsert(key Key, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if n := search(t.root, key); n != nil {
+		n.payload = payload
+		return
+	}
+
+	n := &node{
+		key:      key,
+		payload:  payload,
+		priority: t.rng.Uint64(),
+		size:     1,
+	}
+
+	l, r := split(t.root, key)
+	t.root = merge(merge(l, n), r)
+}
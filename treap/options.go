@@ -0,0 +1,24 @@
+package treap
+
+import "math/rand"
+
+// Option configures a Tree on construction.
+type Option func(*Tree)
+
+// WithSeed seeds the random number generator the priorities of the nodes are
+// drawn from. Trees created with the same seed and modified by the same
+// sequence of operations have the same shape, which makes tests
+// deterministic. By default the current time is used as seed.
+func WithSeed(seed int64) Option {
+	return func(t *Tree) {
+		t.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithoutLocking disables the internal lock of the tree.
+// Trees returned by Split and Merge inherit this option.
+func WithoutLocking() Option {
+	return func(t *Tree) {
+		t.lock.Disable()
+	}
+}
@@ -0,0 +1,85 @@
+package treap
+
+// Split moves all keys less than key into left and all other keys into right,
+// leaving t empty. Both trees inherit the locking behaviour of t. Runs in
+// expected O(lg n) time.
+func (t *Tree) Split(key Key) (left, right *Tree) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	left, right = t.derive(), t.derive()
+	left.root, right.root = split(t.root, key)
+	t.root = nil
+
+	return left, right
+}
+
+// Merge returns a tree holding the keys of left and right, leaving both of them
+// empty. All keys of left have to be less than all keys of right, otherwise
+// ErrOverlap is returned and neither tree is modified. The result inherits the
+// locking behaviour of left. Runs in expected O(lg n) time.
+//
+// Merge locks left before right, so concurrently merging the same trees in
+// opposite order may deadlock.
+func Merge(left, right *Tree) (*Tree, error) {
+	if left == right {
+		return nil, ErrOverlap
+	}
+
+	left.lock.Lock()
+	defer left.lock.Unlock()
+
+	right.lock.Lock()
+	defer right.lock.Unlock()
+
+	if left.root != nil && right.root != nil && !max(left.root).key.Less(min(right.root).key) {
+		return nil, ErrOverlap
+	}
+
+	t := left.derive()
+	t.root = merge(left.root, right.root)
+	left.root, right.root = nil, nil
+
+	return t, nil
+}
+
+// split splits the subtree rooted at n into one subtree with all keys less
+// than key and one with the remaining keys.
+func split(n *node, key Key) (l, r *node) {
+	if n == nil {
+		return nil, nil
+	}
+
+	if n.key.Less(key) {
+		n.right, r = split(n.right, key)
+		n.update()
+
+		return n, r
+	}
+
+	l, n.left = split(n.left, key)
+	n.update()
+
+	return l, n
+}
+
+// merge joins the subtrees rooted at l and r, where all keys of l are less
+// than all keys of r, and returns the new root.
+func merge(l, r *node) *node {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.priority > r.priority:
+		l.right = merge(l.right, r)
+		l.update()
+
+		return l
+	default:
+		r.left = merge(l, r.left)
+		r.update()
+
+		return r
+	}
+}
@@ -0,0 +1,190 @@
+// Package treap implements a treap, a binary search tree which is kept
+// balanced with high probability by assigning random heap priorities to its
+// nodes. All operations run in expected O(lg n) time, including splitting a
+// tree at a key and merging two trees with disjoint key ranges.
+package treap
+
+import (
+	"math/rand"
+	"time"
+)
+
+// NewTreap returns a new treap. Unless WithoutLocking is passed, all
+// operations on the tree are safe to be accessed concurrently.
+func NewTreap(opts ...Option) *Tree {
+	t := &Tree{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if t.rng == nil {
+		t.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return size(t.root)
+}
+
+// Root returns the payload of the root node of the tree.
+func (t *Tree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a single node has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return height(t.root)
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *Tree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return min(t.root).payload
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *Tree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return max(t.root).payload
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *Tree) Search(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if n := search(t.root, key); n != nil {
+		return n.payload
+	}
+
+	return nil
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (t *Tree) Successor(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	// The lowest ancestor whose left subtree holds key.
+	var candidate *node
+
+	for n := t.root; n != nil; {
+		switch {
+		case key.Less(n.key):
+			candidate = n
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		case n.right != nil:
+			return min(n.right).payload
+		case candidate != nil:
+			return candidate.payload
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// derive returns an empty tree with the same locking behaviour as t. Its
+// random number generator is seeded from the one of t, so derived trees stay
+// deterministic if t was created WithSeed.
+func (t *Tree) derive() *Tree {
+	opts := []Option{WithSeed(t.rng.Int63())}
+
+	if t.lock.Disabled() {
+		opts = append(opts, WithoutLocking())
+	}
+
+	return NewTreap(opts...)
+}
+
+func search(n *node, key Key) *node {
+	for n != nil {
+		switch {
+		case key.Less(n.key):
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+func min(n *node) *node {
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+func max(n *node) *node {
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+
+	l, r := height(n.left), height(n.right)
+
+	if l > r {
+		return l + 1
+	}
+
+	return r + 1
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// update recomputes the size of n from its children.
+func (n *node) update() {
+	n.size = 1 + size(n.left) + size(n.right)
+}
@@ -0,0 +1,359 @@
+package treap
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the search tree and heap properties as well as the subtree
+// sizes of all nodes.
+func verify(t *testing.T, tree *Tree) {
+	var walk func(n *node, lo, hi Key) int
+
+	walk = func(n *node, lo, hi Key) int {
+		if n == nil {
+			return 0
+		}
+
+		if lo != nil {
+			require.True(t, lo.Less(n.key), "%v not greater than %v", n.key, lo)
+		}
+
+		if hi != nil {
+			require.True(t, n.key.Less(hi), "%v not less than %v", n.key, hi)
+		}
+
+		for _, c := range []*node{n.left, n.right} {
+			if c != nil {
+				require.LessOrEqual(t, c.priority, n.priority, "heap property violated")
+			}
+		}
+
+		s := 1 + walk(n.left, lo, n.key) + walk(n.right, n.key, hi)
+		require.Equal(t, s, n.size, "wrong size of %v", n.key)
+
+		return s
+	}
+
+	walk(tree.root, nil, nil)
+}
+
+func newTree(keys ...int) *Tree {
+	tree := NewTreap(WithSeed(1))
+
+	for _, k := range keys {
+		tree.Upsert(myInt(k), k)
+	}
+
+	return tree
+}
+
+func keys(tree *Tree) []int {
+	var res []int
+
+	for _, r := range tree.InOrder() {
+		res = append(res, int(r.Key.(myInt)))
+	}
+
+	return res
+}
+
+// priorities is a rand.Source64 which yields the given priorities in order.
+type priorities []uint64
+
+func (p *priorities) Uint64() uint64 {
+	v := (*p)[0]
+	*p = (*p)[1:]
+
+	return v
+}
+
+func (p *priorities) Int63() int64 { return int64(p.Uint64() >> 1) }
+func (p *priorities) Seed(int64)   {}
+
+// newTreeWith returns a tree holding keys, which get the priorities at the
+// same index.
+func newTreeWith(keys []int, prios ...uint64) *Tree {
+	var (
+		tree = NewTreap()
+		src  = priorities(prios)
+	)
+
+	tree.rng = rand.New(&src)
+
+	for _, k := range keys {
+		tree.Upsert(myInt(k), k)
+	}
+
+	return tree
+}
+
+// shape renders the subtree rooted at n, e.g. "2(1 3)" for a root 2 with the
+// children 1 and 3, and "-" for a missing child.
+func shape(n *node) string {
+	switch {
+	case n == nil:
+		return "-"
+	case n.left == nil && n.right == nil:
+		return fmt.Sprint(n.key)
+	}
+
+	return fmt.Sprintf("%v(%s %s)", n.key, shape(n.left), shape(n.right))
+}
+
+func TestTree_Upsert(t *testing.T) {
+	tt := []struct {
+		name  string
+		keys  []int
+		prios []uint64
+		want  string
+	}{
+		{
+			name:  "highest priority becomes root",
+			keys:  []int{1, 2, 3},
+			prios: []uint64{10, 30, 20},
+			want:  "2(1 3)",
+		},
+		{
+			name:  "ascending priorities rotate every key up",
+			keys:  []int{1, 2, 3},
+			prios: []uint64{10, 20, 30},
+			want:  "3(2(1 -) -)",
+		},
+		{
+			name:  "same keys and priorities in another order yield the same shape",
+			keys:  []int{3, 1, 2},
+			prios: []uint64{20, 10, 30},
+			want:  "2(1 3)",
+		},
+		{
+			name:  "new key with lowest priority becomes leaf",
+			keys:  []int{4, 2, 6, 5},
+			prios: []uint64{40, 30, 20, 10},
+			want:  "4(2 6(5 -))",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTreeWith(tc.keys, tc.prios...)
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			verify(t, tree)
+		})
+	}
+
+	t.Run("same seed yields same shape", func(t *testing.T) {
+		a, b := NewTreap(WithSeed(42)), NewTreap(WithSeed(42))
+
+		for i := 0; i < 100; i++ {
+			a.Upsert(myInt(i), i)
+			b.Upsert(myInt(i), i)
+		}
+
+		assert.Equal(t, shape(a.root), shape(b.root))
+	})
+
+	t.Run("ascending keys stay balanced", func(t *testing.T) {
+		tree := NewTreap(WithSeed(1))
+
+		for i := 0; i < 1<<12; i++ {
+			tree.Upsert(myInt(i), i)
+		}
+
+		verify(t, tree)
+		assert.Less(t, float64(tree.Height()), 4*math.Log2(1<<12))
+	})
+}
+
+func TestTree_Delete(t *testing.T) {
+	tt := []struct {
+		name  string
+		keys  []int
+		prios []uint64
+		key   int
+		want  string
+	}{
+		{
+			name:  "child with higher priority replaces root",
+			keys:  []int{4, 2, 6},
+			prios: []uint64{30, 10, 20},
+			key:   4,
+			want:  "6(2 -)",
+		},
+		{
+			name:  "children are merged along their inner spines",
+			keys:  []int{4, 2, 6, 3, 5},
+			prios: []uint64{50, 40, 30, 20, 10},
+			key:   4,
+			want:  "2(- 6(3(- 5) -))",
+		},
+		{
+			name:  "inner node is replaced by its only child",
+			keys:  []int{4, 2, 1},
+			prios: []uint64{30, 20, 10},
+			key:   2,
+			want:  "4(1 -)",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTreeWith(tc.keys, tc.prios...)
+
+			tree.Delete(myInt(tc.key))
+
+			assert.Equal(t, tc.want, shape(tree.root))
+			assert.Equal(t, len(tc.keys)-1, tree.Len())
+			verify(t, tree)
+		})
+	}
+}
+
+func TestTree_random(t *testing.T) {
+	var (
+		tree = NewTreap(WithSeed(7))
+		want = make(map[myInt]bool)
+		rng  = rand.New(rand.NewSource(7))
+	)
+
+	for i := 0; i < 5000; i++ {
+		k := myInt(rng.Intn(1000))
+
+		if rng.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(want, k)
+		} else {
+			tree.Upsert(k, i)
+			want[k] = true
+		}
+
+		if i%250 == 0 {
+			verify(t, tree)
+		}
+	}
+
+	verify(t, tree)
+	require.Equal(t, len(want), tree.Len())
+}
+
+func TestTree_Split(t *testing.T) {
+	tt := []struct {
+		name  string
+		key   int
+		left  []int
+		right []int
+	}{
+		{name: "split at existing key", key: 4, left: []int{1, 2}, right: []int{4, 5, 6}},
+		{name: "split between keys", key: 3, left: []int{1, 2}, right: []int{4, 5, 6}},
+		{name: "split below lowest key", key: 0, right: []int{1, 2, 4, 5, 6}},
+		{name: "split above highest key", key: 7, left: []int{1, 2, 4, 5, 6}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newTree(1, 2, 4, 5, 6)
+
+			l, r := tree.Split(myInt(tc.key))
+
+			assert.Equal(t, tc.left, keys(l))
+			assert.Equal(t, tc.right, keys(r))
+			assert.Equal(t, len(tc.left), l.Len())
+			assert.Equal(t, len(tc.right), r.Len())
+			assert.Equal(t, 0, tree.Len())
+
+			verify(t, l)
+			verify(t, r)
+		})
+	}
+
+	t.Run("split trees can be modified independently", func(t *testing.T) {
+		l, r := newTree(1, 2, 3, 4).Split(myInt(3))
+
+		l.Upsert(myInt(10), 10)
+		r.Delete(myInt(3))
+
+		assert.Equal(t, []int{1, 2, 10}, keys(l))
+		assert.Equal(t, []int{4}, keys(r))
+	})
+
+	t.Run("split trees inherit locking", func(t *testing.T) {
+		l, _ := NewTreap(WithoutLocking()).Split(myInt(1))
+		assert.True(t, l.lock.Disabled())
+
+		l, _ = NewTreap().Split(myInt(1))
+		assert.False(t, l.lock.Disabled())
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("merging disjoint trees", func(t *testing.T) {
+		l, r := newTree(1, 2, 3), newTree(4, 5, 6)
+
+		m, err := Merge(l, r)
+		require.NoError(t, err)
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, keys(m))
+		assert.Equal(t, 6, m.Len())
+		assert.Equal(t, 0, l.Len())
+		assert.Equal(t, 0, r.Len())
+		verify(t, m)
+	})
+
+	t.Run("merging with empty trees", func(t *testing.T) {
+		m, err := Merge(newTree(), newTree(1, 2))
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, keys(m))
+
+		m, err = Merge(newTree(1, 2), newTree())
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, keys(m))
+	})
+
+	t.Run("overlapping trees are rejected", func(t *testing.T) {
+		l, r := newTree(1, 5), newTree(3, 7)
+
+		_, err := Merge(l, r)
+		assert.Equal(t, ErrOverlap, err)
+		assert.Equal(t, []int{1, 5}, keys(l))
+		assert.Equal(t, []int{3, 7}, keys(r))
+
+		_, err = Merge(newTree(1, 3), newTree(3, 4))
+		assert.Equal(t, ErrOverlap, err)
+
+		_, err = Merge(l, l)
+		assert.Equal(t, ErrOverlap, err)
+	})
+
+	t.Run("split and merge round trip", func(t *testing.T) {
+		tree := NewTreap(WithSeed(3))
+
+		for _, k := range rand.New(rand.NewSource(3)).Perm(1000) {
+			tree.Upsert(myInt(k), k)
+		}
+
+		for _, k := range []int{0, 1, 500, 999, 1000} {
+			l, r := tree.Split(myInt(k))
+
+			var err error
+			tree, err = Merge(l, r)
+			require.NoError(t, err)
+
+			verify(t, tree)
+			assert.Equal(t, 1000, tree.Len())
+		}
+
+		assert.Less(t, float64(tree.Height()), 4*math.Log2(1000))
+	})
+}
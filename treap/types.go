@@ -0,0 +1,39 @@
+package treap
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the tree have to implement. It is shared with
+// package redblack, so the same key types work with both trees.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the tree.
+type Result = redblack.Result
+
+// ErrOverlap is returned by Merge if the keys of the left tree aren't all less
+// than the keys of the right tree.
+var ErrOverlap = errors.New("treap: trees overlap")
+
+// Tree represents a treap with a root node and a lock to protect concurrent
+// access. Nodes get random priorities drawn from rng on insertion.
+type Tree struct {
+	lock lock.RWMutex
+	root *node
+	rng  *rand.Rand
+}
+
+// node is ordered by key like in a binary search tree, and by priority like in
+// a max-heap. size is the number of nodes of the subtree rooted at the node.
+type node struct {
+	key      Key
+	payload  interface{}
+	priority uint64
+	size     int
+	left     *node
+	right    *node
+}
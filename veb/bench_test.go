@@ -0,0 +1,131 @@
+package veb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/obitech/go-trees/bst"
+	"github.com/obitech/go-trees/redblack"
+)
+
+const universe = 1 << 20
+
+type rbKey int64
+
+func (k rbKey) Less(v redblack.Key) bool {
+	return k < v.(rbKey)
+}
+
+var result interface{}
+
+// keys returns n random keys of the universe, drawn from a fixed seed so all
+// trees hold the same keys.
+func keys(n int) []int64 {
+	rng := rand.New(rand.NewSource(1))
+	res := make([]int64, n)
+
+	for i := range res {
+		res[i] = rng.Int63n(universe)
+	}
+
+	return res
+}
+
+func BenchmarkSuccessor(b *testing.B) {
+	var (
+		ks = keys(100_000)
+		v  = NewVEBTree(universe)
+		t  = bst.NewBSTree()
+	)
+
+	for _, k := range ks {
+		v.Upsert(k, k)
+		t.Upsert(k, k)
+	}
+
+	b.Run("veb", func(b *testing.B) {
+		var (
+			rng = rand.New(rand.NewSource(2))
+			r   interface{}
+		)
+
+		for n := 0; n < b.N; n++ {
+			r = v.Successor(rng.Int63n(universe))
+		}
+
+		result = r
+	})
+
+	b.Run("bst", func(b *testing.B) {
+		var r interface{}
+
+		// The successor of a BSTree is only defined for existing keys.
+		for n := 0; n < b.N; n++ {
+			r = t.Successor(ks[n%len(ks)])
+		}
+
+		result = r
+	})
+}
+
+func BenchmarkUpsert(b *testing.B) {
+	b.Run("veb", func(b *testing.B) {
+		var (
+			rng = rand.New(rand.NewSource(2))
+			v   = NewVEBTree(universe)
+		)
+
+		for n := 0; n < b.N; n++ {
+			v.Upsert(rng.Int63n(universe), nil)
+		}
+	})
+
+	b.Run("redblack", func(b *testing.B) {
+		var (
+			rng = rand.New(rand.NewSource(2))
+			t   = redblack.NewRedBlackTree()
+		)
+
+		for n := 0; n < b.N; n++ {
+			t.Upsert(rbKey(rng.Int63n(universe)), nil)
+		}
+	})
+}
+
+func BenchmarkDelete(b *testing.B) {
+	ks := keys(100_000)
+
+	b.Run("veb", func(b *testing.B) {
+		var (
+			rng = rand.New(rand.NewSource(2))
+			v   = NewVEBTree(universe)
+		)
+
+		for _, k := range ks {
+			v.Upsert(k, k)
+		}
+
+		b.ResetTimer()
+
+		for n := 0; n < b.N; n++ {
+			v.Delete(rng.Int63n(universe))
+		}
+	})
+
+	b.Run("redblack", func(b *testing.B) {
+		var (
+			rng = rand.New(rand.NewSource(2))
+			t   = redblack.NewRedBlackTree()
+		)
+
+		for _, k := range ks {
+			t.Upsert(rbKey(k), k)
+		}
+
+		b.ResetTimer()
+
+		for n := 0; n < b.N; n++ {
+			t.Delete(rbKey(rng.Int63n(universe)))
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package veb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+// snapshotVersion is the version of the binary format written by WriteTo:
+//
+//	magic | version | universe (uvarint) | count (uvarint) | count × (key (varint) | payload)
+//
+// Entries are written in ascending key order, payloads are length-prefixed
+// and encoded with the tree's codec.
+const snapshotVersion = 1
+
+var snapshotMagic = [4]byte{'V', 'E', 'B', 'S'}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (t *VEBTree) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. All existing entries
+// of the tree are replaced.
+func (t *VEBTree) UnmarshalBinary(data []byte) error {
+	_, err := t.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// WriteTo implements io.WriterTo by writing a snapshot of the tree to w.
+func (t *VEBTree) WriteTo(w io.Writer) (int64, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	e := codec.NewEncoder(w)
+
+	e.WriteHeader(snapshotMagic, snapshotVersion)
+	e.WriteUvarint(uint64(t.universe))
+	e.WriteUvarint(uint64(len(t.payloads)))
+
+	t.keys(func(key int64) {
+		e.WriteVarint(key)
+		e.WriteValue(t.codec, t.payloads[key])
+	})
+
+	return e.Flush()
+}
+
+// ReadFrom implements io.ReaderFrom by reading a snapshot written by WriteTo.
+// All existing entries of the tree are replaced, and the universe of the tree
+// has to be at least as large as the one of the snapshot.
+func (t *VEBTree) ReadFrom(r io.Reader) (int64, error) {
+	d := codec.NewDecoder(r)
+
+	if v := d.ReadHeader(snapshotMagic); d.Err() == nil && v != snapshotVersion {
+		d.Fail(fmt.Errorf("veb: unsupported snapshot version %d", v))
+	}
+
+	if u := d.ReadUvarint(); d.Err() == nil && u > uint64(t.universe) {
+		d.Fail(fmt.Errorf("veb: snapshot universe %d exceeds universe %d", u, t.universe))
+	}
+
+	var (
+		count   = d.ReadUvarint()
+		keys    []int64
+		entries = make(map[int64]interface{})
+	)
+
+	for i := uint64(0); i < count && d.Err() == nil; i++ {
+		key, payload := d.ReadVarint(), d.ReadValue(t.codec)
+
+		switch {
+		case d.Err() != nil:
+		case len(keys) > 0 && keys[len(keys)-1] >= key:
+			d.Fail(errors.New("veb: snapshot keys are not in ascending order"))
+		case key < 0 || key >= t.universe:
+			d.Fail(fmt.Errorf("veb: snapshot key %d out of universe [0, %d)", key, t.universe))
+		}
+
+		keys = append(keys, key)
+		entries[key] = payload
+	}
+
+	if err := d.Err(); err != nil {
+		return d.Count(), err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.clear()
+
+	for _, key := range keys {
+		t.root.insert(uint64(key))
+	}
+
+	t.payloads = entries
+
+	return d.Count(), nil
+}
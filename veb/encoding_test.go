@@ -0,0 +1,141 @@
+package veb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+type failingCodec struct{}
+
+func (failingCodec) Marshal(interface{}) ([]byte, error) {
+	return nil, errors.New("nope")
+}
+
+func (failingCodec) Unmarshal([]byte) (interface{}, error) {
+	return nil, errors.New("nope")
+}
+
+func TestVEBTree_MarshalBinary(t *testing.T) {
+	tt := []struct {
+		name  string
+		items []int64
+	}{
+		{name: "empty tree"},
+		{name: "single key", items: []int64{15}},
+		{name: "multiple keys", items: []int64{0, 1, 2, 3, 500, 1023}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := NewVEBTree(1024)
+
+			for _, k := range tc.items {
+				tree.Upsert(k, k)
+			}
+
+			b, err := tree.MarshalBinary()
+			require.NoError(t, err)
+
+			got := NewVEBTree(1024)
+			require.NoError(t, got.UnmarshalBinary(b))
+
+			assert.Equal(t, len(tc.items), got.Len())
+
+			for _, k := range tc.items {
+				assert.Equal(t, k, got.Search(k))
+			}
+
+			for i := 1; i < len(tc.items); i++ {
+				assert.Equal(t, tc.items[i], got.Successor(tc.items[i-1]))
+			}
+		})
+	}
+}
+
+func TestVEBTree_ReadFrom(t *testing.T) {
+	t.Run("replaces existing entries", func(t *testing.T) {
+		src := NewVEBTree(10)
+		src.Upsert(1, "1")
+
+		var buf bytes.Buffer
+
+		n, err := src.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		dst := NewVEBTree(10)
+		dst.Upsert(2, "2")
+
+		read, err := dst.ReadFrom(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, n, read)
+		assert.Equal(t, "1", dst.Search(1))
+		assert.Nil(t, dst.Search(2))
+		assert.Nil(t, dst.Successor(1))
+	})
+
+	t.Run("truncated snapshot returns error", func(t *testing.T) {
+		src := NewVEBTree(10)
+		src.Upsert(1, "1")
+
+		b, err := src.MarshalBinary()
+		require.NoError(t, err)
+
+		assert.Error(t, NewVEBTree(10).UnmarshalBinary(b[:len(b)-1]))
+	})
+
+	t.Run("invalid magic number returns error", func(t *testing.T) {
+		assert.Equal(t, codec.ErrInvalidMagic, NewVEBTree(10).UnmarshalBinary([]byte("BSTS\x01\x00")))
+	})
+
+	t.Run("larger universe returns error", func(t *testing.T) {
+		b, err := NewVEBTree(100).MarshalBinary()
+		require.NoError(t, err)
+
+		assert.Error(t, NewVEBTree(10).UnmarshalBinary(b))
+		assert.NoError(t, NewVEBTree(1000).UnmarshalBinary(b))
+	})
+
+	tt := []struct {
+		name string
+		keys []int64
+	}{
+		{name: "unsorted keys return error", keys: []int64{2, 2}},
+		{name: "keys outside of universe return error", keys: []int64{-1}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			e := codec.NewEncoder(&buf)
+			e.WriteHeader(snapshotMagic, snapshotVersion)
+			e.WriteUvarint(10)
+			e.WriteUvarint(uint64(len(tc.keys)))
+
+			for _, k := range tc.keys {
+				e.WriteVarint(k)
+				e.WriteValue(codec.Gob{}, nil)
+			}
+
+			_, err := e.Flush()
+			require.NoError(t, err)
+
+			assert.Error(t, NewVEBTree(10).UnmarshalBinary(buf.Bytes()))
+		})
+	}
+
+	t.Run("custom codec is used", func(t *testing.T) {
+		tree := NewVEBTree(10, WithCodec(failingCodec{}))
+		tree.Upsert(1, "1")
+
+		_, err := tree.MarshalBinary()
+		assert.Error(t, err)
+	})
+}
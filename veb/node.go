@@ -0,0 +1,262 @@
+package veb
+
+import "math/bits"
+
+const (
+	// maxDenseBits is the maximum number of high bits for which clusters are
+	// indexed by a slice.
+	maxDenseBits = 16
+
+	// maxLeafBits is the maximum number of bits of a node which keeps its keys
+	// in a bitset instead of clusters, which saves the lowest levels of
+	// recursion and their cache misses.
+	maxLeafBits = 6
+)
+
+func newNode(bits uint) *node {
+	return &node{
+		bits:  bits,
+		empty: true,
+	}
+}
+
+func (n *node) leaf() bool {
+	return n.bits <= maxLeafBits
+}
+
+// lowBits returns the number of bits of a key which are stored in a cluster.
+func (n *node) lowBits() uint {
+	return n.bits / 2
+}
+
+func (n *node) high(x uint64) uint64 {
+	return x >> n.lowBits()
+}
+
+func (n *node) low(x uint64) uint64 {
+	return x & (1<<n.lowBits() - 1)
+}
+
+func (n *node) index(high, low uint64) uint64 {
+	return high<<n.lowBits() | low
+}
+
+// cluster returns the cluster of the given high bits, or nil if it's empty.
+func (n *node) cluster(h uint64) *node {
+	if n.sparse != nil {
+		return n.sparse[h]
+	}
+
+	if n.dense != nil {
+		return n.dense[h]
+	}
+
+	return nil
+}
+
+func (n *node) setCluster(h uint64, c *node) {
+	if n.summary == nil {
+		highBits := n.bits - n.lowBits()
+		n.summary = newNode(highBits)
+
+		if highBits > maxDenseBits {
+			n.sparse = make(map[uint64]*node)
+		} else {
+			n.dense = make([]*node, 1<<highBits)
+		}
+	}
+
+	if n.sparse != nil {
+		if c == nil {
+			delete(n.sparse, h)
+		} else {
+			n.sparse[h] = c
+		}
+
+		return
+	}
+
+	n.dense[h] = c
+}
+
+// insert adds x, which must not be part of the set yet. Only one recursive
+// call does more than constant work, so insert runs in O(lg bits) time.
+func (n *node) insert(x uint64) {
+	if n.leaf() {
+		n.set |= 1 << x
+		n.updateLeaf()
+
+		return
+	}
+
+	if n.empty {
+		n.min, n.max, n.empty = x, x, false
+		return
+	}
+
+	if x < n.min {
+		x, n.min = n.min, x
+	}
+
+	h, l := n.high(x), n.low(x)
+
+	c := n.cluster(h)
+	if c == nil {
+		c = newNode(n.lowBits())
+		n.setCluster(h, c)
+		n.summary.insert(h)
+	}
+
+	// Inserting into an empty cluster takes constant time, so at most one of
+	// the calls recurses further.
+	c.insert(l)
+
+	if x > n.max {
+		n.max = x
+	}
+}
+
+// delete removes x, which must be part of the set, in O(lg bits) time.
+func (n *node) delete(x uint64) {
+	if n.leaf() {
+		n.set &^= 1 << x
+		n.updateLeaf()
+
+		return
+	}
+
+	if n.min == n.max {
+		n.empty = true
+		return
+	}
+
+	// The minimum isn't stored in a cluster, so the next key takes its place
+	// and is removed from its cluster instead.
+	if x == n.min {
+		h := n.summary.min
+		x = n.index(h, n.cluster(h).min)
+		n.min = x
+	}
+
+	h := n.high(x)
+	c := n.cluster(h)
+
+	c.delete(n.low(x))
+
+	if c.empty {
+		n.setCluster(h, nil)
+		n.summary.delete(h)
+	}
+
+	if x == n.max {
+		switch {
+		case n.summary.empty:
+			n.max = n.min
+		case c.empty:
+			h = n.summary.max
+			n.max = n.index(h, n.cluster(h).max)
+		default:
+			n.max = n.index(h, c.max)
+		}
+	}
+}
+
+func (n *node) contains(x uint64) bool {
+	switch {
+	case n.empty:
+		return false
+	case x == n.min || x == n.max:
+		return true
+	case n.leaf():
+		return n.set&(1<<x) != 0
+	}
+
+	c := n.cluster(n.high(x))
+
+	return c != nil && c.contains(n.low(x))
+}
+
+// successor returns the smallest key greater than x in O(lg bits) time.
+func (n *node) successor(x uint64) (uint64, bool) {
+	switch {
+	case n.empty || x >= n.max:
+		return 0, false
+	case x < n.min:
+		return n.min, true
+	case n.leaf():
+		return uint64(bits.TrailingZeros64(n.set >> (x + 1) << (x + 1))), true
+	}
+
+	h, l := n.high(x), n.low(x)
+
+	if c := n.cluster(h); c != nil && l < c.max {
+		s, _ := c.successor(l)
+		return n.index(h, s), true
+	}
+
+	// x < max, so there is a key in a later cluster.
+	h, _ = n.summary.successor(h)
+
+	return n.index(h, n.cluster(h).min), true
+}
+
+// height returns the number of cluster levels below n which hold keys, or -1
+// if n is empty.
+func (n *node) height() int {
+	if n.empty {
+		return -1
+	}
+
+	if n.leaf() || n.summary == nil || n.summary.empty {
+		return 0
+	}
+
+	max := -1
+
+	for h, ok := n.summary.min, true; ok; h, ok = n.summary.successor(h) {
+		if ch := n.cluster(h).height(); ch > max {
+			max = ch
+		}
+	}
+
+	return max + 1
+}
+
+// predecessor returns the greatest key less than x in O(lg bits) time.
+func (n *node) predecessor(x uint64) (uint64, bool) {
+	switch {
+	case n.empty || x <= n.min:
+		return 0, false
+	case x > n.max:
+		return n.max, true
+	case n.leaf():
+		return uint64(63 - bits.LeadingZeros64(n.set&(1<<x-1))), true
+	}
+
+	h, l := n.high(x), n.low(x)
+
+	if c := n.cluster(h); c != nil && l > c.min {
+		p, _ := c.predecessor(l)
+		return n.index(h, p), true
+	}
+
+	if n.summary != nil {
+		if p, ok := n.summary.predecessor(h); ok {
+			return n.index(p, n.cluster(p).max), true
+		}
+	}
+
+	// The minimum isn't stored in any cluster.
+	return n.min, true
+}
+
+// updateLeaf sets the minimum and maximum of a leaf from its bitset.
+func (n *node) updateLeaf() {
+	n.empty = n.set == 0
+	if n.empty {
+		return
+	}
+
+	n.min = uint64(bits.TrailingZeros64(n.set))
+	n.max = uint64(63 - bits.LeadingZeros64(n.set))
+}
@@ -0,0 +1,199 @@
+// Package veb implements a van Emde Boas tree, which stores integer keys from
+// a bounded universe [0, u) with arbitrary payloads. Successor, Predecessor,
+// Upsert and Delete run in O(lg lg u) time, independent of the number of keys,
+// which makes it a good fit for dense universes such as port numbers or shard
+// IDs. Search runs in O(1) time.
+package veb
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/obitech/go-trees/codec"
+)
+
+// NewVEBTree returns an empty van Emde Boas tree for the keys in [0, universe).
+// Unless WithoutLocking is passed, all operations on the tree are safe to be
+// accessed concurrently. Panics if universe is less than 1.
+func NewVEBTree(universe int64, opts ...Option) *VEBTree {
+	if universe < 1 {
+		panic(fmt.Sprintf("veb: invalid universe %d", universe))
+	}
+
+	t := &VEBTree{
+		universe: universe,
+		codec:    codec.Gob{},
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	t.clear()
+
+	return t
+}
+
+// clear removes all keys from the tree.
+func (t *VEBTree) clear() {
+	b := uint(bits.Len64(uint64(t.universe - 1)))
+	if b == 0 {
+		b = 1
+	}
+
+	t.root = newNode(b)
+	t.payloads = make(map[int64]interface{})
+}
+
+// Universe returns the upper bound of the keys, which is exclusive.
+func (t *VEBTree) Universe() int64 {
+	return t.universe
+}
+
+// Len returns the number of keys in the tree.
+func (t *VEBTree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return len(t.payloads)
+}
+
+// Root returns the payload of the key stored in the root node, which is the
+// lowest key of the tree, or nil.
+func (t *VEBTree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.min()
+}
+
+// Height returns the number of cluster levels below the root which hold keys,
+// which is at most O(lg lg u). Returns -1 if the tree has no keys. A tree with
+// only a single key has a height of zero.
+func (t *VEBTree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.root.height()
+}
+
+// Upsert inserts or updates an item. Runs in O(lg lg u) time. Panics if key is
+// outside of the universe, use TryUpsert for keys which aren't known to be in
+// it.
+func (t *VEBTree) Upsert(key int64, payload interface{}) {
+	if err := t.TryUpsert(key, payload); err != nil {
+		panic(err)
+	}
+}
+
+// TryUpsert is like Upsert, but returns ErrOutOfUniverse, leaving the tree
+// unchanged, if key is outside of the universe.
+func (t *VEBTree) TryUpsert(key int64, payload interface{}) error {
+	if key < 0 || key >= t.universe {
+		return fmt.Errorf("%w: %d not in [0, %d)", ErrOutOfUniverse, key, t.universe)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.payloads[key]; !ok {
+		t.root.insert(uint64(key))
+	}
+
+	t.payloads[key] = payload
+
+	return nil
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *VEBTree) Search(key int64) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.payloads[key]
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *VEBTree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.min()
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *VEBTree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root.empty {
+		return nil
+	}
+
+	return t.payloads[int64(t.root.max)]
+}
+
+// Successor returns the payload of the lowest key greater than key, or nil.
+// Unlike in a search tree, key doesn't need to exist. Runs in O(lg lg u)
+// time.
+func (t *VEBTree) Successor(key int64) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if key < 0 {
+		return t.min()
+	}
+
+	if s, ok := t.root.successor(uint64(key)); ok {
+		return t.payloads[int64(s)]
+	}
+
+	return nil
+}
+
+// Predecessor returns the payload of the highest key less than key, or nil.
+// Key doesn't need to exist. Runs in O(lg lg u) time.
+func (t *VEBTree) Predecessor(key int64) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if key < 0 {
+		return nil
+	}
+
+	if p, ok := t.root.predecessor(uint64(key)); ok {
+		return t.payloads[int64(p)]
+	}
+
+	return nil
+}
+
+// Delete deletes a key. Runs in O(lg lg u) time.
+func (t *VEBTree) Delete(key int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.payloads[key]; ok {
+		t.root.delete(uint64(key))
+		delete(t.payloads, key)
+	}
+}
+
+func (t *VEBTree) min() interface{} {
+	if t.root.empty {
+		return nil
+	}
+
+	return t.payloads[int64(t.root.min)]
+}
+
+// keys calls fn for all keys in ascending order.
+func (t *VEBTree) keys(fn func(key int64)) {
+	if t.root.empty {
+		return
+	}
+
+	for k, ok := t.root.min, true; ok; k, ok = t.root.successor(k) {
+		fn(int64(k))
+	}
+}
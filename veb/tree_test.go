@@ -0,0 +1,220 @@
+package veb
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/obitech/go-trees/bst"
+)
+
+// bstAPI is the method set of bst.BSTree, which VEBTree shares.
+type bstAPI interface {
+	Root() interface{}
+	Height() int
+	Upsert(key int64, payload interface{})
+	Search(key int64) interface{}
+	Min() interface{}
+	Max() interface{}
+	Successor(key int64) interface{}
+	Delete(key int64)
+}
+
+var (
+	_ bstAPI = (*bst.BSTree)(nil)
+	_ bstAPI = (*VEBTree)(nil)
+)
+
+// model is a sorted slice of keys which the tree is checked against.
+type model []int64
+
+func (m model) successor(key int64) interface{} {
+	i := sort.Search(len(m), func(i int) bool { return m[i] > key })
+	if i == len(m) {
+		return nil
+	}
+
+	return m[i]
+}
+
+func (m model) predecessor(key int64) interface{} {
+	i := sort.Search(len(m), func(i int) bool { return m[i] >= key })
+	if i == 0 {
+		return nil
+	}
+
+	return m[i-1]
+}
+
+func TestVEBTree(t *testing.T) {
+	tree := NewVEBTree(16)
+
+	assert.Nil(t, tree.Min())
+	assert.Nil(t, tree.Max())
+	assert.Nil(t, tree.Successor(0))
+	assert.Nil(t, tree.Predecessor(15))
+	assert.Nil(t, tree.Root())
+	assert.Equal(t, -1, tree.Height())
+	assert.Equal(t, int64(16), tree.Universe())
+
+	for _, k := range []int64{2, 3, 4, 5, 7, 14, 15} {
+		tree.Upsert(k, k)
+	}
+
+	tree.Upsert(7, "seven")
+
+	assert.Equal(t, 7, tree.Len())
+	assert.Equal(t, int64(2), tree.Root())
+	assert.Equal(t, 0, tree.Height())
+	assert.Equal(t, int64(2), tree.Min())
+	assert.Equal(t, int64(15), tree.Max())
+	assert.Equal(t, "seven", tree.Search(7))
+	assert.Nil(t, tree.Search(8))
+	assert.Nil(t, tree.Search(-1))
+	assert.Equal(t, "seven", tree.Successor(5))
+	assert.Equal(t, int64(14), tree.Successor(8))
+	assert.Equal(t, int64(2), tree.Successor(-10))
+	assert.Nil(t, tree.Successor(15))
+	assert.Equal(t, "seven", tree.Predecessor(14))
+	assert.Equal(t, int64(15), tree.Predecessor(100))
+	assert.Nil(t, tree.Predecessor(2))
+
+	tree.Delete(2)
+	tree.Delete(15)
+	tree.Delete(8)
+
+	assert.Equal(t, 5, tree.Len())
+	assert.Equal(t, int64(3), tree.Min())
+	assert.Equal(t, int64(14), tree.Max())
+
+	assert.True(t, errors.Is(tree.TryUpsert(16, nil), ErrOutOfUniverse))
+	assert.True(t, errors.Is(tree.TryUpsert(-1, nil), ErrOutOfUniverse))
+	assert.NoError(t, tree.TryUpsert(0, "zero"))
+	assert.Panics(t, func() { tree.Upsert(16, nil) })
+	assert.Equal(t, 6, tree.Len())
+	assert.Equal(t, "zero", tree.Root())
+	assert.Nil(t, tree.Successor(14))
+	assert.Panics(t, func() { NewVEBTree(0) })
+}
+
+func TestVEBTree_Height(t *testing.T) {
+	tree := NewVEBTree(1 << 16)
+
+	// The root only stores its minimum, all other keys live in clusters of 8
+	// bits, whose clusters of 4 bits are bitsets.
+	tt := []struct {
+		key  int64
+		want int
+	}{
+		{key: 1 << 8, want: 0},
+		{key: 1<<8 + 1, want: 1},
+		{key: 1<<8 + 1<<4, want: 2},
+		{key: 1 << 12, want: 2},
+	}
+
+	for _, tc := range tt {
+		tree.Upsert(tc.key, nil)
+		assert.Equal(t, tc.want, tree.Height(), "after upserting %d", tc.key)
+	}
+
+	tree.Delete(1<<8 + 1<<4)
+	assert.Equal(t, 1, tree.Height())
+}
+
+func TestVEBTree_random(t *testing.T) {
+	tt := []struct {
+		name     string
+		universe int64
+	}{
+		{name: "single key", universe: 1},
+		{name: "two keys", universe: 2},
+		{name: "largest bitset", universe: 1 << maxLeafBits},
+		{name: "smallest clusters", universe: 1<<maxLeafBits + 1},
+		{name: "odd bits", universe: 1 << 7},
+		{name: "not a power of two", universe: 1000},
+		{name: "ports", universe: 1 << 16},
+		{name: "sparse", universe: 1 << 40},
+		{name: "max", universe: math.MaxInt64},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				rng  = rand.New(rand.NewSource(tc.universe))
+				tree = NewVEBTree(tc.universe, WithoutLocking())
+				keys = make(map[int64]bool)
+				// Draw keys from a small range near the top to provoke
+				// collisions in sparse universes.
+				random = func() int64 {
+					if tc.universe <= 2000 {
+						return rng.Int63n(tc.universe)
+					}
+
+					return tc.universe - 1 - rng.Int63n(2000)
+				}
+			)
+
+			for i := 0; i < 3000; i++ {
+				k := random()
+
+				if rng.Intn(3) == 0 {
+					tree.Delete(k)
+					delete(keys, k)
+				} else {
+					tree.Upsert(k, k)
+					keys[k] = true
+				}
+
+				if i%100 != 0 {
+					continue
+				}
+
+				m := make(model, 0, len(keys))
+				for k := range keys {
+					m = append(m, k)
+				}
+
+				sort.Slice(m, func(i, j int) bool { return m[i] < m[j] })
+
+				require.Equal(t, len(m), tree.Len())
+
+				if len(m) > 0 {
+					require.Equal(t, m[0], tree.Min())
+					require.Equal(t, m[len(m)-1], tree.Max())
+				}
+
+				for j := 0; j < 50; j++ {
+					q := random()
+
+					require.Equal(t, m.successor(q), tree.Successor(q), "successor of %d", q)
+					require.Equal(t, m.predecessor(q), tree.Predecessor(q), "predecessor of %d", q)
+
+					if keys[q] {
+						require.Equal(t, q, tree.Search(q))
+					} else {
+						require.Nil(t, tree.Search(q))
+					}
+				}
+			}
+
+			for k := range keys {
+				tree.Delete(k)
+			}
+
+			assert.True(t, tree.root.empty)
+			assert.Empty(t, tree.root.sparse)
+			assert.True(t, tree.root.summary == nil || tree.root.summary.empty)
+		})
+	}
+}
+
+func TestNewVEBTree_WithoutLocking(t *testing.T) {
+	tree := NewVEBTree(10, WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+}
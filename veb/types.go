@@ -0,0 +1,40 @@
+package veb
+
+import (
+	"errors"
+
+	"github.com/obitech/go-trees/codec"
+	"github.com/obitech/go-trees/internal/lock"
+)
+
+// ErrOutOfUniverse is returned by TryUpsert for keys outside of the universe
+// of the tree.
+var ErrOutOfUniverse = errors.New("veb: key out of universe")
+
+// VEBTree represents a van Emde Boas tree over a bounded universe of keys,
+// with a lock to protect concurrent access.
+type VEBTree struct {
+	lock     lock.RWMutex
+	root     *node
+	universe int64
+	payloads map[int64]interface{}
+	codec    codec.Codec
+}
+
+// node holds a set of keys with the given number of bits. The minimum is only
+// stored in the node itself, all other keys are split into their high bits,
+// which are kept in the summary, and their low bits, which are kept in the
+// cluster of their high bits. Nodes of at most maxLeafBits bits keep all of
+// their keys in set instead. Clusters are allocated when they receive their
+// first key. They're indexed by a slice, unless there are more than
+// 1<<maxDenseBits of them, in which case a map keeps memory proportional to
+// the number of keys.
+type node struct {
+	bits     uint
+	empty    bool
+	min, max uint64
+	set      uint64
+	summary  *node
+	dense    []*node
+	sparse   map[uint64]*node
+}
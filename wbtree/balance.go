@@ -0,0 +1,62 @@
+package wbtree
+
+func weight(n *node) int {
+	return size(n) + 1
+}
+
+// balanced returns true if the weight of b doesn't exceed delta times the
+// weight of a.
+func balanced(a, b *node) bool {
+	return delta*weight(a) >= weight(b)
+}
+
+// single returns true if a subtree with the inner child a and the outer child
+// b is fixed by a single rotation.
+func single(a, b *node) bool {
+	return weight(a) < gamma*weight(b)
+}
+
+// balance restores the weight balance of n, whose subtrees were balanced
+// before one of them grew or shrank by one node, and returns the new root of
+// the subtree. The size of n is updated as well.
+func balance(n *node) *node {
+	switch {
+	case !balanced(n.left, n.right):
+		if !single(n.right.left, n.right.right) {
+			n.right = rotateRight(n.right)
+		}
+
+		return rotateLeft(n)
+	case !balanced(n.right, n.left):
+		if !single(n.left.right, n.left.left) {
+			n.left = rotateLeft(n.left)
+		}
+
+		return rotateRight(n)
+	default:
+		n.update()
+		return n
+	}
+}
+
+func rotateLeft(x *node) *node {
+	y := x.right
+	x.right = y.left
+	y.left = x
+
+	x.update()
+	y.update()
+
+	return y
+}
+
+func rotateRight(x *node) *node {
+	y := x.left
+	x.left = y.right
+	y.right = x
+
+	x.update()
+	y.update()
+
+	return y
+}
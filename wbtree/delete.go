@@ -0,0 +1,49 @@
+package wbtree
+
+// Delete deletes the node with the given key.
+func (t *Tree) Delete(key Key) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if search(t.root, key) != nil {
+		t.root = remove(t.root, key)
+	}
+}
+
+// remove deletes key, which has to exist, from the subtree rooted at n and
+// returns its new root.
+func remove(n *node, key Key) *node {
+	switch {
+	case key.Less(n.key):
+		n.left = remove(n.left, key)
+	case n.key.Less(key):
+		n.right = remove(n.right, key)
+	case n.left == nil:
+		return n.right
+	case n.right == nil:
+		return n.left
+	default:
+		// Replace n by its successor.
+		var succ *node
+
+		n.right, succ = removeMin(n.right)
+		succ.left, succ.right = n.left, n.right
+		n = succ
+	}
+
+	return balance(n)
+}
+
+// removeMin unlinks the lowest node of the subtree rooted at n and returns the
+// new root of the subtree along with the unlinked node.
+func removeMin(n *node) (*node, *node) {
+	if n.left == nil {
+		return n.right, n
+	}
+
+	var min *node
+
+	n.left, min = removeMin(n.left)
+
+	return balance(n), min
+}
@@ -0,0 +1,30 @@
+package wbtree
+
+// Upsert updates an existing payload, or inserts a new one with the given key.
+func (t *Tree) Upsert(key Key, payload interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if n := search(t.root, key); n != nil {
+		n.payload = payload
+		return
+	}
+
+	t.root = insert(t.root, &node{key: key, payload: payload, size: 1})
+}
+
+// insert adds z, whose key doesn't exist yet, to the subtree rooted at n and
+// returns its new root.
+func insert(n, z *node) *node {
+	if n == nil {
+		return z
+	}
+
+	if z.key.Less(n.key) {
+		n.left = insert(n.left, z)
+	} else {
+		n.right = insert(n.right, z)
+	}
+
+	return balance(n)
+}
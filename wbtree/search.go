@@ -0,0 +1,32 @@
+package wbtree
+
+// InOrder returns an ordered list of all entries.
+func (t *Tree) InOrder() []Result {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	res := make([]Result, 0, t.root.size)
+
+	inorder(t.root, &res)
+
+	return res
+}
+
+func inorder(n *node, res *[]Result) {
+	if n == nil {
+		return
+	}
+
+	inorder(n.left, res)
+
+	*res = append(*res, Result{
+		Key:     n.key,
+		Payload: n.payload,
+	})
+
+	inorder(n.right, res)
+}
@@ -0,0 +1,227 @@
+// Package wbtree implements a weight-balanced tree, a binary search tree which
+// balances itself by the sizes of its subtrees: no subtree may hold more than
+// delta times as many nodes as its sibling. As sizes are maintained anyway,
+// finding the rank of a key and the key of a rank come for free. All
+// operations run in O(lg n) time.
+package wbtree
+
+// delta and gamma are the balance parameters of the tree. A node is balanced
+// if the weight of neither subtree exceeds delta times the weight of the
+// other, where the weight is the size plus one. gamma decides between single
+// and double rotations. (3, 2) is the only integer pair which is known to
+// keep the tree balanced for both insertions and deletions.
+const (
+	delta = 3
+	gamma = 2
+)
+
+// NewWBTree returns a new weight-balanced tree. Unless WithoutLocking is
+// passed, all operations on the tree are safe to be accessed concurrently.
+func NewWBTree(opts ...Option) *Tree {
+	t := &Tree{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Len returns the number of keys in the tree. Runs in O(1) time.
+func (t *Tree) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return size(t.root)
+}
+
+// Root returns the payload of the root node of the tree.
+func (t *Tree) Root() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root.payload
+}
+
+// Height returns the height (max depth) of the tree. Returns -1 if the tree
+// has no nodes. A (rooted) tree with only a single node has a height of zero.
+func (t *Tree) Height() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return height(t.root)
+}
+
+// Min returns the payload of the lowest key, or nil.
+func (t *Tree) Min() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return min(t.root).payload
+}
+
+// Max returns the payload of the highest key, or nil.
+func (t *Tree) Max() interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.root == nil {
+		return nil
+	}
+
+	return max(t.root).payload
+}
+
+// Search returns the payload for a given key, or nil.
+func (t *Tree) Search(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if n := search(t.root, key); n != nil {
+		return n.payload
+	}
+
+	return nil
+}
+
+// Successor returns the payload of the next highest neighbour (key-wise) of the
+// passed key.
+func (t *Tree) Successor(key Key) interface{} {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	// The lowest ancestor whose left subtree holds key.
+	var candidate *node
+
+	for n := t.root; n != nil; {
+		switch {
+		case key.Less(n.key):
+			candidate = n
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		case n.right != nil:
+			return min(n.right).payload
+		case candidate != nil:
+			return candidate.payload
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Rank returns the number of keys less than the passed key, which is the
+// zero-based position of the key if it exists, and whether it exists.
+func (t *Tree) Rank(key Key) (int, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var rank int
+
+	for n := t.root; n != nil; {
+		switch {
+		case key.Less(n.key):
+			n = n.left
+		case n.key.Less(key):
+			rank += size(n.left) + 1
+			n = n.right
+		default:
+			return rank + size(n.left), true
+		}
+	}
+
+	return rank, false
+}
+
+// Select returns the entry with the given zero-based rank. Returns false if
+// the rank is out of range.
+func (t *Tree) Select(rank int) (Result, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if rank < 0 || rank >= size(t.root) {
+		return Result{}, false
+	}
+
+	n := t.root
+
+	for {
+		switch l := size(n.left); {
+		case rank < l:
+			n = n.left
+		case rank > l:
+			rank -= l + 1
+			n = n.right
+		default:
+			return Result{Key: n.key, Payload: n.payload}, true
+		}
+	}
+}
+
+func search(n *node, key Key) *node {
+	for n != nil {
+		switch {
+		case key.Less(n.key):
+			n = n.left
+		case n.key.Less(key):
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+func min(n *node) *node {
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n
+}
+
+func max(n *node) *node {
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+
+	l, r := height(n.left), height(n.right)
+
+	if l > r {
+		return l + 1
+	}
+
+	return r + 1
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// update recomputes the size of n from its children.
+func (n *node) update() {
+	n.size = 1 + size(n.left) + size(n.right)
+}
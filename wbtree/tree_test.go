@@ -0,0 +1,229 @@
+package wbtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type myInt int
+
+func (i myInt) Less(v Key) bool {
+	return i < v.(myInt)
+}
+
+// verify checks the subtree sizes and the weight balance of every node.
+func verify(t *testing.T, tree *Tree) {
+	var walk func(n *node) int
+
+	walk = func(n *node) int {
+		if n == nil {
+			return 0
+		}
+
+		size := 1 + walk(n.left) + walk(n.right)
+
+		require.Equal(t, size, n.size, "size of %v", n.key)
+		require.True(t, balanced(n.left, n.right), "%v is right heavy", n.key)
+		require.True(t, balanced(n.right, n.left), "%v is left heavy", n.key)
+
+		return size
+	}
+
+	walk(tree.root)
+
+	if n := tree.Len(); n > 0 {
+		require.LessOrEqual(t, float64(tree.Height()), math.Log(float64(n+1))/math.Log(4.0/3))
+	}
+}
+
+// perfect returns a perfectly balanced subtree of the keys in [lo, hi).
+func perfect(lo, hi int) *node {
+	if lo >= hi {
+		return nil
+	}
+
+	mid := (lo + hi) / 2
+	n := &node{key: myInt(mid), left: perfect(lo, mid), right: perfect(mid+1, hi)}
+	n.update()
+
+	return n
+}
+
+// mirror swaps the children of all nodes and negates their keys, so the
+// result is a search tree again.
+func mirror(n *node) *node {
+	if n == nil {
+		return nil
+	}
+
+	n.key = -n.key.(myInt)
+	n.left, n.right = mirror(n.right), mirror(n.left)
+
+	return n
+}
+
+func TestBalance(t *testing.T) {
+	// The root has a left subtree of size left and a right child whose inner
+	// (left) and outer (right) subtrees have the given sizes. Keys are
+	// numbered in order, starting from zero.
+	tt := []struct {
+		name               string
+		left, inner, outer int
+		want               string
+	}{
+		{name: "weight at delta times sibling is balanced", left: 1, inner: 2, outer: 2, want: "none"},
+		{name: "weight above delta times sibling rotates", left: 1, inner: 3, outer: 2, want: "single"},
+		{name: "empty sibling at delta is balanced", left: 0, inner: 0, outer: 1, want: "none"},
+		{name: "empty sibling above delta rotates", left: 0, inner: 1, outer: 1, want: "single"},
+		{name: "inner weight below gamma times outer is single", left: 0, inner: 2, outer: 1, want: "single"},
+		{name: "inner weight at gamma times outer is double", left: 0, inner: 3, outer: 1, want: "double"},
+		{name: "inner weight above gamma times outer is double", left: 0, inner: 2, outer: 0, want: "double"},
+	}
+
+	for _, tc := range tt {
+		for _, mirrored := range []bool{false, true} {
+			name := tc.name
+			if mirrored {
+				name += " mirrored"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				var (
+					rootKey  = tc.left
+					innerLo  = rootKey + 1
+					childKey = innerLo + tc.inner
+					inner    = perfect(innerLo, childKey)
+					child    = &node{key: myInt(childKey), left: inner, right: perfect(childKey+1, childKey+1+tc.outer)}
+					root     = &node{key: myInt(rootKey), left: perfect(0, tc.left), right: child}
+				)
+
+				child.update()
+				root.update()
+
+				want := map[string]myInt{
+					"none":   myInt(rootKey),
+					"single": myInt(childKey),
+				}
+				if inner != nil {
+					want["double"] = inner.key.(myInt)
+				}
+
+				if mirrored {
+					root = mirror(root)
+
+					for k, v := range want {
+						want[k] = -v
+					}
+				}
+
+				tree := &Tree{root: balance(root)}
+
+				assert.Equal(t, want[tc.want], tree.root.key)
+				verify(t, tree)
+			})
+		}
+	}
+}
+
+func TestTree_RankSelect(t *testing.T) {
+	tree := NewWBTree()
+
+	_, ok := tree.Select(0)
+	assert.False(t, ok)
+
+	for i := 0; i < 50; i++ {
+		tree.Upsert(myInt(2*i), i)
+	}
+
+	tt := []struct {
+		name   string
+		key    int
+		rank   int
+		exists bool
+	}{
+		{name: "lowest key", key: 0, rank: 0, exists: true},
+		{name: "highest key", key: 98, rank: 49, exists: true},
+		{name: "inner key", key: 42, rank: 21, exists: true},
+		{name: "missing inner key", key: 43, rank: 22, exists: false},
+		{name: "below lowest key", key: -1, rank: 0, exists: false},
+		{name: "above highest key", key: 99, rank: 50, exists: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rank, exists := tree.Rank(myInt(tc.key))
+			assert.Equal(t, tc.rank, rank)
+			assert.Equal(t, tc.exists, exists)
+
+			if !tc.exists {
+				return
+			}
+
+			r, ok := tree.Select(tc.rank)
+			require.True(t, ok)
+			assert.Equal(t, myInt(tc.key), r.Key)
+			assert.Equal(t, tc.key/2, r.Payload)
+		})
+	}
+
+	for _, rank := range []int{-1, 50} {
+		_, ok := tree.Select(rank)
+		assert.False(t, ok, "rank %d", rank)
+	}
+}
+
+func TestTree_random(t *testing.T) {
+	var (
+		rng  = rand.New(rand.NewSource(1))
+		tree = NewWBTree(WithoutLocking())
+		want = make(map[int]int)
+	)
+
+	for i := 0; i < 5000; i++ {
+		k := rng.Intn(500)
+
+		if rng.Intn(3) == 0 {
+			tree.Delete(myInt(k))
+			delete(want, k)
+		} else {
+			tree.Upsert(myInt(k), i)
+			want[k] = i
+		}
+
+		if i%100 == 0 {
+			verify(t, tree)
+		}
+	}
+
+	verify(t, tree)
+	require.Equal(t, len(want), tree.Len())
+
+	sorted := make([]int, 0, len(want))
+	for k, v := range want {
+		require.Equal(t, v, tree.Search(myInt(k)))
+		sorted = append(sorted, k)
+	}
+
+	sort.Ints(sorted)
+
+	for i, k := range sorted {
+		rank, ok := tree.Rank(myInt(k))
+		require.True(t, ok)
+		require.Equal(t, i, rank)
+
+		r, ok := tree.Select(i)
+		require.True(t, ok)
+		require.Equal(t, myInt(k), r.Key)
+	}
+}
+
+func TestNewWBTree_WithoutLocking(t *testing.T) {
+	tree := NewWBTree(WithoutLocking())
+
+	assert.True(t, tree.lock.Disabled())
+}
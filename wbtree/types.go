@@ -0,0 +1,30 @@
+package wbtree
+
+import (
+	"github.com/obitech/go-trees/internal/lock"
+	"github.com/obitech/go-trees/redblack"
+)
+
+// Key is the interface keys of the tree have to implement. It is shared with
+// package redblack, so the same key types work with both trees.
+type Key = redblack.Key
+
+// Result is a search result when looking up a Key in the tree.
+type Result = redblack.Result
+
+// Tree represents a weight-balanced tree with a root node and a lock to
+// protect concurrent access.
+type Tree struct {
+	lock lock.RWMutex
+	root *node
+}
+
+// node is balanced by its size, the number of nodes of the subtree rooted at
+// it, which is all the balancing information the tree needs.
+type node struct {
+	key     Key
+	payload interface{}
+	size    int
+	left    *node
+	right   *node
+}